@@ -0,0 +1,68 @@
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+	log "github.com/shyunku-libraries/go-logger"
+	"net/http"
+	"team.gg-server/controllers/middlewares"
+	"team.gg-server/service"
+	"team.gg-server/util"
+)
+
+func UseAdminRouter(r *gin.Engine) {
+	g := r.Group("/admin")
+	g.Use(middlewares.AuthMiddleware, middlewares.AdminMiddleware)
+
+	g.GET("/workers", GetWorkers)
+	g.POST("/workers/pause", PauseWorker)
+	g.POST("/workers/resume", ResumeWorker)
+	g.POST("/workers/collect", CollectWorker)
+}
+
+func GetWorkers(c *gin.Context) {
+	c.JSON(http.StatusOK, GetWorkersResponseDto(service.GetWorkerStates()))
+}
+
+func PauseWorker(c *gin.Context) {
+	worker, ok := bindWorker(c)
+	if !ok {
+		return
+	}
+	worker.Pause()
+	c.JSON(http.StatusOK, worker.State())
+}
+
+func ResumeWorker(c *gin.Context) {
+	worker, ok := bindWorker(c)
+	if !ok {
+		return
+	}
+	worker.Resume()
+	c.JSON(http.StatusOK, worker.State())
+}
+
+// CollectWorker triggers immediate run of worker (even if paused)
+func CollectWorker(c *gin.Context) {
+	worker, ok := bindWorker(c)
+	if !ok {
+		return
+	}
+	worker.Trigger()
+	c.JSON(http.StatusOK, worker.State())
+}
+
+func bindWorker(c *gin.Context) (*service.Worker, bool) {
+	var req WorkerActionRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return nil, false
+	}
+
+	worker, exists := service.GetWorker(req.Name)
+	if !exists {
+		util.AbortWithStrJson(c, http.StatusNotFound, "worker not found")
+		return nil, false
+	}
+	return worker, true
+}
@@ -0,0 +1,9 @@
+package admin
+
+import "team.gg-server/service"
+
+type GetWorkersResponseDto []service.WorkerState
+
+type WorkerActionRequestDto struct {
+	Name string `json:"name" binding:"required"`
+}
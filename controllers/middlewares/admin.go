@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	log "github.com/shyunku-libraries/go-logger"
+	"net/http"
+	"os"
+	"strings"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/util"
+)
+
+// AdminMiddleware allows only users listed in ADMIN_USER_IDS (comma separated), should be used after AuthMiddleware
+func AdminMiddleware(c *gin.Context) {
+	uid := c.GetString("uid")
+	if uid == "" {
+		util.AbortWithStrJson(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	user, exists, err := models.GetUserDAO_byUid(db.Root, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !exists || !isAdminUserId(user.UserId) {
+		util.AbortWithStrJson(c, http.StatusForbidden, "forbidden")
+		return
+	}
+
+	c.Next()
+}
+
+func isAdminUserId(userId string) bool {
+	for _, adminUserId := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+		if adminUserId = strings.TrimSpace(adminUserId); adminUserId != "" && adminUserId == userId {
+			return true
+		}
+	}
+	return false
+}
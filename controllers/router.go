@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"sync"
+	"team.gg-server/controllers/admin"
 	"team.gg-server/controllers/middlewares"
 	"team.gg-server/controllers/socket"
 	"team.gg-server/controllers/test"
@@ -19,7 +20,7 @@ import (
 
 var GlobalLogger = log.GetLogger()
 
-func SetupRouter() *gin.Engine {
+func SetupRouter(ctx context.Context, waitGroup *sync.WaitGroup) *gin.Engine {
 	gin.DefaultWriter = GlobalLogger
 	gin.DefaultErrorWriter = GlobalLogger
 
@@ -47,10 +48,11 @@ func SetupRouter() *gin.Engine {
 
 	// platform routes
 	v1.UseV1Router(r)
+	admin.UseAdminRouter(r)
 	if core.DebugMode {
 		test.UseTestRouter(r)
 	}
-	socket.UseSocket(r)
+	socket.UseSocket(ctx, waitGroup, r)
 
 	// 404
 	r.NoRoute(func(c *gin.Context) {
@@ -66,7 +68,7 @@ func ping(c *gin.Context) {
 
 func RunGin(ctx context.Context, waitGroup *sync.WaitGroup) {
 	log.Infof("Starting server on port on %s...", core.AppServerPort)
-	r := SetupRouter()
+	r := SetupRouter(ctx, waitGroup)
 	srv := &http.Server{
 		Addr:    fmt.Sprintf(":%s", core.AppServerPort),
 		Handler: r,
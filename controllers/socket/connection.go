@@ -1,13 +1,17 @@
 package socket
 
 import (
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
 	socketio "github.com/googollee/go-socket.io"
 	"github.com/googollee/go-socket.io/engineio"
 	"github.com/googollee/go-socket.io/engineio/transport"
 	"github.com/googollee/go-socket.io/engineio/transport/websocket"
 	log "github.com/shyunku-libraries/go-logger"
+	"io"
 	"os"
+	"sync"
 	"team.gg-server/controllers/middlewares"
 	"team.gg-server/libs/db"
 	"team.gg-server/models"
@@ -17,9 +21,9 @@ var (
 	SocketIO = NewSocketManager()
 )
 
-func UseSocket(r *gin.Engine) {
+func UseSocket(ctx context.Context, waitGroup *sync.WaitGroup, r *gin.Engine) {
 	g := r.Group("/socket.io")
-	io := socketio.NewServer(&engineio.Options{
+	server := socketio.NewServer(&engineio.Options{
 		Transports: []transport.Transport{
 			//&polling.Transport{
 			//	Client: &http.Client{
@@ -29,22 +33,33 @@ func UseSocket(r *gin.Engine) {
 			&websocket.Transport{},
 		},
 	})
-	SocketIO.Io = io
-	userHandlers(io)
+	SocketIO.Io = server
+	userHandlers(server)
 
 	log.Infof("socket.io server started")
+	waitGroup.Add(1)
 	go func() {
-		err := io.Serve()
-		if err != nil {
+		defer waitGroup.Done()
+		// accept loop returns io.EOF when server is closed
+		if err := server.Serve(); err != nil && !errors.Is(err, io.EOF) {
 			log.Error("socket.io server error: ", err)
 			log.Fatal(err)
 			os.Exit(1)
 		}
+		log.Info("socket.io server stopped.")
+	}()
+
+	go func() {
+		<-ctx.Done()
+		log.Info("socket.io server is shutting down...")
+		if err := server.Close(); err != nil {
+			log.Error(err)
+		}
 	}()
 
 	wrapper := func(c *gin.Context) {
 		c.Request.Header.Del("Origin")
-		io.ServeHTTP(c.Writer, c.Request)
+		server.ServeHTTP(c.Writer, c.Request)
 	}
 
 	g.Use(middlewares.UnsafeAuthMiddleware)
@@ -96,6 +111,63 @@ func userHandlers(io *socketio.Server) {
 	/* ---------------------- custom ---------------------- */
 	io.OnEvent("/", EventJoinCustomConfigRoom, func(s socketio.Conn, configId string) {
 		log.Debugf("Socket event: [%v] %v", s.ID(), configId)
+		permitted, err := canJoinCustomConfigRoom(s, configId)
+		if err != nil {
+			log.Warn(err)
+			return
+		}
+		if !permitted {
+			log.Debugf("Socket [%v] is not permitted to join room of private config: %v", s.ID(), configId)
+			return
+		}
 		s.Join(RoomKey(configId))
 	})
 }
+
+// canJoinCustomConfigRoom checks config is public, or user of socket is member of private config
+func canJoinCustomConfigRoom(s socketio.Conn, configId string) (bool, error) {
+	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(db.Root, configId)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, nil
+	}
+	if !customGameConfigurationDAO.IsPrivate {
+		return true, nil
+	}
+	userSocket, ok := SocketIO.GetUserByConnId(s.ID())
+	if !ok || userSocket.User == nil {
+		return false, nil
+	}
+	if customGameConfigurationDAO.CreatorUid == userSocket.User.Uid {
+		return true, nil
+	}
+	_, isMember, err := models.GetCustomGameMemberDAO(db.Root, configId, userSocket.User.Uid)
+	if err != nil {
+		return false, err
+	}
+	return isMember, nil
+}
+
+// EvictFromCustomConfigRoom makes sockets which can't join room anymore leave it
+// (removed member, or non-member after config became private)
+func EvictFromCustomConfigRoom(configId string) {
+	roomKey := RoomKey(configId)
+	// room is locked while iterating, so sockets leave after it
+	conns := make([]socketio.Conn, 0)
+	SocketIO.Io.ForEach("/", roomKey, func(conn socketio.Conn) {
+		conns = append(conns, conn)
+	})
+	for _, conn := range conns {
+		permitted, err := canJoinCustomConfigRoom(conn, configId)
+		if err != nil {
+			log.Warn(err)
+			continue
+		}
+		if !permitted {
+			log.Debugf("Socket [%v] left room of config: %v", conn.ID(), configId)
+			conn.Leave(roomKey)
+		}
+	}
+}
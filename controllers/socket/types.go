@@ -3,6 +3,7 @@ package socket
 import (
 	socketio "github.com/googollee/go-socket.io"
 	"team.gg-server/models"
+	"time"
 )
 
 // types
@@ -11,7 +12,12 @@ const (
 
 	EventJoinCustomConfigRoom        = "join_custom_config_room"
 	EventCustomConfigOptimizeProcess = "custom_config/optimize_process"
+	EventCustomConfigOptimizeDone    = "custom_config/optimize_done"
 	EventCustomConfigUpdated         = "custom_config/updated"
+	EventCustomConfigMemberUpdated   = "custom_config/member_updated"
+	EventCustomConfigRevisionApplied = "custom_config/revision_applied"
+	EventCustomConfigDraftUpdated    = "custom_config/draft_updated"
+	EventCustomConfigDraftDone       = "custom_config/draft_done"
 )
 
 type UserSocket struct {
@@ -66,6 +72,11 @@ func (sm *Manager) GetUserByUserId(userId string) (UserSocket, bool) {
 	return userSocket, ok
 }
 
+func (sm *Manager) GetUserByConnId(connId string) (UserSocket, bool) {
+	userSocket, ok := sm.sockets[connId]
+	return userSocket, ok
+}
+
 func (sm *Manager) BroadcastToCustomConfigRoom(configId string, event string, data interface{}) {
 	if data == nil {
 		data = map[string]interface{}{}
@@ -124,8 +135,48 @@ const (
 )
 
 type CustomConfigOptimizeProcessData struct {
+	JobId    string  `json:"jobId"`
 	Type     string  `json:"type"`
 	Progress float64 `json:"progress"`
 	Current  int64   `json:"current"`
 	Total    int64   `json:"total"`
 }
+
+type CustomConfigOptimizeDoneData struct {
+	JobId                    string   `json:"jobId"`
+	Status                   string   `json:"status"`
+	Error                    *string  `json:"error"`
+	ConflictingConstraintIds []string `json:"conflictingConstraintIds"`
+}
+
+type CustomConfigUpdatedData struct {
+	Version int `json:"version"` // version of configuration after update
+}
+
+type CustomConfigMemberUpdatedData struct {
+	Uid  string  `json:"uid"`
+	Role *string `json:"role"` // nil if member is removed
+}
+
+type CustomConfigRevisionAppliedData struct {
+	RevisionId string `json:"revisionId"`
+	Operation  string `json:"operation"` // undo, redo or restore
+	ActorUid   string `json:"actorUid"`
+}
+
+type CustomConfigDraftUpdatedData struct {
+	DraftId    string     `json:"draftId"`
+	Turn       int        `json:"turn"`       // index of pick to be made next
+	TurnTeam   int        `json:"turnTeam"`   // team of captain to pick next, 0 if all picked
+	TurnEndsAt *time.Time `json:"turnEndsAt"` // auto pick is made at this time
+	// pick just made, nil on draft start
+	PickedPuuid    *string `json:"pickedPuuid"`
+	PickedPosition *string `json:"pickedPosition"`
+	AutoPicked     bool    `json:"autoPicked"`
+}
+
+type CustomConfigDraftDoneData struct {
+	DraftId string  `json:"draftId"`
+	Status  string  `json:"status"`
+	Error   *string `json:"error"`
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/shyunku-libraries/go-logger"
+	"net/http"
+	"team.gg-server/third_party/riot"
+	"team.gg-server/util"
+)
+
+// RiotErrorStatus maps error of riot api to http status code of ours
+func RiotErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, riot.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, riot.ErrRateLimited), errors.Is(err, riot.ErrForbidden):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, riot.ErrUpstream), errors.Is(err, riot.ErrDecode):
+		return http.StatusBadGateway
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// AbortWithRiotError aborts request with status mapped from riot api error.
+// notFoundMessage is sent when riot says there's no such resource.
+func AbortWithRiotError(c *gin.Context, err error, notFoundMessage string) {
+	status := RiotErrorStatus(err)
+	switch status {
+	case http.StatusNotFound:
+		util.AbortWithStrJson(c, status, notFoundMessage)
+	case http.StatusServiceUnavailable:
+		log.Warn(err)
+		util.AbortWithStrJson(c, status, "riot api is temporarily unavailable")
+	case http.StatusBadGateway:
+		log.Error(err)
+		util.AbortWithStrJson(c, status, "riot api error")
+	default:
+		log.Error(err)
+		util.AbortWithStrJson(c, status, "internal server error")
+	}
+}
+
+// ParsePlatform converts region parameter of request into riot platform routing value.
+// empty region means default platform (kr).
+func ParsePlatform(region string) (string, bool) {
+	if region == "" {
+		return riot.DefaultPlatform, true
+	}
+	platform := riot.NormalizePlatform(region)
+	if !riot.IsSupportedPlatform(platform) {
+		return "", false
+	}
+	return platform, true
+}
@@ -5,9 +5,11 @@ import (
 	log "github.com/shyunku-libraries/go-logger"
 	"net/http"
 	"team.gg-server/controllers/socket"
+	util2 "team.gg-server/controllers/util"
 	"team.gg-server/libs/db"
 	"team.gg-server/models"
 	"team.gg-server/service"
+	"team.gg-server/third_party/riot"
 	"team.gg-server/third_party/riot/api"
 	"team.gg-server/util"
 )
@@ -28,7 +30,13 @@ func getSummonerPuuid(c *gin.Context) {
 		return
 	}
 
-	tagLine := "KR1"
+	platform, ok := util2.ParsePlatform(req.Region)
+	if !ok {
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid region")
+		return
+	}
+
+	tagLine := riot.GetDefaultTagLine(platform)
 	if req.TagLine != nil {
 		tagLine = *req.TagLine
 	}
@@ -39,21 +47,16 @@ func getSummonerPuuid(c *gin.Context) {
 	}
 
 	var puuid string
-	summonerDAO, exists, err := models.GetSummonerDAO_byNameTag(db.Root, req.GameName, tagLine)
+	summonerDAO, exists, err := models.GetSummonerDAO_byNameTag(db.Root, platform, req.GameName, tagLine)
 	if err != nil {
 		log.Error(err)
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
 	if !exists {
-		account, status, err := api.GetAccountByRiotId(req.GameName, tagLine)
+		account, err := api.GetAccountByRiotId(c, platform, req.GameName, tagLine)
 		if err != nil {
-			if status == http.StatusNotFound {
-				util.AbortWithStrJson(c, http.StatusNotFound, "summoner not found")
-			} else {
-				log.Error(err)
-				util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
-			}
+			util2.AbortWithRiotError(c, err, "summoner not found")
 			return
 		}
 		puuid = account.Puuid
@@ -101,7 +104,7 @@ func setSummonerLineFavor(c *gin.Context) {
 		return
 	}
 
-	customGameConfigIdList := make([]string, 0)
+	customGameConfigVersions := make(map[string]int)
 	for _, discordIntegration := range discordIntegrations {
 		candidateDAOs, err := models.GetCustomGameCandidateDAOs_byPuuid(tx, discordIntegration.Puuid)
 		if err != nil {
@@ -133,7 +136,15 @@ func setSummonerLineFavor(c *gin.Context) {
 				return
 			}
 
-			customGameConfigIdList = append(customGameConfigIdList, candidateDAO.CustomGameConfigId)
+			// change from discord is not based on any version of configuration
+			version, err := service.IncreaseCustomGameVersion(tx, candidateDAO.CustomGameConfigId, nil)
+			if err != nil {
+				log.Error(err)
+				_ = tx.Rollback()
+				util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+				return
+			}
+			customGameConfigVersions[candidateDAO.CustomGameConfigId] = version
 		}
 	}
 
@@ -144,8 +155,10 @@ func setSummonerLineFavor(c *gin.Context) {
 		return
 	}
 
-	for _, customGameConfigId := range customGameConfigIdList {
-		socket.SocketIO.BroadcastToCustomConfigRoom(customGameConfigId, socket.EventCustomConfigUpdated, nil)
+	for customGameConfigId, version := range customGameConfigVersions {
+		socket.SocketIO.BroadcastToCustomConfigRoom(customGameConfigId, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+			Version: version,
+		})
 	}
 	c.JSON(http.StatusOK, nil)
 }
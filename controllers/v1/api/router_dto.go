@@ -3,6 +3,7 @@ package api
 type GetSummonerPuuidRequestDto struct {
 	GameName string  `form:"gameName" binding:"required"`
 	TagLine  *string `form:"tagLine" binding:"required"`
+	Region   string  `form:"region"`
 }
 
 type SetSummonerLineFavorRequestDto struct {
@@ -14,6 +14,7 @@ import (
 	"team.gg-server/libs/db"
 	"team.gg-server/models"
 	"team.gg-server/service"
+	"team.gg-server/third_party/riot"
 	"team.gg-server/util"
 )
 
@@ -262,9 +263,8 @@ func RsoLogin(c *gin.Context) {
 	}
 	if !exists {
 		// fetch summoner info
-		if _, _, err = service.RenewSummonerInfoByPuuid(db.Root, puuid); err != nil {
-			log.Error(err)
-			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		if _, err = service.RenewSummonerInfoByPuuid(c, db.Root, riot.DefaultPlatform, puuid); err != nil {
+			util2.AbortWithRiotError(c, err, "summoner not found")
 			return
 		}
 	}
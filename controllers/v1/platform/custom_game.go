@@ -1,6 +1,7 @@
 package platform
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -9,6 +10,7 @@ import (
 	"net/http"
 	"sort"
 	"team.gg-server/controllers/socket"
+	util2 "team.gg-server/controllers/util"
 	"team.gg-server/libs/db"
 	"team.gg-server/models"
 	"team.gg-server/service"
@@ -30,6 +32,7 @@ func UseCustomGameRouter(r *gin.RouterGroup) {
 
 	g.PUT("/candidate", AddCandidateToCustomGameConfiguration)
 	g.DELETE("/candidate", DeleteCandidateFromCustomGameConfiguration)
+	g.GET("/candidate/favor-position/suggest", SuggestCustomGameCandidatePositionFavor)
 
 	g.POST("/arrange", ArrangeCustomGameParticipant)
 	g.POST("/unarrange", UnarrangeCustomGameParticipant)
@@ -37,7 +40,44 @@ func UseCustomGameRouter(r *gin.RouterGroup) {
 	g.POST("/custom-tier-rank", SetCustomGameCandidateCustomTierRank)
 	g.POST("/custom-color-label", SetCustomGameCandidateCustomColorLabel)
 	g.DELETE("/custom-color-label", DeleteCustomGameCandidateCustomColorLabel)
+	g.POST("/must-play", SetCustomGameCandidateMustPlay)
+	g.PUT("/constraint", AddCustomGameConstraint)
+	g.DELETE("/constraint", DeleteCustomGameConstraint)
+	g.POST("/finish-round", FinishCustomGameRound)
 	g.POST("/optimize", OptimizeCustomGameConfiguration)
+	g.GET("/optimize", GetCustomGameOptimizeJob)
+	g.POST("/optimize/cancel", CancelCustomGameOptimizeJob)
+	g.POST("/fairness-model", SetCustomGameFairnessModel)
+
+	g.POST("/draft", StartCustomGameDraft)
+	g.GET("/draft", GetCustomGameDraft)
+	g.POST("/draft/pick", PickCustomGameDraft)
+	g.POST("/draft/cancel", CancelCustomGameDraft)
+
+	g.POST("/result", RecordCustomGameResult)
+	g.POST("/result/import", ImportCustomGameResult)
+	g.GET("/results", GetCustomGameResults)
+	g.DELETE("/result", DeleteCustomGameResult)
+	g.GET("/ratings", GetCustomGameRatings)
+
+	g.GET("/members", GetCustomGameMembers)
+	g.POST("/member/role", SetCustomGameMemberRole)
+	g.DELETE("/member", DeleteCustomGameMember)
+	g.POST("/invite", CreateCustomGameInvite)
+	g.GET("/invites", GetCustomGameInvites)
+	g.DELETE("/invite", DeleteCustomGameInvite)
+	g.POST("/invite/accept", AcceptCustomGameInvite)
+
+	g.POST("/privacy", SetCustomGameConfigPrivacy)
+	g.POST("/share", CreateCustomGameShareToken)
+	g.GET("/shares", GetCustomGameShareTokens)
+	g.DELETE("/share", DeleteCustomGameShareToken)
+	g.GET("/shared", GetCustomGameSharedView)
+
+	g.GET("/revisions", GetCustomGameRevisions)
+	g.POST("/revision/undo", UndoCustomGameRevision)
+	g.POST("/revision/redo", RedoCustomGameRevision)
+	g.POST("/revision/restore", RestoreCustomGameRevision)
 
 	g.POST("/arrange-all", SelectMaxCandidates)
 	g.POST("/unarrange-all", UnarrangeAllParticipants)
@@ -72,6 +112,20 @@ func GetCustomGameConfiguration(c *gin.Context) {
 		return
 	}
 
+	uid := c.GetString("uid")
+
+	// check if user can view custom game
+	permitted, err := service.CheckViewPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not member of private custom game")
+		return
+	}
+
 	resp, err := service.GetCustomGameConfigurationVO(req.Id)
 	if err != nil {
 		log.Error(err)
@@ -143,6 +197,7 @@ func CreateCustomGameConfiguration(c *gin.Context) {
 		MidInfluenceWeight:     types.WeightMidInfluence,
 		AdcInfluenceWeight:     types.WeightAdcInfluence,
 		SupportInfluenceWeight: types.WeightSupportInfluence,
+		FairnessModel:          types.CustomGameFairnessModelDefault,
 	}
 	if err := newCustomGameConfigurationDAO.Upsert(db.Root); err != nil {
 		log.Error(err)
@@ -188,8 +243,26 @@ func GetCustomConfigurationBalance(c *gin.Context) {
 		return
 	}
 
-	resp, err := service.GetCustomGameConfigurationBalanceVO(req.Id)
+	uid := c.GetString("uid")
+
+	// check if user can view custom game
+	permitted, err := service.CheckViewPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not member of private custom game")
+		return
+	}
+
+	resp, err := service.GetCustomGameBalanceDetailVO(db.Root, req.Id)
 	if err != nil {
+		if errors.Is(err, service.ErrCustomGameConfigurationNotExists) {
+			util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
+			return
+		}
 		log.Error(err)
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
@@ -208,24 +281,26 @@ func AddCandidateToCustomGameConfiguration(c *gin.Context) {
 
 	uid := c.GetString("uid")
 
-	// check if user is creator of custom game
-	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(db.Root, req.CustomGameConfigId)
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
 	if err != nil {
 		log.Error(err)
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
-	if !exists {
-		util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
 		return
 	}
-	if customGameConfigurationDAO.CreatorUid != uid {
-		util.AbortWithStrJson(c, http.StatusForbidden, "user is not creator of custom game")
+
+	platform, ok := util2.ParsePlatform(req.Region)
+	if !ok {
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid region")
 		return
 	}
 
 	// get summoner
-	summonerDAO, exists, err := models.GetSummonerDAO_byNameTag(db.Root, req.Name, req.TagLine)
+	summonerDAO, exists, err := models.GetSummonerDAO_byNameTag(db.Root, platform, req.Name, req.TagLine)
 	if err != nil {
 		log.Error(err)
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
@@ -240,21 +315,16 @@ func AddCandidateToCustomGameConfiguration(c *gin.Context) {
 			return
 		}
 
-		account, status, err := api.GetAccountByRiotId(req.Name, req.TagLine)
+		account, err := api.GetAccountByRiotId(c, platform, req.Name, req.TagLine)
 		if err != nil {
-			if status == http.StatusNotFound {
-				util.AbortWithStrJson(c, http.StatusNotFound, "invalid game name")
-				return
-			}
-			log.Error(err)
-			util.AbortWithStrJson(c, http.StatusBadRequest, "internal server error")
+			_ = tx.Rollback()
+			util2.AbortWithRiotError(c, err, "invalid game name")
 			return
 		}
 
-		if err := service.RenewSummonerTotal(tx, account.Puuid); err != nil {
-			log.Error(err)
+		if err := service.RenewSummonerTotal(c, tx, platform, account.Puuid); err != nil {
 			_ = tx.Rollback()
-			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+			util2.AbortWithRiotError(c, err, "summoner not found")
 			return
 		}
 
@@ -266,7 +336,7 @@ func AddCandidateToCustomGameConfiguration(c *gin.Context) {
 		}
 
 		// retry
-		summonerDAO, exists, err = models.GetSummonerDAO_byNameTag(db.Root, req.Name, req.TagLine)
+		summonerDAO, exists, err = models.GetSummonerDAO_byNameTag(db.Root, platform, req.Name, req.TagLine)
 		if err != nil {
 			log.Error(err)
 			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
@@ -306,8 +376,37 @@ func AddCandidateToCustomGameConfiguration(c *gin.Context) {
 		FlavorAdc:          0,
 		FlavorSupport:      0,
 	}
-	if err := newCandidateDAO.Upsert(db.Root); err != nil {
+	// no preference yet, infer from recent matches
+	if _, err := service.ApplyCustomGamePositionFavorSuggestion(&newCandidateDAO); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	tx, err := db.Root.BeginTxx(c, nil)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.CustomGameConfigId, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	if err := newCandidateDAO.Upsert(tx); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
 		log.Error(err)
+		_ = tx.Rollback()
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
@@ -319,10 +418,67 @@ func AddCandidateToCustomGameConfiguration(c *gin.Context) {
 		return
 	}
 
-	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, nil)
+	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
 	c.JSON(http.StatusOK, candidateVO)
 }
 
+// SuggestCustomGameCandidatePositionFavor suggests position favors of candidates from their recent matches, without applying them
+func SuggestCustomGameCandidatePositionFavor(c *gin.Context) {
+	var req SuggestCustomGameCandidatePositionFavorRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user can view custom game
+	permitted, err := service.CheckViewPermissionForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not member of private custom game")
+		return
+	}
+
+	if req.Puuid == "" {
+		suggestionVOs, err := service.GetCustomGamePositionFavorSuggestionVOs(db.Root, req.CustomGameConfigId)
+		if err != nil {
+			log.Error(err)
+			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		c.JSON(http.StatusOK, SuggestCustomGameCandidatePositionFavorResponseDto(suggestionVOs))
+		return
+	}
+
+	// check if candidate exists
+	_, exists, err := models.GetCustomGameCandidateDAO_byPuuid(db.Root, req.CustomGameConfigId, req.Puuid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !exists {
+		util.AbortWithStrJson(c, http.StatusNotFound, "candidate not found")
+		return
+	}
+
+	suggestionVO, err := service.GetCustomGamePositionFavorSuggestionVO(req.Puuid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	c.JSON(http.StatusOK, SuggestCustomGameCandidatePositionFavorResponseDto{*suggestionVO})
+}
+
 func DeleteCandidateFromCustomGameConfiguration(c *gin.Context) {
 	var req DeleteCandidateFromCustomGameRequestDto
 	if err := c.ShouldBindQuery(&req); err != nil {
@@ -333,30 +489,58 @@ func DeleteCandidateFromCustomGameConfiguration(c *gin.Context) {
 
 	uid := c.GetString("uid")
 
-	// check if user is creator of custom game
-	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(db.Root, req.CustomGameConfigId)
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
 	if err != nil {
 		log.Error(err)
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
-	if !exists {
-		util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
+
+	tx, err := db.Root.BeginTxx(c, nil)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
-	if customGameConfigurationDAO.CreatorUid != uid {
-		util.AbortWithStrJson(c, http.StatusForbidden, "user is not creator of custom game")
+
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.CustomGameConfigId, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	// delete constraints of candidate
+	if err := models.DeleteCustomGameConstraintDAOs_byPuuid(tx, req.CustomGameConfigId, req.Puuid); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
 	// delete candidate
-	if err := models.DeleteCustomGameCandidateDAO_byPuuid(db.Root, req.CustomGameConfigId, req.Puuid); err != nil {
+	if err := models.DeleteCustomGameCandidateDAO_byPuuid(tx, req.CustomGameConfigId, req.Puuid); err != nil {
 		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	socket.SocketIO.BroadcastToCustomConfigRoom(req.CustomGameConfigId, socket.EventCustomConfigUpdated, nil)
+	socket.SocketIO.BroadcastToCustomConfigRoom(req.CustomGameConfigId, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
 
 	c.JSON(http.StatusOK, nil)
 }
@@ -371,19 +555,15 @@ func ArrangeCustomGameParticipant(c *gin.Context) {
 
 	uid := c.GetString("uid")
 
-	// check if user is creator of custom game
-	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(db.Root, req.CustomGameConfigId)
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
 	if err != nil {
 		log.Error(err)
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
-	if !exists {
-		util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
-		return
-	}
-	if customGameConfigurationDAO.CreatorUid != uid {
-		util.AbortWithStrJson(c, http.StatusForbidden, "user is not creator of custom game")
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
 		return
 	}
 
@@ -409,8 +589,24 @@ func ArrangeCustomGameParticipant(c *gin.Context) {
 		return
 	}
 
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.CustomGameConfigId, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	// keep state before change for revision
+	before, err := service.GetCustomGameArrangementSnapshot(tx, req.CustomGameConfigId)
+	if err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
 	// check if candidate exists in config
-	_, exists, err = models.GetCustomGameCandidateDAO_byPuuid(tx, req.CustomGameConfigId, req.Puuid)
+	_, exists, err := models.GetCustomGameCandidateDAO_byPuuid(tx, req.CustomGameConfigId, req.Puuid)
 	if err != nil {
 		log.Error(err)
 		_ = tx.Rollback()
@@ -496,6 +692,13 @@ func ArrangeCustomGameParticipant(c *gin.Context) {
 		return
 	}
 
+	if err := service.RecordCustomGameRevision(tx, req.CustomGameConfigId, &uid, types.CustomGameOperationArrange, *before); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		log.Error(err)
 		_ = tx.Rollback()
@@ -503,7 +706,9 @@ func ArrangeCustomGameParticipant(c *gin.Context) {
 		return
 	}
 
-	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, nil)
+	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
 	c.JSON(http.StatusOK, nil)
 }
 
@@ -517,25 +722,37 @@ func UnarrangeCustomGameParticipant(c *gin.Context) {
 
 	uid := c.GetString("uid")
 
-	// check if user is creator of custom game
-	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(db.Root, req.CustomGameConfigId)
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
 	if err != nil {
 		log.Error(err)
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
-	if !exists {
-		util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
+
+	tx, err := db.Root.BeginTxx(c, nil)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
-	if customGameConfigurationDAO.CreatorUid != uid {
-		util.AbortWithStrJson(c, http.StatusForbidden, "user is not creator of custom game")
+
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.CustomGameConfigId, req.Version)
+	if !ok {
+		_ = tx.Rollback()
 		return
 	}
 
-	tx, err := db.Root.BeginTxx(c, nil)
+	// keep state before change for revision
+	before, err := service.GetCustomGameArrangementSnapshot(tx, req.CustomGameConfigId)
 	if err != nil {
 		log.Error(err)
+		_ = tx.Rollback()
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
@@ -561,6 +778,13 @@ func UnarrangeCustomGameParticipant(c *gin.Context) {
 		return
 	}
 
+	if err := service.RecordCustomGameRevision(tx, req.CustomGameConfigId, &uid, types.CustomGameOperationUnarrange, *before); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		log.Error(err)
 		_ = tx.Rollback()
@@ -568,7 +792,9 @@ func UnarrangeCustomGameParticipant(c *gin.Context) {
 		return
 	}
 
-	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, nil)
+	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
 	c.JSON(http.StatusOK, nil)
 }
 
@@ -582,7 +808,7 @@ func SetCustomGameParticipantFavorPosition(c *gin.Context) {
 
 	uid := c.GetString("uid")
 
-	// check if user is creator of custom game
+	// check if user is editor of custom game
 	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
 	if err != nil {
 		log.Error(err)
@@ -590,7 +816,7 @@ func SetCustomGameParticipantFavorPosition(c *gin.Context) {
 		return
 	}
 	if !permitted {
-		util.AbortWithStrJson(c, http.StatusForbidden, "user is not creator of custom game")
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
 		return
 	}
 
@@ -601,6 +827,13 @@ func SetCustomGameParticipantFavorPosition(c *gin.Context) {
 		return
 	}
 
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.CustomGameConfigId, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
 	// check if candidate exists in config
 	candidateDAO, exists, err := models.GetCustomGameCandidateDAO_byPuuid(tx, req.CustomGameConfigId, req.Puuid)
 	if err != nil {
@@ -665,7 +898,9 @@ func SetCustomGameParticipantFavorPosition(c *gin.Context) {
 		return
 	}
 
-	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, nil)
+	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
 	c.JSON(http.StatusOK, nil)
 }
 
@@ -679,7 +914,7 @@ func SetCustomGameCandidateCustomTierRank(c *gin.Context) {
 
 	uid := c.GetString("uid")
 
-	// check if user is creator of custom game
+	// check if user is editor of custom game
 	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
 	if err != nil {
 		log.Error(err)
@@ -687,7 +922,7 @@ func SetCustomGameCandidateCustomTierRank(c *gin.Context) {
 		return
 	}
 	if !permitted {
-		util.AbortWithStrJson(c, http.StatusForbidden, "user is not creator of custom game")
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
 		return
 	}
 
@@ -698,6 +933,13 @@ func SetCustomGameCandidateCustomTierRank(c *gin.Context) {
 		return
 	}
 
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.CustomGameConfigId, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
 	// check if candidate exists in config
 	candidateDAO, exists, err := models.GetCustomGameCandidateDAO_byPuuid(tx, req.CustomGameConfigId, req.Puuid)
 	if err != nil {
@@ -754,7 +996,9 @@ func SetCustomGameCandidateCustomTierRank(c *gin.Context) {
 		return
 	}
 
-	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, nil)
+	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
 	c.JSON(http.StatusOK, nil)
 }
 
@@ -768,7 +1012,7 @@ func SetCustomGameCandidateCustomColorLabel(c *gin.Context) {
 
 	uid := c.GetString("uid")
 
-	// check if user is creator of custom game
+	// check if user is editor of custom game
 	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
 	if err != nil {
 		log.Error(err)
@@ -777,7 +1021,7 @@ func SetCustomGameCandidateCustomColorLabel(c *gin.Context) {
 	}
 
 	if !permitted {
-		util.AbortWithStrJson(c, http.StatusForbidden, "user is not creator of custom game")
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
 		return
 	}
 
@@ -788,6 +1032,13 @@ func SetCustomGameCandidateCustomColorLabel(c *gin.Context) {
 		return
 	}
 
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.CustomGameConfigId, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
 	// check if candidate exists in config
 	_, exists, err := models.GetCustomGameCandidateDAO_byPuuid(tx, req.CustomGameConfigId, req.Puuid)
 	if err != nil {
@@ -949,7 +1200,9 @@ func SetCustomGameCandidateCustomColorLabel(c *gin.Context) {
 		return
 	}
 
-	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, nil)
+	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
 	c.JSON(http.StatusOK, nil)
 }
 
@@ -963,7 +1216,7 @@ func DeleteCustomGameCandidateCustomColorLabel(c *gin.Context) {
 
 	uid := c.GetString("uid")
 
-	// check if user is creator of custom game
+	// check if user is editor of custom game
 	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
 	if err != nil {
 		log.Error(err)
@@ -971,7 +1224,7 @@ func DeleteCustomGameCandidateCustomColorLabel(c *gin.Context) {
 		return
 	}
 	if !permitted {
-		util.AbortWithStrJson(c, http.StatusForbidden, "user is not creator of custom game")
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
 		return
 	}
 
@@ -982,6 +1235,13 @@ func DeleteCustomGameCandidateCustomColorLabel(c *gin.Context) {
 		return
 	}
 
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.CustomGameConfigId, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
 	// delete color labels
 	if err := models.DeleteCustomGameParticipantColorLabels_byCustomGameConfigId(tx, req.CustomGameConfigId); err != nil {
 		log.Error(err)
@@ -997,12 +1257,14 @@ func DeleteCustomGameCandidateCustomColorLabel(c *gin.Context) {
 		return
 	}
 
-	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, nil)
+	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
 	c.JSON(http.StatusOK, nil)
 }
 
-func OptimizeCustomGameConfiguration(c *gin.Context) {
-	var req OptimizeCustomGameConfigurationRequestDto
+func SetCustomGameCandidateMustPlay(c *gin.Context) {
+	var req SetCustomGameCandidateMustPlayRequestDto
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Error(err)
 		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
@@ -1011,130 +1273,342 @@ func OptimizeCustomGameConfiguration(c *gin.Context) {
 
 	uid := c.GetString("uid")
 
-	tx, err := db.Root.BeginTxx(c, nil)
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
 	if err != nil {
 		log.Error(err)
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
 
-	// check if user is creator of custom game
-	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(tx, req.Id)
+	candidateDAO, exists, err := models.GetCustomGameCandidateDAO_byPuuid(db.Root, req.CustomGameConfigId, req.Puuid)
 	if err != nil {
 		log.Error(err)
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
 	if !exists {
-		util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
-		return
-	}
-	if customGameConfigurationDAO.CreatorUid != uid {
-		util.AbortWithStrJson(c, http.StatusForbidden, "user is not creator of custom game")
+		util.AbortWithStrJson(c, http.StatusBadRequest, "candidate not found")
 		return
 	}
 
-	if req.LineFairnessWeight == nil || *req.LineFairnessWeight < 0 || *req.LineFairnessWeight > 1 {
-		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid line fairness weight")
-		return
-	}
-	if req.TopInfluenceWeight == nil || *req.TopInfluenceWeight < 0 || *req.TopInfluenceWeight > 1 {
-		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid top influence weight")
-		return
-	}
-	if req.JungleInfluenceWeight == nil || *req.JungleInfluenceWeight < 0 || *req.JungleInfluenceWeight > 1 {
-		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid jungle influence weight")
+	tx, err := db.Root.BeginTxx(c, nil)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
-	if req.MidInfluenceWeight == nil || *req.MidInfluenceWeight < 0 || *req.MidInfluenceWeight > 1 {
-		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid mid influence weight")
+
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.CustomGameConfigId, req.Version)
+	if !ok {
+		_ = tx.Rollback()
 		return
 	}
-	if req.AdcInfluenceWeight == nil || *req.AdcInfluenceWeight < 0 || *req.AdcInfluenceWeight > 1 {
-		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid adc influence weight")
+
+	candidateDAO.MustPlay = *req.MustPlay
+	if err := candidateDAO.Upsert(tx); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	customGameConfigurationDAO.LineFairnessWeight = *req.LineFairnessWeight
-	customGameConfigurationDAO.TierFairnessWeight = *req.TierFairnessWeight
-	customGameConfigurationDAO.LineSatisfactionWeight = 1 - *req.LineFairnessWeight - *req.TierFairnessWeight
-	customGameConfigurationDAO.TopInfluenceWeight = *req.TopInfluenceWeight
-	customGameConfigurationDAO.JungleInfluenceWeight = *req.JungleInfluenceWeight
-	customGameConfigurationDAO.MidInfluenceWeight = *req.MidInfluenceWeight
-	customGameConfigurationDAO.AdcInfluenceWeight = *req.AdcInfluenceWeight
-	customGameConfigurationDAO.SupportInfluenceWeight = 1 - *req.TopInfluenceWeight - *req.JungleInfluenceWeight - *req.MidInfluenceWeight - *req.AdcInfluenceWeight
-
-	if err := customGameConfigurationDAO.Upsert(tx); err != nil {
+	if err := tx.Commit(); err != nil {
 		log.Error(err)
+		_ = tx.Rollback()
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	participantVOsMap, err := service.GetCurrentCustomGameTeamParticipantVOMap(tx, req.Id)
-	if err != nil {
+	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
+	c.JSON(http.StatusOK, nil)
+}
+
+// AddCustomGameConstraint adds constraint of arrangement, rejected if it conflicts with existing constraints
+func AddCustomGameConstraint(c *gin.Context) {
+	var req AddCustomGameConstraintRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Error(err)
-		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
 		return
 	}
 
-	colorLabelDAOs, err := models.GetCustomGameParticipantColorLabelDAOs_byCustomGameConfigId(tx, req.Id)
+	uid := c.GetString("uid")
+
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
 	if err != nil {
 		log.Error(err)
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
 
-	colorMap := make(map[string]int)
-	for _, colorLabelDAO := range colorLabelDAOs {
-		if colorLabelDAO.ColorCode == 0 {
+	constraintVO := service.CustomGameConstraintVO{
+		Id:          uuid.NewString(),
+		Type:        req.Type,
+		Puuid:       req.Puuid,
+		TargetPuuid: req.TargetPuuid,
+		Team:        req.Team,
+		Position:    req.Position,
+	}
+	if err := service.ValidateCustomGameConstraint(constraintVO); err != nil {
+		util.AbortWithErrJson(c, http.StatusBadRequest, err)
+		return
+	}
+
+	tx, err := db.Root.BeginTxx(c, nil)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.CustomGameConfigId, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	// constrained players should be candidates
+	for _, puuid := range []*string{&req.Puuid, req.TargetPuuid} {
+		if puuid == nil {
 			continue
 		}
-		if _, exists := colorMap[colorLabelDAO.Puuid]; !exists {
-			colorMap[colorLabelDAO.Puuid] = 0
+		_, exists, err := models.GetCustomGameCandidateDAO_byPuuid(tx, req.CustomGameConfigId, *puuid)
+		if err != nil {
+			log.Error(err)
+			_ = tx.Rollback()
+			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+			return
 		}
+		if !exists {
+			_ = tx.Rollback()
+			util.AbortWithStrJson(c, http.StatusBadRequest, "candidate not found")
+			return
+		}
+	}
+
+	constraintVOs, err := service.GetCustomGameConstraintVOs(tx, req.CustomGameConfigId)
+	if err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	for _, existing := range constraintVOs {
+		if existing.Type == constraintVO.Type && existing.Puuid == constraintVO.Puuid &&
+			util.EqualPtr(existing.TargetPuuid, constraintVO.TargetPuuid) &&
+			util.EqualPtr(existing.Team, constraintVO.Team) &&
+			util.EqualPtr(existing.Position, constraintVO.Position) {
+			_ = tx.Rollback()
+			util.AbortWithStrJson(c, http.StatusConflict, "constraint already exists")
+			return
+		}
+	}
+
+	colorLabelDAOs, err := models.GetCustomGameParticipantColorLabelDAOs_byCustomGameConfigId(tx, req.CustomGameConfigId)
+	if err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	colorMap := make(map[string]int)
+	for _, colorLabelDAO := range colorLabelDAOs {
 		colorMap[colorLabelDAO.Puuid] = colorLabelDAO.ColorCode
 	}
 
-	configWeightsVO := service.CustomGameConfigurationWeightsMixer(*customGameConfigurationDAO)
-	optimizedParticipantVOsMap, err := service.FindBalancedCustomGameConfig(req.Id, participantVOsMap, configWeightsVO, colorMap)
+	// reject if no arrangement satisfies constraints
+	conflict := service.FindCustomGameConstraintConflict(append(constraintVOs, constraintVO), colorMap)
+	if conflict != nil {
+		_ = tx.Rollback()
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error":                  "constraint conflicts with other constraints",
+			"conflictingConstraints": conflict,
+		})
+		return
+	}
+
+	constraintDAO := models.CustomGameConstraintDAO{
+		Id:                 constraintVO.Id,
+		CustomGameConfigId: req.CustomGameConfigId,
+		Type:               constraintVO.Type,
+		Puuid:              constraintVO.Puuid,
+		TargetPuuid:        constraintVO.TargetPuuid,
+		Team:               constraintVO.Team,
+		Position:           constraintVO.Position,
+		CreatedAt:          time.Now(),
+	}
+	if err := constraintDAO.Insert(tx); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
+	c.JSON(http.StatusOK, AddCustomGameConstraintResponseDto(constraintVO))
+}
+
+func DeleteCustomGameConstraint(c *gin.Context) {
+	var req DeleteCustomGameConstraintRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
 	if err != nil {
 		log.Error(err)
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
 
-	// reorganize participants
+	constraintDAO, exists, err := models.GetCustomGameConstraintDAO_byId(db.Root, req.CustomGameConfigId, req.Id)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !exists {
+		util.AbortWithStrJson(c, http.StatusNotFound, "constraint not found")
+		return
+	}
 
-	participantVOs, err := models.GetCustomGameParticipantDAOs_byCustomGameConfigId(tx, req.Id)
+	tx, err := db.Root.BeginTxx(c, nil)
 	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.CustomGameConfigId, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	if err := constraintDAO.Delete(tx); err != nil {
 		log.Error(err)
 		_ = tx.Rollback()
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	for _, participantVO := range participantVOs {
-		// delete
-		if err := participantVO.Delete(tx); err != nil {
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
+	c.JSON(http.StatusOK, nil)
+}
+
+// FinishCustomGameRound records bench history of the round (participants played, other candidates benched)
+func FinishCustomGameRound(c *gin.Context) {
+	var req FinishCustomGameRoundRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	configIds := []string{req.Id}
+	if req.LobbyConfigId != nil && *req.LobbyConfigId != req.Id {
+		configIds = append(configIds, *req.LobbyConfigId)
+	}
+	for _, configId := range configIds {
+		permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, configId, uid)
+		if err != nil {
 			log.Error(err)
-			_ = tx.Rollback()
 			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 			return
 		}
+		if !permitted {
+			util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+			return
+		}
 	}
 
-	for _, participantVO := range participantVOs {
-		teamParticipantVO, exists := (*optimizedParticipantVOsMap)[participantVO.Puuid]
-		if !exists {
-			log.Errorf("participant not found in optimized map")
+	tx, err := db.Root.BeginTxx(c, nil)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.Id, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	played := make(map[string]bool)
+	for _, configId := range configIds {
+		participantDAOs, err := models.GetCustomGameParticipantDAOs_byCustomGameConfigId(tx, configId)
+		if err != nil {
+			log.Error(err)
 			_ = tx.Rollback()
 			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
 			return
 		}
+		for _, participantDAO := range participantDAOs {
+			played[participantDAO.Puuid] = true
+		}
+	}
 
-		participantVO.Team = teamParticipantVO.Team
-		participantVO.Position = teamParticipantVO.Position
-		if err := participantVO.Upsert(tx); err != nil {
+	candidateDAOs, err := models.GetCustomGameCandidateDAOs_byCustomGameConfigId(tx, req.Id)
+	if err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	for _, candidateDAO := range candidateDAOs {
+		if played[candidateDAO.Puuid] {
+			candidateDAO.PlayedCount++
+			candidateDAO.BenchStreak = 0
+		} else {
+			candidateDAO.BenchStreak++
+		}
+		if err := candidateDAO.Upsert(tx); err != nil {
 			log.Error(err)
 			_ = tx.Rollback()
 			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
@@ -1142,7 +1616,333 @@ func OptimizeCustomGameConfiguration(c *gin.Context) {
 		}
 	}
 
-	if err := service.RecalculateCustomGameBalance(tx, req.Id); err != nil {
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
+	c.JSON(http.StatusOK, nil)
+}
+
+func OptimizeCustomGameConfiguration(c *gin.Context) {
+	var req OptimizeCustomGameConfigurationRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	var lobbyVersion *int
+	if req.LobbyConfigId != nil {
+		if *req.LobbyConfigId == req.Id {
+			util.AbortWithStrJson(c, http.StatusBadRequest, "invalid lobby configuration")
+			return
+		}
+		permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, *req.LobbyConfigId, uid)
+		if err != nil {
+			log.Error(err)
+			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		if !permitted {
+			util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of lobby configuration")
+			return
+		}
+		lobbyConfigDAO, exists, err := models.GetCustomGameDAO_byId(db.Root, *req.LobbyConfigId)
+		if err != nil {
+			log.Error(err)
+			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		if !exists {
+			util.AbortWithStrJson(c, http.StatusBadRequest, "invalid lobby configuration")
+			return
+		}
+		lobbyVersion = &lobbyConfigDAO.Version
+	}
+
+	tx, err := db.Root.BeginTxx(c, nil)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// check if user is editor of custom game
+	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(tx, req.Id)
+	if err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !exists {
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
+		return
+	}
+	permitted, err := service.CheckPermissionForCustomGameConfig(tx, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
+
+	// hold optimization of configurations, so that weights are committed only if job can start
+	reservation, err := service.ReserveCustomGameOptimizeJob(req.Id, req.LobbyConfigId)
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, service.ErrCustomGameOptimizeJobAlreadyRunning) {
+			util.AbortWithStrJson(c, http.StatusConflict, "optimization is already running")
+			return
+		}
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	started := false
+	defer func() {
+		if !started {
+			reservation.Release()
+		}
+	}()
+
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.Id, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	// keep state before change for revision
+	before, err := service.GetCustomGameArrangementSnapshot(tx, req.Id)
+	if err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	if req.LineFairnessWeight == nil || *req.LineFairnessWeight < 0 || *req.LineFairnessWeight > 1 {
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid line fairness weight")
+		return
+	}
+	if req.TopInfluenceWeight == nil || *req.TopInfluenceWeight < 0 || *req.TopInfluenceWeight > 1 {
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid top influence weight")
+		return
+	}
+	if req.JungleInfluenceWeight == nil || *req.JungleInfluenceWeight < 0 || *req.JungleInfluenceWeight > 1 {
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid jungle influence weight")
+		return
+	}
+	if req.MidInfluenceWeight == nil || *req.MidInfluenceWeight < 0 || *req.MidInfluenceWeight > 1 {
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid mid influence weight")
+		return
+	}
+	if req.AdcInfluenceWeight == nil || *req.AdcInfluenceWeight < 0 || *req.AdcInfluenceWeight > 1 {
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid adc influence weight")
+		return
+	}
+
+	customGameConfigurationDAO.LineFairnessWeight = *req.LineFairnessWeight
+	customGameConfigurationDAO.TierFairnessWeight = *req.TierFairnessWeight
+	customGameConfigurationDAO.LineSatisfactionWeight = 1 - *req.LineFairnessWeight - *req.TierFairnessWeight
+	customGameConfigurationDAO.TopInfluenceWeight = *req.TopInfluenceWeight
+	customGameConfigurationDAO.JungleInfluenceWeight = *req.JungleInfluenceWeight
+	customGameConfigurationDAO.MidInfluenceWeight = *req.MidInfluenceWeight
+	customGameConfigurationDAO.AdcInfluenceWeight = *req.AdcInfluenceWeight
+	customGameConfigurationDAO.SupportInfluenceWeight = 1 - *req.TopInfluenceWeight - *req.JungleInfluenceWeight - *req.MidInfluenceWeight - *req.AdcInfluenceWeight
+	if req.InHouseRatingWeight != nil {
+		customGameConfigurationDAO.InHouseRatingWeight = *req.InHouseRatingWeight
+	}
+	if req.ChampionPoolWeight != nil {
+		customGameConfigurationDAO.ChampionPoolWeight = *req.ChampionPoolWeight
+	}
+
+	if err := customGameConfigurationDAO.Upsert(tx); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	if err := service.RecordCustomGameRevision(tx, req.Id, &uid, types.CustomGameOperationWeights, *before); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	options := service.CustomGameOptimizeOptions{
+		ResultCount:   types.CustomGameOptimizeResultCount,
+		IncludeBench:  req.IncludeBench,
+		ActorUid:      uid,
+		Version:       &version,
+		LobbyVersion:  lobbyVersion,
+		FairnessModel: customGameConfigurationDAO.FairnessModel,
+	}
+	if req.ResultCount != nil {
+		options.ResultCount = *req.ResultCount
+		if options.ResultCount > types.CustomGameOptimizeMaxResultCount {
+			options.ResultCount = types.CustomGameOptimizeMaxResultCount
+		}
+	}
+
+	// optimization runs in background, progress & result are sent via socket
+	configWeightsVO := service.CustomGameConfigurationWeightsMixer(*customGameConfigurationDAO)
+	jobVO := reservation.Start(configWeightsVO, options)
+	started = true
+
+	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
+	c.JSON(http.StatusAccepted, OptimizeCustomGameConfigurationResponseDto(*jobVO))
+}
+
+func GetCustomGameOptimizeJob(c *gin.Context) {
+	var req GetCustomGameOptimizeJobRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user can view custom game
+	permitted, err := service.CheckViewPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not member of private custom game")
+		return
+	}
+
+	jobVO, exists := service.GetCustomGameOptimizeJobVO(req.Id)
+	if !exists {
+		util.AbortWithStrJson(c, http.StatusNotFound, "optimization job not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, GetCustomGameOptimizeJobResponseDto(*jobVO))
+}
+
+func CancelCustomGameOptimizeJob(c *gin.Context) {
+	var req CancelCustomGameOptimizeJobRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
+
+	if err := service.CancelCustomGameOptimizeJob(req.Id, req.JobId); err != nil {
+		if errors.Is(err, service.ErrCustomGameOptimizeJobNotFound) {
+			util.AbortWithStrJson(c, http.StatusNotFound, "running optimization job not found")
+			return
+		}
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// SetCustomGameFairnessModel changes fairness model of configuration, balance is recalculated by new model
+func SetCustomGameFairnessModel(c *gin.Context) {
+	var req SetCustomGameFairnessModelRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
+
+	tx, err := db.Root.BeginTxx(c, nil)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.Id, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	before, err := service.GetCustomGameArrangementSnapshot(tx, req.Id)
+	if err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	if err := service.SetCustomGameFairnessModel(tx, req.Id, req.FairnessModel); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	if err := service.RecordCustomGameRevision(tx, req.Id, &uid, types.CustomGameOperationFairness, *before); err != nil {
 		log.Error(err)
 		_ = tx.Rollback()
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
@@ -1156,7 +1956,9 @@ func OptimizeCustomGameConfiguration(c *gin.Context) {
 		return
 	}
 
-	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, nil)
+	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
 	c.JSON(http.StatusOK, nil)
 }
 
@@ -1170,7 +1972,7 @@ func SelectMaxCandidates(c *gin.Context) {
 
 	uid := c.GetString("uid")
 
-	// check if user is creator of custom game
+	// check if user is editor of custom game
 	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.Id, uid)
 	if err != nil {
 		log.Error(err)
@@ -1178,7 +1980,7 @@ func SelectMaxCandidates(c *gin.Context) {
 		return
 	}
 	if !permitted {
-		util.AbortWithStrJson(c, http.StatusForbidden, "user is not creator of custom game")
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
 		return
 	}
 
@@ -1189,6 +1991,22 @@ func SelectMaxCandidates(c *gin.Context) {
 		return
 	}
 
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.Id, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	// keep state before change for revision
+	before, err := service.GetCustomGameArrangementSnapshot(tx, req.Id)
+	if err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
 	// get all candidates
 	candidateDAOs, err := models.GetCustomGameCandidateDAOs_byCustomGameConfigId(tx, req.Id)
 	if err != nil {
@@ -1267,6 +2085,13 @@ func SelectMaxCandidates(c *gin.Context) {
 		return
 	}
 
+	if err := service.RecordCustomGameRevision(tx, req.Id, &uid, types.CustomGameOperationArrangeAll, *before); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		log.Error(err)
 		_ = tx.Rollback()
@@ -1274,7 +2099,9 @@ func SelectMaxCandidates(c *gin.Context) {
 		return
 	}
 
-	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, nil)
+	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
 	c.JSON(http.StatusOK, nil)
 }
 
@@ -1288,7 +2115,7 @@ func UnarrangeAllParticipants(c *gin.Context) {
 
 	uid := c.GetString("uid")
 
-	// check if user is creator of custom game
+	// check if user is editor of custom game
 	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.Id, uid)
 	if err != nil {
 		log.Error(err)
@@ -1296,7 +2123,7 @@ func UnarrangeAllParticipants(c *gin.Context) {
 		return
 	}
 	if !permitted {
-		util.AbortWithStrJson(c, http.StatusForbidden, "user is not creator of custom game")
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
 		return
 	}
 
@@ -1307,6 +2134,22 @@ func UnarrangeAllParticipants(c *gin.Context) {
 		return
 	}
 
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.Id, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	// keep state before change for revision
+	before, err := service.GetCustomGameArrangementSnapshot(tx, req.Id)
+	if err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
 	// delete all participants
 	if err := models.DeleteCustomGameParticipantDAOs_byId(tx, req.Id); err != nil {
 		log.Error(err)
@@ -1331,6 +2174,13 @@ func UnarrangeAllParticipants(c *gin.Context) {
 		return
 	}
 
+	if err := service.RecordCustomGameRevision(tx, req.Id, &uid, types.CustomGameOperationUnarrangeAll, *before); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		log.Error(err)
 		_ = tx.Rollback()
@@ -1338,7 +2188,9 @@ func UnarrangeAllParticipants(c *gin.Context) {
 		return
 	}
 
-	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, nil)
+	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
 	c.JSON(http.StatusOK, nil)
 }
 
@@ -1352,7 +2204,7 @@ func SwapTeam(c *gin.Context) {
 
 	uid := c.GetString("uid")
 
-	// check if user is creator of custom game
+	// check if user is editor of custom game
 	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.Id, uid)
 	if err != nil {
 		log.Error(err)
@@ -1360,7 +2212,7 @@ func SwapTeam(c *gin.Context) {
 		return
 	}
 	if !permitted {
-		util.AbortWithStrJson(c, http.StatusForbidden, "user is not creator of custom game")
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
 		return
 	}
 
@@ -1371,6 +2223,22 @@ func SwapTeam(c *gin.Context) {
 		return
 	}
 
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.Id, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	// keep state before change for revision
+	before, err := service.GetCustomGameArrangementSnapshot(tx, req.Id)
+	if err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
 	// get all participants
 	participantDAOs, err := models.GetCustomGameParticipantDAOs_byCustomGameConfigId(tx, req.Id)
 	if err != nil {
@@ -1410,6 +2278,13 @@ func SwapTeam(c *gin.Context) {
 		return
 	}
 
+	if err := service.RecordCustomGameRevision(tx, req.Id, &uid, types.CustomGameOperationSwapTeam, *before); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		log.Error(err)
 		_ = tx.Rollback()
@@ -1417,7 +2292,9 @@ func SwapTeam(c *gin.Context) {
 		return
 	}
 
-	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, nil)
+	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
 	c.JSON(http.StatusOK, nil)
 }
 
@@ -1431,7 +2308,7 @@ func ShuffleTeam(c *gin.Context) {
 
 	uid := c.GetString("uid")
 
-	// check if user is creator of custom game
+	// check if user is editor of custom game
 	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.Id, uid)
 	if err != nil {
 		log.Error(err)
@@ -1439,7 +2316,7 @@ func ShuffleTeam(c *gin.Context) {
 		return
 	}
 	if !permitted {
-		util.AbortWithStrJson(c, http.StatusForbidden, "user is not creator of custom game")
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
 		return
 	}
 
@@ -1450,6 +2327,22 @@ func ShuffleTeam(c *gin.Context) {
 		return
 	}
 
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.Id, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	// keep state before change for revision
+	before, err := service.GetCustomGameArrangementSnapshot(tx, req.Id)
+	if err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
 	// get all participants
 	participantDAOs, err := models.GetCustomGameParticipantDAOs_byCustomGameConfigId(tx, req.Id)
 	if err != nil {
@@ -1496,6 +2389,13 @@ func ShuffleTeam(c *gin.Context) {
 		return
 	}
 
+	if err := service.RecordCustomGameRevision(tx, req.Id, &uid, types.CustomGameOperationShuffle, *before); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
 	if err := tx.Commit(); err != nil {
 		log.Error(err)
 		_ = tx.Rollback()
@@ -1503,12 +2403,14 @@ func ShuffleTeam(c *gin.Context) {
 		return
 	}
 
-	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, nil)
+	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
 	c.JSON(http.StatusOK, nil)
 }
 
 func RenewRanks(c *gin.Context) {
-	var req UtilityRequestDto
+	var req RenewRanksRequestDto
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Error(err)
 		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
@@ -1517,7 +2419,7 @@ func RenewRanks(c *gin.Context) {
 
 	uid := c.GetString("uid")
 
-	// check if user is creator of custom game
+	// check if user is editor of custom game
 	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.Id, uid)
 	if err != nil {
 		log.Error(err)
@@ -1525,7 +2427,7 @@ func RenewRanks(c *gin.Context) {
 		return
 	}
 	if !permitted {
-		util.AbortWithStrJson(c, http.StatusForbidden, "user is not creator of custom game")
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
 		return
 	}
 
@@ -1561,24 +2463,22 @@ func RenewRanks(c *gin.Context) {
 		}
 
 		// update profile
-		if _, _, err := service.RenewSummonerInfoByPuuid(tx, summonerDAO.Puuid); err != nil {
+		if _, err := service.RenewSummonerInfoByPuuid(c, tx, summonerDAO.Platform, summonerDAO.Puuid); err != nil {
 			log.Warnf("failed to renew summoner info: %s, but whatever.", err)
 			log.Warn(err)
 		}
 
 		// get rank info
-		if err := service.RenewSummonerLeague(tx, summonerDAO.Id, summonerDAO.Puuid); err != nil {
-			log.Error(err)
+		if err := service.RenewSummonerLeague(c, tx, summonerDAO.Platform, summonerDAO.Id, summonerDAO.Puuid); err != nil {
 			_ = tx.Rollback()
-			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+			util2.AbortWithRiotError(c, err, "summoner not found")
 			return
 		}
 
 		// get mastery info
-		if err := service.RenewSummonerMastery(tx, summonerDAO.Id, summonerDAO.Puuid); err != nil {
-			log.Error(err)
+		if err := service.RenewSummonerMastery(c, tx, summonerDAO.Platform, summonerDAO.Id, summonerDAO.Puuid); err != nil {
 			_ = tx.Rollback()
-			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+			util2.AbortWithRiotError(c, err, "summoner not found")
 			return
 		}
 	}
@@ -0,0 +1,181 @@
+package platform
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/shyunku-libraries/go-logger"
+	"net/http"
+	"team.gg-server/libs/db"
+	"team.gg-server/service"
+	"team.gg-server/types"
+	"team.gg-server/util"
+)
+
+// StartCustomGameDraft starts captain draft, picks & result are sent via socket
+func StartCustomGameDraft(c *gin.Context) {
+	var req StartCustomGameDraftRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
+
+	// drafted teams are applied on this version
+	if !checkCustomGameVersion(c, req.Id, req.Version) {
+		return
+	}
+
+	options := service.CustomGameDraftOptions{
+		Order:       req.Order,
+		TurnSeconds: types.CustomGameDraftDefaultTurnSeconds,
+		ActorUid:    uid,
+		Version:     *req.Version,
+	}
+	if req.TurnSeconds != nil {
+		if *req.TurnSeconds < types.CustomGameDraftMinTurnSeconds || *req.TurnSeconds > types.CustomGameDraftMaxTurnSeconds {
+			util.AbortWithStrJson(c, http.StatusBadRequest, "invalid turn seconds")
+			return
+		}
+		options.TurnSeconds = *req.TurnSeconds
+	}
+	for i, captain := range req.Captains {
+		options.Captains[i] = service.CustomGameDraftCaptainVO{
+			Team:     i + 1,
+			Puuid:    captain.Puuid,
+			Uid:      captain.Uid,
+			Position: captain.Position,
+		}
+	}
+
+	draftVO, err := service.StartCustomGameDraft(db.Root, req.Id, options)
+	if err != nil {
+		if errors.Is(err, service.ErrCustomGameDraftAlreadyRunning) {
+			util.AbortWithErrJson(c, http.StatusConflict, err)
+			return
+		}
+		if errors.Is(err, service.ErrCustomGameDraftInvalidOrder) ||
+			errors.Is(err, service.ErrCustomGameDraftInvalidCaptain) ||
+			errors.Is(err, service.ErrCustomGameDraftNotEnough) {
+			util.AbortWithErrJson(c, http.StatusBadRequest, err)
+			return
+		}
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, StartCustomGameDraftResponseDto(*draftVO))
+}
+
+func GetCustomGameDraft(c *gin.Context) {
+	var req GetCustomGameDraftRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user can view custom game
+	permitted, err := service.CheckViewPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not member of private custom game")
+		return
+	}
+
+	draftVO, exists := service.GetCustomGameDraftVO(req.Id)
+	if !exists {
+		util.AbortWithStrJson(c, http.StatusNotFound, "draft not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, GetCustomGameDraftResponseDto(*draftVO))
+}
+
+// PickCustomGameDraft picks player for captain of current turn, only user controlling the captain can pick
+func PickCustomGameDraft(c *gin.Context) {
+	var req PickCustomGameDraftRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	draftVO, err := service.PickCustomGameDraft(req.Id, req.DraftId, uid, req.Puuid, req.Position)
+	if err != nil {
+		if errors.Is(err, service.ErrCustomGameDraftNotFound) {
+			util.AbortWithStrJson(c, http.StatusNotFound, "running draft not found")
+			return
+		}
+		if errors.Is(err, service.ErrCustomGameDraftNotYourTurn) {
+			util.AbortWithErrJson(c, http.StatusForbidden, err)
+			return
+		}
+		if errors.Is(err, service.ErrCustomGameDraftInvalidPick) {
+			util.AbortWithErrJson(c, http.StatusBadRequest, err)
+			return
+		}
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, PickCustomGameDraftResponseDto(*draftVO))
+}
+
+func CancelCustomGameDraft(c *gin.Context) {
+	var req CancelCustomGameDraftRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
+
+	if err := service.CancelCustomGameDraft(req.Id, req.DraftId); err != nil {
+		if errors.Is(err, service.ErrCustomGameDraftNotFound) {
+			util.AbortWithStrJson(c, http.StatusNotFound, "running draft not found")
+			return
+		}
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
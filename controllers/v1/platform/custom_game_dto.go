@@ -1,6 +1,9 @@
 package platform
 
-import "team.gg-server/service"
+import (
+	"team.gg-server/service"
+	"time"
+)
 
 type GetCustomGameConfigurationsResponseDto []service.CustomGameConfigurationSummaryVO
 
@@ -30,13 +33,23 @@ type AddCandidateToCustomGameRequestDto struct {
 	CustomGameConfigId string `json:"customGameConfigId" binding:"required"`
 	Name               string `json:"name" binding:"required"`
 	TagLine            string `json:"tagLine" binding:"required"`
+	Region             string `json:"region"`
+	Version            *int   `json:"version" binding:"required"`
 }
 
 type AddCandidateToCustomGameResponseDto service.CustomGameCandidateVO
 
+type SuggestCustomGameCandidatePositionFavorRequestDto struct {
+	CustomGameConfigId string `form:"customGameConfigId" binding:"required"`
+	Puuid              string `form:"puuid"` // suggest for every candidate if empty
+}
+
+type SuggestCustomGameCandidatePositionFavorResponseDto []service.CustomGamePositionFavorSuggestionVO
+
 type DeleteCandidateFromCustomGameRequestDto struct {
 	CustomGameConfigId string `form:"customGameConfigId" binding:"required"`
 	Puuid              string `form:"puuid" binding:"required"`
+	Version            *int   `form:"version" binding:"required"`
 }
 
 type ArrangeCustomGameParticipantRequestDto struct {
@@ -44,11 +57,13 @@ type ArrangeCustomGameParticipantRequestDto struct {
 	Puuid              string `json:"puuid" binding:"required"`
 	Team               int    `json:"team" binding:"required"`
 	TargetPosition     string `json:"targetPosition" binding:"required"`
+	Version            *int   `json:"version" binding:"required"`
 }
 
 type UnarrangeCustomGameParticipantRequestDto struct {
 	CustomGameConfigId string `json:"customGameConfigId" binding:"required"`
 	Puuid              string `json:"puuid" binding:"required"`
+	Version            *int   `json:"version" binding:"required"`
 }
 
 type SetCustomGameParticipantFavorPositionRequestDto struct {
@@ -56,6 +71,7 @@ type SetCustomGameParticipantFavorPositionRequestDto struct {
 	Puuid              string `json:"puuid" binding:"required"`
 	FavorPosition      string `json:"favorPosition" binding:"required"`
 	Strength           *int   `json:"strength" binding:"required"`
+	Version            *int   `json:"version" binding:"required"`
 }
 
 type SetCustomGameCandidateCustomTierRankRequestDto struct {
@@ -63,16 +79,51 @@ type SetCustomGameCandidateCustomTierRankRequestDto struct {
 	Puuid              string  `json:"puuid" binding:"required"`
 	Tier               *string `json:"tier"`
 	Rank               *string `json:"rank"`
+	Version            *int    `json:"version" binding:"required"`
 }
 
 type SetCustomGameCandidateCustomColorLabelRequestDto struct {
 	CustomGameConfigId string `json:"customGameConfigId" binding:"required"`
 	Puuid              string `json:"puuid" binding:"required"`
 	ColorCode          *int   `json:"colorCode" binding:"required,number,gte=0,lte=5"`
+	Version            *int   `json:"version" binding:"required"`
+}
+
+type SetCustomGameCandidateMustPlayRequestDto struct {
+	CustomGameConfigId string `json:"customGameConfigId" binding:"required"`
+	Puuid              string `json:"puuid" binding:"required"`
+	MustPlay           *bool  `json:"mustPlay" binding:"required"`
+	Version            *int   `json:"version" binding:"required"`
+}
+
+type AddCustomGameConstraintRequestDto struct {
+	CustomGameConfigId string  `json:"customGameConfigId" binding:"required"`
+	Type               string  `json:"type" binding:"required"`
+	Puuid              string  `json:"puuid" binding:"required"`
+	TargetPuuid        *string `json:"targetPuuid"` // together, apart
+	Team               *int    `json:"team"`        // locked team
+	Position           *string `json:"position"`    // locked position, forbidden position
+	Version            *int    `json:"version" binding:"required"`
+}
+
+type AddCustomGameConstraintResponseDto service.CustomGameConstraintVO
+
+type DeleteCustomGameConstraintRequestDto struct {
+	CustomGameConfigId string `form:"customGameConfigId" binding:"required"`
+	Id                 string `form:"id" binding:"required"`
+	Version            *int   `form:"version" binding:"required"`
+}
+
+type FinishCustomGameRoundRequestDto struct {
+	Id string `json:"id" binding:"required"`
+	// participants of this configuration also count as played (when 2 lobbies are played)
+	LobbyConfigId *string `json:"lobbyConfigId"`
+	Version       *int    `json:"version" binding:"required"`
 }
 
 type DeleteCustomGameCandidateCustomColorLabelRequestDto struct {
 	CustomGameConfigId string `form:"customGameConfigId" binding:"required"`
+	Version            *int   `form:"version" binding:"required"`
 }
 
 type OptimizeCustomGameConfigurationRequestDto struct {
@@ -87,8 +138,222 @@ type OptimizeCustomGameConfigurationRequestDto struct {
 	MidInfluenceWeight     *float64 `json:"midInfluenceWeight" binding:"required"`
 	AdcInfluenceWeight     *float64 `json:"adcInfluenceWeight" binding:"required"`
 	SupportInfluenceWeight *float64 `json:"supportInfluenceWeight" binding:"required"`
+
+	// blend of in-house rating into rating point (0: tier only, 1: in-house rating only), unchanged if omitted
+	InHouseRatingWeight *float64 `json:"inHouseRatingWeight" binding:"omitempty,gte=0,lte=1"`
+	// penalty of lacking champion pool on positions (0: ignored), unchanged if omitted
+	ChampionPoolWeight *float64 `json:"championPoolWeight" binding:"omitempty,gte=0,lte=1"`
+
+	// count of top configurations to return (best one is applied)
+	ResultCount *int `json:"resultCount" binding:"omitempty,gte=1"`
+	// select who plays among all candidates (with must-play & bench rotation)
+	IncludeBench bool `json:"includeBench"`
+	// split candidates into 2 lobbies, 2nd lobby is arranged in this configuration (owned by the same user)
+	LobbyConfigId *string `json:"lobbyConfigId"`
+	Version       *int    `json:"version" binding:"required"`
 }
 
-type UtilityRequestDto struct {
+type OptimizeCustomGameConfigurationResponseDto service.CustomGameOptimizeJobVO
+
+type GetCustomGameOptimizeJobRequestDto struct {
+	Id string `form:"id" binding:"required"`
+}
+
+type GetCustomGameOptimizeJobResponseDto service.CustomGameOptimizeJobVO
+
+type CancelCustomGameOptimizeJobRequestDto struct {
+	Id    string `json:"id" binding:"required"`
+	JobId string `json:"jobId" binding:"required"`
+}
+
+type SetCustomGameFairnessModelRequestDto struct {
+	Id            string `json:"id" binding:"required"`
+	FairnessModel string `json:"fairnessModel" binding:"required,oneof=DEFAULT WIN_PROBABILITY"`
+	Version       *int   `json:"version" binding:"required"`
+}
+
+type RecordCustomGameResultRequestDto struct {
+	Id          string     `json:"id" binding:"required"`
+	Winner      int        `json:"winner" binding:"required,oneof=1 2"`
+	RiotMatchId *string    `json:"riotMatchId"` // match id of custom game on riot server
+	PlayedAt    *time.Time `json:"playedAt"`    // now if omitted
+	Version     *int       `json:"version" binding:"required"`
+}
+
+type RecordCustomGameResultResponseDto service.CustomGameResultVO
+
+type ImportCustomGameResultRequestDto struct {
 	Id string `json:"id" binding:"required"`
+	// time window of matches searched (default: recent hours until now)
+	StartTime *time.Time `json:"startTime"`
+	EndTime   *time.Time `json:"endTime"`
+	Version   *int       `json:"version" binding:"required"`
+}
+
+type ImportCustomGameResultResponseDto service.CustomGameResultVO
+
+type GetCustomGameResultsRequestDto struct {
+	Id string `form:"id" binding:"required"`
+}
+
+type GetCustomGameResultsResponseDto []service.CustomGameResultVO
+
+type DeleteCustomGameResultRequestDto struct {
+	CustomGameConfigId string `form:"customGameConfigId" binding:"required"`
+	Id                 string `form:"id" binding:"required"`
+	Version            *int   `form:"version" binding:"required"`
+}
+
+type GetCustomGameRatingsRequestDto struct {
+	Id string `form:"id" binding:"required"`
+}
+
+type GetCustomGameRatingsResponseDto []service.CustomGameRatingVO
+
+type GetCustomGameMembersRequestDto struct {
+	Id string `form:"id" binding:"required"`
+}
+
+type GetCustomGameMembersResponseDto []service.CustomGameMemberVO
+
+type SetCustomGameMemberRoleRequestDto struct {
+	CustomGameConfigId string `json:"customGameConfigId" binding:"required"`
+	Uid                string `json:"uid" binding:"required"`
+	Role               string `json:"role" binding:"required,oneof=OWNER EDITOR VIEWER"`
+}
+
+type DeleteCustomGameMemberRequestDto struct {
+	CustomGameConfigId string `form:"customGameConfigId" binding:"required"`
+	Uid                string `form:"uid" binding:"required"`
+}
+
+type CreateCustomGameInviteRequestDto struct {
+	Id        string `json:"id" binding:"required"`
+	Role      string `json:"role" binding:"required,oneof=EDITOR VIEWER"`
+	ExpiresIn *int64 `json:"expiresIn"` // seconds until expiry (default: a day)
+}
+
+type CreateCustomGameInviteResponseDto service.CustomGameInviteVO
+
+type GetCustomGameInvitesRequestDto struct {
+	Id string `form:"id" binding:"required"`
+}
+
+type GetCustomGameInvitesResponseDto []service.CustomGameInviteVO
+
+type DeleteCustomGameInviteRequestDto struct {
+	CustomGameConfigId string `form:"customGameConfigId" binding:"required"`
+	Token              string `form:"token" binding:"required"`
+}
+
+type AcceptCustomGameInviteRequestDto struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type AcceptCustomGameInviteResponseDto struct {
+	CustomGameConfigId string                     `json:"customGameConfigId"`
+	Member             service.CustomGameMemberVO `json:"member"`
+}
+
+type SetCustomGameConfigPrivacyRequestDto struct {
+	Id        string `json:"id" binding:"required"`
+	IsPrivate *bool  `json:"isPrivate" binding:"required"`
+	Version   *int   `json:"version" binding:"required"`
+}
+
+type CreateCustomGameShareTokenRequestDto struct {
+	Id        string `json:"id" binding:"required"`
+	ExpiresIn *int64 `json:"expiresIn"` // seconds until expiry (never expires if omitted)
+}
+
+type CreateCustomGameShareTokenResponseDto service.CustomGameShareTokenVO
+
+type GetCustomGameShareTokensRequestDto struct {
+	Id string `form:"id" binding:"required"`
+}
+
+type GetCustomGameShareTokensResponseDto []service.CustomGameShareTokenVO
+
+type DeleteCustomGameShareTokenRequestDto struct {
+	CustomGameConfigId string `form:"customGameConfigId" binding:"required"`
+	Token              string `form:"token" binding:"required"`
+}
+
+type GetCustomGameSharedViewRequestDto struct {
+	Token string `form:"token" binding:"required"`
+}
+
+type GetCustomGameSharedViewResponseDto service.CustomGameSharedViewVO
+
+type GetCustomGameRevisionsRequestDto struct {
+	Id string `form:"id" binding:"required"`
+}
+
+type GetCustomGameRevisionsResponseDto []service.CustomGameRevisionVO
+
+type UndoCustomGameRevisionRequestDto struct {
+	Id      string `json:"id" binding:"required"`
+	Version *int   `json:"version" binding:"required"`
+}
+
+type UndoCustomGameRevisionResponseDto service.CustomGameRevisionVO
+
+type RedoCustomGameRevisionRequestDto struct {
+	Id      string `json:"id" binding:"required"`
+	Version *int   `json:"version" binding:"required"`
+}
+
+type RedoCustomGameRevisionResponseDto service.CustomGameRevisionVO
+
+type RestoreCustomGameRevisionRequestDto struct {
+	CustomGameConfigId string `json:"customGameConfigId" binding:"required"`
+	RevisionId         string `json:"revisionId" binding:"required"`
+	Version            *int   `json:"version" binding:"required"`
+}
+
+type RestoreCustomGameRevisionResponseDto service.CustomGameRevisionVO
+
+type RenewRanksRequestDto struct {
+	Id string `json:"id" binding:"required"`
+}
+
+type UtilityRequestDto struct {
+	Id      string `json:"id" binding:"required"`
+	Version *int   `json:"version" binding:"required"`
+}
+
+type StartCustomGameDraftRequestDto struct {
+	Id          string                      `json:"id" binding:"required"`
+	Version     *int                        `json:"version" binding:"required"`
+	Order       string                      `json:"order" binding:"required"`
+	TurnSeconds *int                        `json:"turnSeconds"`                       // default if omitted
+	Captains    []CustomGameDraftCaptainDto `json:"captains" binding:"required,len=2"` // captain of team 1 first
+}
+
+type CustomGameDraftCaptainDto struct {
+	Puuid    string `json:"puuid" binding:"required"`
+	Uid      string `json:"uid" binding:"required"` // member who picks for captain
+	Position string `json:"position" binding:"required"`
+}
+
+type StartCustomGameDraftResponseDto service.CustomGameDraftVO
+
+type GetCustomGameDraftRequestDto struct {
+	Id string `form:"id" binding:"required"`
+}
+
+type GetCustomGameDraftResponseDto service.CustomGameDraftVO
+
+type PickCustomGameDraftRequestDto struct {
+	Id       string `json:"id" binding:"required"`
+	DraftId  string `json:"draftId" binding:"required"`
+	Puuid    string `json:"puuid" binding:"required"`
+	Position string `json:"position"` // favorite free position of player if empty
+}
+
+type PickCustomGameDraftResponseDto service.CustomGameDraftVO
+
+type CancelCustomGameDraftRequestDto struct {
+	Id      string `json:"id" binding:"required"`
+	DraftId string `json:"draftId" binding:"required"`
 }
@@ -0,0 +1,331 @@
+package platform
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/shyunku-libraries/go-logger"
+	"net/http"
+	"team.gg-server/controllers/socket"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/service"
+	"team.gg-server/types"
+	"team.gg-server/util"
+	"time"
+)
+
+func GetCustomGameMembers(c *gin.Context) {
+	var req GetCustomGameMembersRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is member of custom game
+	permitted, err := service.CheckRoleForCustomGameConfig(db.Root, req.Id, uid, types.CustomGameRoleViewer)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not member of custom game")
+		return
+	}
+
+	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(db.Root, req.Id)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !exists {
+		util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
+		return
+	}
+
+	memberVOs, err := service.GetCustomGameMemberVOs(db.Root, *customGameConfigurationDAO)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, GetCustomGameMembersResponseDto(memberVOs))
+}
+
+func SetCustomGameMemberRole(c *gin.Context) {
+	var req SetCustomGameMemberRoleRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is owner of custom game
+	permitted, err := service.CheckOwnershipForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not owner of custom game")
+		return
+	}
+
+	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(db.Root, req.CustomGameConfigId)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !exists {
+		util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
+		return
+	}
+
+	if err := service.SetCustomGameMemberRole(db.Root, *customGameConfigurationDAO, req.Uid, req.Role); err != nil {
+		if errors.Is(err, service.ErrCustomGameInvalidRole) || errors.Is(err, service.ErrCustomGameCreatorRole) {
+			util.AbortWithErrJson(c, http.StatusBadRequest, err)
+			return
+		}
+		if errors.Is(err, service.ErrCustomGameMemberNotFound) {
+			util.AbortWithErrJson(c, http.StatusNotFound, err)
+			return
+		}
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigMemberUpdated, socket.CustomConfigMemberUpdatedData{
+		Uid:  req.Uid,
+		Role: &req.Role,
+	})
+	c.JSON(http.StatusOK, nil)
+}
+
+// DeleteCustomGameMember removes member by owner, or member leaves by oneself
+func DeleteCustomGameMember(c *gin.Context) {
+	var req DeleteCustomGameMemberRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is owner of custom game
+	if req.Uid != uid {
+		permitted, err := service.CheckOwnershipForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
+		if err != nil {
+			log.Error(err)
+			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		if !permitted {
+			util.AbortWithStrJson(c, http.StatusForbidden, "user is not owner of custom game")
+			return
+		}
+	}
+
+	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(db.Root, req.CustomGameConfigId)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !exists {
+		util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
+		return
+	}
+
+	if err := service.RemoveCustomGameMember(db.Root, *customGameConfigurationDAO, req.Uid); err != nil {
+		if errors.Is(err, service.ErrCustomGameCreatorRole) {
+			util.AbortWithErrJson(c, http.StatusBadRequest, err)
+			return
+		}
+		if errors.Is(err, service.ErrCustomGameMemberNotFound) {
+			util.AbortWithErrJson(c, http.StatusNotFound, err)
+			return
+		}
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigMemberUpdated, socket.CustomConfigMemberUpdatedData{
+		Uid:  req.Uid,
+		Role: nil,
+	})
+	// removed member stops receiving updates of private config
+	socket.EvictFromCustomConfigRoom(req.CustomGameConfigId)
+	c.JSON(http.StatusOK, nil)
+}
+
+func CreateCustomGameInvite(c *gin.Context) {
+	var req CreateCustomGameInviteRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is owner of custom game
+	permitted, err := service.CheckOwnershipForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not owner of custom game")
+		return
+	}
+
+	expiry := types.CustomGameInviteDefaultExpiry
+	if req.ExpiresIn != nil {
+		expiry = time.Duration(*req.ExpiresIn) * time.Second
+	}
+	if expiry <= 0 || expiry > types.CustomGameInviteMaxExpiry {
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid expiry")
+		return
+	}
+
+	inviteVO, err := service.CreateCustomGameInvite(db.Root, req.Id, req.Role, uid, expiry)
+	if err != nil {
+		if errors.Is(err, service.ErrCustomGameInvalidRole) {
+			util.AbortWithErrJson(c, http.StatusBadRequest, err)
+			return
+		}
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, CreateCustomGameInviteResponseDto(*inviteVO))
+}
+
+func GetCustomGameInvites(c *gin.Context) {
+	var req GetCustomGameInvitesRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is owner of custom game
+	permitted, err := service.CheckOwnershipForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not owner of custom game")
+		return
+	}
+
+	inviteVOs, err := service.GetCustomGameInviteVOs(db.Root, req.Id)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, GetCustomGameInvitesResponseDto(inviteVOs))
+}
+
+// DeleteCustomGameInvite revokes invite link before expiry
+func DeleteCustomGameInvite(c *gin.Context) {
+	var req DeleteCustomGameInviteRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is owner of custom game
+	permitted, err := service.CheckOwnershipForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not owner of custom game")
+		return
+	}
+
+	inviteDAO, exists, err := models.GetCustomGameInviteDAO_byToken(db.Root, req.Token)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !exists || inviteDAO.CustomGameConfigId != req.CustomGameConfigId {
+		util.AbortWithStrJson(c, http.StatusNotFound, "invite not found")
+		return
+	}
+
+	if err := inviteDAO.Delete(db.Root); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// AcceptCustomGameInvite makes user member of configuration with role of invite
+func AcceptCustomGameInvite(c *gin.Context) {
+	var req AcceptCustomGameInviteRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+	if uid == "" {
+		util.AbortWithStrJson(c, http.StatusUnauthorized, "user not found")
+		return
+	}
+
+	configId, memberVO, err := service.AcceptCustomGameInvite(db.Root, req.Token, uid)
+	if err != nil {
+		if errors.Is(err, service.ErrCustomGameInviteNotFound) {
+			util.AbortWithErrJson(c, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, service.ErrCustomGameMemberNotFound) {
+			util.AbortWithStrJson(c, http.StatusUnauthorized, "user not found")
+			return
+		}
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	socket.SocketIO.MulticastToCustomConfigRoom(configId, uid, socket.EventCustomConfigMemberUpdated, socket.CustomConfigMemberUpdatedData{
+		Uid:  memberVO.Uid,
+		Role: &memberVO.Role,
+	})
+	c.JSON(http.StatusOK, AcceptCustomGameInviteResponseDto{
+		CustomGameConfigId: configId,
+		Member:             *memberVO,
+	})
+}
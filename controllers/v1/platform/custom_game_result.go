@@ -0,0 +1,305 @@
+package platform
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/shyunku-libraries/go-logger"
+	"net/http"
+	"team.gg-server/controllers/socket"
+	util2 "team.gg-server/controllers/util"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/service"
+	"team.gg-server/types"
+	"team.gg-server/util"
+	"time"
+)
+
+// RecordCustomGameResult records current arrangement as played game, and updates in-house ratings
+func RecordCustomGameResult(c *gin.Context) {
+	var req RecordCustomGameResultRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
+
+	playedAt := time.Now()
+	if req.PlayedAt != nil {
+		playedAt = *req.PlayedAt
+	}
+
+	tx, err := db.Root.BeginTxx(c, nil)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.Id, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	resultVO, err := service.RecordCustomGameResult(tx, req.Id, req.Winner, req.RiotMatchId, playedAt)
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, service.ErrCustomGameResultIncompleteTeams) || errors.Is(err, service.ErrCustomGameResultInvalidWinner) {
+			util.AbortWithErrJson(c, http.StatusBadRequest, err)
+			return
+		}
+		if errors.Is(err, service.ErrCustomGameResultAlreadyRecorded) {
+			util.AbortWithErrJson(c, http.StatusConflict, err)
+			return
+		}
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// in-house ratings of candidates are changed
+	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
+	c.JSON(http.StatusOK, RecordCustomGameResultResponseDto(*resultVO))
+}
+
+// ImportCustomGameResult finds custom game played by current arrangement from riot matches, and records it
+func ImportCustomGameResult(c *gin.Context) {
+	var req ImportCustomGameResultRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
+
+	endTime := time.Now()
+	if req.EndTime != nil {
+		endTime = *req.EndTime
+	}
+	startTime := endTime.Add(-types.CustomGameResultImportWindow)
+	if req.StartTime != nil {
+		startTime = *req.StartTime
+	}
+	if !startTime.Before(endTime) {
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid time window")
+		return
+	}
+
+	// reject import based on stale version early (import takes a while),
+	// version is checked again and increased in the transaction recording the result
+	if !checkCustomGameVersion(c, req.Id, req.Version) {
+		return
+	}
+
+	resultVO, version, err := service.ImportCustomGameResult(c, req.Id, req.Version, startTime, endTime)
+	if err != nil {
+		if errors.Is(err, service.ErrCustomGameVersionMismatch) {
+			abortWithCustomGameVersionConflict(c, req.Id)
+			return
+		}
+		if errors.Is(err, service.ErrCustomGameConfigurationNotExists) {
+			util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
+			return
+		}
+		if errors.Is(err, service.ErrCustomGameResultIncompleteTeams) {
+			util.AbortWithErrJson(c, http.StatusBadRequest, err)
+			return
+		}
+		if errors.Is(err, service.ErrCustomGameResultMatchNotFound) {
+			util.AbortWithErrJson(c, http.StatusNotFound, err)
+			return
+		}
+		// errors of riot api while searching match (or internal error)
+		util2.AbortWithRiotError(c, err, "summoner not found")
+		return
+	}
+
+	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
+	c.JSON(http.StatusOK, ImportCustomGameResultResponseDto(*resultVO))
+}
+
+func GetCustomGameResults(c *gin.Context) {
+	var req GetCustomGameResultsRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user can view custom game
+	permitted, err := service.CheckViewPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not member of private custom game")
+		return
+	}
+
+	resultVOs, err := service.GetCustomGameResultVOs(db.Root, req.Id)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, GetCustomGameResultsResponseDto(resultVOs))
+}
+
+// DeleteCustomGameResult deletes played game, in-house ratings are recalculated without it
+func DeleteCustomGameResult(c *gin.Context) {
+	var req DeleteCustomGameResultRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
+
+	tx, err := db.Root.BeginTxx(c, nil)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.CustomGameConfigId, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	resultDAO, exists, err := models.GetCustomGameResultDAO_byId(tx, req.Id)
+	if err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !exists || resultDAO.CustomGameConfigId == nil || *resultDAO.CustomGameConfigId != req.CustomGameConfigId {
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusNotFound, "result not found")
+		return
+	}
+
+	if err := service.DeleteCustomGameResult(tx, *resultDAO); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	socket.SocketIO.MulticastToCustomConfigRoom(req.CustomGameConfigId, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
+	c.JSON(http.StatusOK, nil)
+}
+
+// GetCustomGameRatings returns in-house ratings of group which configuration belongs to
+func GetCustomGameRatings(c *gin.Context) {
+	var req GetCustomGameRatingsRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user can view custom game
+	permitted, err := service.CheckViewPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not member of private custom game")
+		return
+	}
+
+	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(db.Root, req.Id)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !exists {
+		util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
+		return
+	}
+
+	ratingVOs, err := service.GetCustomGameRatingVOs(db.Root, customGameConfigurationDAO.CreatorUid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, GetCustomGameRatingsResponseDto(ratingVOs))
+}
@@ -0,0 +1,163 @@
+package platform
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/shyunku-libraries/go-logger"
+	"net/http"
+	"team.gg-server/controllers/socket"
+	"team.gg-server/libs/db"
+	"team.gg-server/service"
+	"team.gg-server/types"
+	"team.gg-server/util"
+)
+
+func GetCustomGameRevisions(c *gin.Context) {
+	var req GetCustomGameRevisionsRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user can view custom game
+	permitted, err := service.CheckViewPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not member of private custom game")
+		return
+	}
+
+	revisionVOs, err := service.GetCustomGameRevisionVOs(db.Root, req.Id)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, GetCustomGameRevisionsResponseDto(revisionVOs))
+}
+
+func UndoCustomGameRevision(c *gin.Context) {
+	var req UndoCustomGameRevisionRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	revisionVO, ok := applyCustomGameRevision(c, req.Id, req.Version, types.CustomGameOperationUndo, func(tx db.Context, uid string) (*service.CustomGameRevisionVO, error) {
+		return service.UndoCustomGameRevision(tx, req.Id)
+	})
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, UndoCustomGameRevisionResponseDto(*revisionVO))
+}
+
+func RedoCustomGameRevision(c *gin.Context) {
+	var req RedoCustomGameRevisionRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	revisionVO, ok := applyCustomGameRevision(c, req.Id, req.Version, types.CustomGameOperationRedo, func(tx db.Context, uid string) (*service.CustomGameRevisionVO, error) {
+		return service.RedoCustomGameRevision(tx, req.Id)
+	})
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, RedoCustomGameRevisionResponseDto(*revisionVO))
+}
+
+// RestoreCustomGameRevision brings back arrangement & weights right after given revision
+func RestoreCustomGameRevision(c *gin.Context) {
+	var req RestoreCustomGameRevisionRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	revisionVO, ok := applyCustomGameRevision(c, req.CustomGameConfigId, req.Version, types.CustomGameOperationRestore, func(tx db.Context, uid string) (*service.CustomGameRevisionVO, error) {
+		return service.RestoreCustomGameRevision(tx, req.CustomGameConfigId, req.RevisionId, &uid)
+	})
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, RestoreCustomGameRevisionResponseDto(*revisionVO))
+}
+
+// applyCustomGameRevision runs undo/redo/restore in a transaction and broadcasts reverted state to everyone in room.
+// aborts request and returns false on failure.
+func applyCustomGameRevision(c *gin.Context, configId string, expectedVersion *int, operation string,
+	apply func(tx db.Context, uid string) (*service.CustomGameRevisionVO, error)) (*service.CustomGameRevisionVO, bool) {
+	uid := c.GetString("uid")
+
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, configId, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return nil, false
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return nil, false
+	}
+
+	tx, err := db.Root.BeginTxx(c, nil)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return nil, false
+	}
+
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, configId, expectedVersion)
+	if !ok {
+		_ = tx.Rollback()
+		return nil, false
+	}
+
+	revisionVO, err := apply(tx, uid)
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, service.ErrCustomGameRevisionNothingToUndo) || errors.Is(err, service.ErrCustomGameRevisionNothingToRedo) {
+			util.AbortWithErrJson(c, http.StatusConflict, err)
+			return nil, false
+		}
+		if errors.Is(err, service.ErrCustomGameRevisionNotFound) {
+			util.AbortWithErrJson(c, http.StatusNotFound, err)
+			return nil, false
+		}
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return nil, false
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return nil, false
+	}
+
+	socket.SocketIO.BroadcastToCustomConfigRoom(configId, socket.EventCustomConfigRevisionApplied, socket.CustomConfigRevisionAppliedData{
+		RevisionId: revisionVO.Id,
+		Operation:  operation,
+		ActorUid:   uid,
+	})
+	socket.SocketIO.BroadcastToCustomConfigRoom(configId, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
+	return revisionVO, true
+}
@@ -0,0 +1,228 @@
+package platform
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/shyunku-libraries/go-logger"
+	"net/http"
+	"team.gg-server/controllers/socket"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/service"
+	"team.gg-server/util"
+	"time"
+)
+
+// SetCustomGameConfigPrivacy makes configuration private, so that only members can view it by id
+func SetCustomGameConfigPrivacy(c *gin.Context) {
+	var req SetCustomGameConfigPrivacyRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is owner of custom game
+	permitted, err := service.CheckOwnershipForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not owner of custom game")
+		return
+	}
+
+	tx, err := db.Root.BeginTxx(c, nil)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// reject change based on stale version
+	version, ok := increaseCustomGameVersion(c, tx, req.Id, req.Version)
+	if !ok {
+		_ = tx.Rollback()
+		return
+	}
+
+	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(tx, req.Id)
+	if err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !exists {
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
+		return
+	}
+
+	if err := service.SetCustomGameConfigPrivacy(tx, *customGameConfigurationDAO, *req.IsPrivate); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	socket.SocketIO.MulticastToCustomConfigRoom(req.Id, uid, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+		Version: version,
+	})
+	if *req.IsPrivate {
+		// non-members stop receiving updates
+		socket.EvictFromCustomConfigRoom(req.Id)
+	}
+	c.JSON(http.StatusOK, nil)
+}
+
+func CreateCustomGameShareToken(c *gin.Context) {
+	var req CreateCustomGameShareTokenRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
+
+	var expiry *time.Duration
+	if req.ExpiresIn != nil {
+		if *req.ExpiresIn <= 0 {
+			util.AbortWithStrJson(c, http.StatusBadRequest, "invalid expiry")
+			return
+		}
+		duration := time.Duration(*req.ExpiresIn) * time.Second
+		expiry = &duration
+	}
+
+	shareTokenVO, err := service.CreateCustomGameShareToken(db.Root, req.Id, uid, expiry)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, CreateCustomGameShareTokenResponseDto(*shareTokenVO))
+}
+
+func GetCustomGameShareTokens(c *gin.Context) {
+	var req GetCustomGameShareTokensRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.Id, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
+
+	shareTokenVOs, err := service.GetCustomGameShareTokenVOs(db.Root, req.Id)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, GetCustomGameShareTokensResponseDto(shareTokenVOs))
+}
+
+// DeleteCustomGameShareToken revokes share token, shared view is not accessible by it anymore
+func DeleteCustomGameShareToken(c *gin.Context) {
+	var req DeleteCustomGameShareTokenRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	uid := c.GetString("uid")
+
+	// check if user is editor of custom game
+	permitted, err := service.CheckPermissionForCustomGameConfig(db.Root, req.CustomGameConfigId, uid)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !permitted {
+		util.AbortWithStrJson(c, http.StatusForbidden, "user is not editor of custom game")
+		return
+	}
+
+	shareTokenDAO, exists, err := models.GetCustomGameShareTokenDAO_byToken(db.Root, req.Token)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !exists || shareTokenDAO.CustomGameConfigId != req.CustomGameConfigId {
+		util.AbortWithStrJson(c, http.StatusNotFound, "share token not found")
+		return
+	}
+
+	if err := shareTokenDAO.Delete(db.Root); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// GetCustomGameSharedView returns read-only view of teams & balance, accessible without login
+func GetCustomGameSharedView(c *gin.Context) {
+	var req GetCustomGameSharedViewRequestDto
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	sharedViewVO, err := service.GetCustomGameSharedViewVO(db.Root, req.Token)
+	if err != nil {
+		if errors.Is(err, service.ErrCustomGameShareTokenNotFound) {
+			util.AbortWithErrJson(c, http.StatusNotFound, err)
+			return
+		}
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, GetCustomGameSharedViewResponseDto(*sharedViewVO))
+}
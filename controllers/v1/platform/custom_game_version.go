@@ -0,0 +1,73 @@
+package platform
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/shyunku-libraries/go-logger"
+	"net/http"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/service"
+	"team.gg-server/util"
+)
+
+// increaseCustomGameVersion increases version of configuration which request is based on.
+// expectedVersion is version field of request dto, every change request carries version of configuration client has loaded.
+// aborts request and returns false on failure (caller should rollback transaction),
+// responds 409 with current configuration if request is based on stale version, or 409 while configuration is being drafted.
+func increaseCustomGameVersion(c *gin.Context, tx db.Context, configId string, expectedVersion *int) (int, bool) {
+	// configuration is locked while drafting, drafted teams are applied on version draft started with
+	if service.IsCustomGameDrafting(configId) {
+		util.AbortWithErrJson(c, http.StatusConflict, service.ErrCustomGameDraftAlreadyRunning)
+		return 0, false
+	}
+	version, err := service.IncreaseCustomGameVersion(tx, configId, expectedVersion)
+	if err != nil {
+		if errors.Is(err, service.ErrCustomGameVersionMismatch) {
+			abortWithCustomGameVersionConflict(c, configId)
+			return 0, false
+		}
+		if errors.Is(err, service.ErrCustomGameConfigurationNotExists) {
+			util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
+			return 0, false
+		}
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return 0, false
+	}
+	return version, true
+}
+
+// abortWithCustomGameVersionConflict responds current configuration, so that client can rebase its change on it
+func abortWithCustomGameVersionConflict(c *gin.Context, configId string) {
+	configVO, err := service.GetCustomGameConfigurationVO(configId)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+		"error":   service.ErrCustomGameVersionMismatch.Error(),
+		"current": configVO,
+	})
+}
+
+// checkCustomGameVersion checks request is based on current version of configuration without increasing it.
+// aborts request and returns false on failure.
+func checkCustomGameVersion(c *gin.Context, configId string, expectedVersion *int) bool {
+	configDAO, exists, err := models.GetCustomGameDAO_byId(db.Root, configId)
+	if err != nil {
+		log.Error(err)
+		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		return false
+	}
+	if !exists {
+		util.AbortWithStrJson(c, http.StatusNotFound, "custom game configuration not found")
+		return false
+	}
+	if expectedVersion == nil || configDAO.Version != *expectedVersion {
+		abortWithCustomGameVersionConflict(c, configId)
+		return false
+	}
+	return true
+}
@@ -1,14 +1,17 @@
 package v1
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	log "github.com/shyunku-libraries/go-logger"
 	"net/http"
+	util2 "team.gg-server/controllers/util"
 	api2 "team.gg-server/controllers/v1/api"
 	"team.gg-server/controllers/v1/platform"
 	"team.gg-server/libs/db"
 	"team.gg-server/models"
 	"team.gg-server/service"
+	"team.gg-server/third_party/riot"
 	"team.gg-server/third_party/riot/api"
 	"team.gg-server/types"
 	"team.gg-server/util"
@@ -39,7 +42,13 @@ func GetSummonerInfo(c *gin.Context) {
 		return
 	}
 
-	tagLine := "KR1"
+	platform, ok := util2.ParsePlatform(req.Region)
+	if !ok {
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid region")
+		return
+	}
+
+	tagLine := riot.GetDefaultTagLine(platform)
 	if req.TagLine != nil {
 		tagLine = *req.TagLine
 	}
@@ -49,7 +58,7 @@ func GetSummonerInfo(c *gin.Context) {
 		return
 	}
 
-	summonerDAO, exists, err := models.GetSummonerDAO_byNameTag(db.Root, req.GameName, tagLine)
+	summonerDAO, exists, err := models.GetSummonerDAO_byNameTag(db.Root, platform, req.GameName, tagLine)
 	if err != nil {
 		log.Error(err)
 		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
@@ -64,21 +73,16 @@ func GetSummonerInfo(c *gin.Context) {
 			return
 		}
 
-		account, status, err := api.GetAccountByRiotId(req.GameName, tagLine)
+		account, err := api.GetAccountByRiotId(c, platform, req.GameName, tagLine)
 		if err != nil {
-			if status == http.StatusNotFound {
-				util.AbortWithStrJson(c, http.StatusNotFound, "invalid game name: "+req.GameName+" "+tagLine)
-				return
-			}
-			log.Error(err)
-			util.AbortWithStrJson(c, http.StatusBadRequest, "internal server error")
+			_ = tx.Rollback()
+			util2.AbortWithRiotError(c, err, "invalid game name: "+req.GameName+" "+tagLine)
 			return
 		}
 
-		if err := service.RenewSummonerTotal(tx, account.Puuid); err != nil {
-			log.Error(err)
+		if err := service.RenewSummonerTotal(c, tx, platform, account.Puuid); err != nil {
 			_ = tx.Rollback()
-			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+			util2.AbortWithRiotError(c, err, "summoner not found")
 			return
 		}
 
@@ -90,7 +94,7 @@ func GetSummonerInfo(c *gin.Context) {
 		}
 
 		// retry
-		summonerDAO, exists, err = models.GetSummonerDAO_byNameTag(db.Root, req.GameName, tagLine)
+		summonerDAO, exists, err = models.GetSummonerDAO_byNameTag(db.Root, platform, req.GameName, tagLine)
 		if err != nil {
 			log.Error(err)
 			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
@@ -172,15 +176,16 @@ func GetSummonerInfoByPuuid(c *gin.Context) {
 		return
 	}
 	if !exists {
-		// find summoner by puuid on riot
-		summonerDAO, exists, err = service.RenewSummonerInfoByPuuid(db.Root, req.Puuid)
-		if err != nil {
-			log.Error(err)
-			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		platform, ok := util2.ParsePlatform(req.Region)
+		if !ok {
+			util.AbortWithStrJson(c, http.StatusBadRequest, "invalid region")
 			return
 		}
-		if !exists {
-			util.AbortWithStrJson(c, http.StatusNotFound, "account/summoner not found")
+
+		// find summoner by puuid on riot
+		summonerDAO, err = service.RenewSummonerInfoByPuuid(c, db.Root, platform, req.Puuid)
+		if err != nil {
+			util2.AbortWithRiotError(c, err, "account/summoner not found")
 			return
 		}
 	}
@@ -211,12 +216,17 @@ func GetMatches(c *gin.Context) {
 	}
 
 	if len(matchesVOs) < 20 {
-		if err := service.RenewSummonerMatches(db.Root, req.Puuid, &api.MatchIdsReqOption{
+		platform, err := resolveSummonerPlatform(req.Puuid, req.Region)
+		if err != nil {
+			abortWithPlatformError(c, err)
+			return
+		}
+
+		if err := service.RenewSummonerMatches(c, db.Root, platform, req.Puuid, &api.MatchIdsReqOption{
 			Count:   service.GetInitialMatchCount(),
 			QueueId: queueType,
 		}); err != nil {
-			log.Error(err)
-			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+			util2.AbortWithRiotError(c, err, "summoner not found")
 			return
 		}
 
@@ -268,6 +278,12 @@ func RenewSummonerInfo(c *gin.Context) {
 		return
 	}
 
+	platform, err := resolveSummonerPlatform(req.Puuid, req.Region)
+	if err != nil {
+		abortWithPlatformError(c, err)
+		return
+	}
+
 	tx, err := db.Root.BeginTxx(c, nil)
 	if err != nil {
 		log.Error(err)
@@ -275,10 +291,9 @@ func RenewSummonerInfo(c *gin.Context) {
 		return
 	}
 
-	if err := service.RenewSummonerTotal(tx, req.Puuid); err != nil {
-		log.Error(err)
+	if err := service.RenewSummonerTotal(c, tx, platform, req.Puuid); err != nil {
 		_ = tx.Rollback()
-		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		util2.AbortWithRiotError(c, err, "summoner not found")
 		return
 	}
 
@@ -307,6 +322,12 @@ func LoadMatches(c *gin.Context) {
 		queueId = *req.QueueId
 	}
 
+	platform, err := resolveSummonerPlatform(req.Puuid, req.Region)
+	if err != nil {
+		abortWithPlatformError(c, err)
+		return
+	}
+
 	_, exists, err := models.GetOldestSummonerMatchDAO(db.Root, req.Puuid)
 	if err != nil {
 		log.Error(err)
@@ -315,23 +336,21 @@ func LoadMatches(c *gin.Context) {
 	}
 	if !exists {
 		// just renew matches (recent)
-		if err := service.RenewSummonerMatches(db.Root, req.Puuid, &api.MatchIdsReqOption{
+		if err := service.RenewSummonerMatches(c, db.Root, platform, req.Puuid, &api.MatchIdsReqOption{
 			QueueId: queueId,
 		}); err != nil {
-			log.Error(err)
-			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+			util2.AbortWithRiotError(c, err, "summoner not found")
 			return
 		}
 	} else {
 		// renew matches (before requested time)
 		beforeTime := time.UnixMilli(*req.Before)
-		if err := service.RenewSummonerMatches(db.Root, req.Puuid, &api.MatchIdsReqOption{
+		if err := service.RenewSummonerMatches(c, db.Root, platform, req.Puuid, &api.MatchIdsReqOption{
 			QueueId: queueId,
 			Count:   service.GetLoadMoreMatchCount(),
 			EndTime: &beforeTime,
 		}); err != nil {
-			log.Error(err)
-			util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+			util2.AbortWithRiotError(c, err, "summoner not found")
 			return
 		}
 	}
@@ -370,14 +389,9 @@ func GetIngameInfo(c *gin.Context) {
 		return
 	}
 
-	spectatorInfo, status, err := api.GetSpectatorInfo(summonerDAO.Id)
+	spectatorInfo, err := api.GetSpectatorInfo(c, summonerDAO.Platform, summonerDAO.Id)
 	if err != nil {
-		if status == http.StatusNotFound {
-			util.AbortWithStrJson(c, http.StatusNotFound, "not in game")
-			return
-		}
-		log.Error(err)
-		util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+		util2.AbortWithRiotError(c, err, "not in game")
 		return
 	}
 
@@ -403,3 +417,33 @@ func GetIngameInfo(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+var errInvalidRegion = errors.New("invalid region")
+
+// resolveSummonerPlatform returns platform of summoner stored in db, or requested region if not stored yet.
+// returns errInvalidRegion if region is needed but invalid.
+func resolveSummonerPlatform(puuid string, region string) (string, error) {
+	summonerDAO, exists, err := models.GetSummonerDAO_byPuuid(db.Root, puuid)
+	if err != nil {
+		return "", err
+	}
+	if exists && summonerDAO.Platform != "" {
+		return summonerDAO.Platform, nil
+	}
+
+	platform, ok := util2.ParsePlatform(region)
+	if !ok {
+		return "", errInvalidRegion
+	}
+	return platform, nil
+}
+
+// abortWithPlatformError responds 400 for invalid region, 500 for others
+func abortWithPlatformError(c *gin.Context, err error) {
+	if errors.Is(err, errInvalidRegion) {
+		util.AbortWithStrJson(c, http.StatusBadRequest, "invalid region")
+		return
+	}
+	log.Error(err)
+	util.AbortWithStrJson(c, http.StatusInternalServerError, "internal server error")
+}
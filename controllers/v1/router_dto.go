@@ -20,6 +20,7 @@ type SignupRequestDto struct {
 type GetSummonerInfoRequestDto struct {
 	GameName string  `form:"gameName" binding:"required"`
 	TagLine  *string `form:"tagLine" binding:"required"`
+	Region   string  `form:"region"`
 }
 
 type GetSummonerInfoResponseDto struct {
@@ -32,12 +33,14 @@ type GetSummonerInfoResponseDto struct {
 }
 
 type GetSummonerInfoByPuuidRequestDto struct {
-	Puuid string `form:"puuid" binding:"required"`
+	Puuid  string `form:"puuid" binding:"required"`
+	Region string `form:"region"`
 }
 
 type GetMatchesRequestDto struct {
 	Puuid   string `form:"puuid" binding:"required"`
 	QueueId *int   `form:"queueId" binding:"required"`
+	Region  string `form:"region"`
 }
 
 type QuickSearchSummonerRequestDto struct {
@@ -47,13 +50,15 @@ type QuickSearchSummonerRequestDto struct {
 type QuickSearchSummonerResponseDto []service.SummonerSummaryVO
 
 type RenewSummonerInfoRequestDto struct {
-	Puuid string `json:"puuid" binding:"required"`
+	Puuid  string `json:"puuid" binding:"required"`
+	Region string `json:"region"`
 }
 
 type LoadMatchesRequestDto struct {
 	Puuid   string `json:"puuid" binding:"required"`
 	Before  *int64 `json:"before" binding:"required"`
 	QueueId *int   `json:"queueId" binding:"required"`
+	Region  string `json:"region"`
 }
 
 type LoadMatchesResponseDto []service.MatchSummaryVO
@@ -1,12 +1,12 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
-	log "github.com/shyunku-libraries/go-logger"
 	"os"
 )
 
@@ -16,35 +16,9 @@ type Database struct {
 	*sqlx.DB
 }
 
-type DatabaseProcess struct {
-	Id      string  `db:"Id"`
-	User    *string `db:"User"`
-	Host    *string `db:"Host"`
-	Db      *string `db:"db"`
-	Command *string `db:"Command"`
-	Time    *string `db:"Time"`
-	State   *string `db:"State"`
-	Info    *string `db:"Info"`
-}
-
+// Finalize closes database handle.
+// background workers should be stopped (and their transactions done) before this.
 func (d *Database) Finalize() error {
-	// get all process
-	var processList []DatabaseProcess
-	if err := d.Select(&processList, "SHOW PROCESSLIST"); err != nil {
-		if !errors.Is(err, sql.ErrNoRows) {
-			return err
-		}
-	}
-	for _, process := range processList {
-		if process.Db != nil && *process.Db == "teamgg" {
-			_, _ = d.Exec(fmt.Sprintf("KILL %s", process.Id))
-			query := "???"
-			if process.Info != nil {
-				query = *process.Info
-			}
-			log.Debugf("Killed process: %s --- %s", process.Id, query)
-		}
-	}
 	return d.Close()
 }
 
@@ -130,6 +104,9 @@ type Context interface {
 	Exec(query string, args ...interface{}) (sql.Result, error)
 	Get(dest interface{}, query string, args ...interface{}) error
 	Select(dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 	Rebind(query string) string
 }
 
@@ -138,3 +115,9 @@ type TxContext interface {
 	Commit() error
 	Rollback() error
 }
+
+// IsDuplicateEntryError tells if err is violation of primary key or unique constraint
+func IsDuplicateEntryError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
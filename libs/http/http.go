@@ -28,6 +28,7 @@ type Response struct {
 	StatusCode int
 	Body       []byte
 	Stream     io.ReadCloser
+	Header     http.Header
 	Err        error
 
 	ContentLength int64
@@ -51,6 +52,7 @@ func Get(req GetRequest) (Response, error) {
 	}
 
 	respBody.StatusCode = resp.StatusCode
+	respBody.Header = resp.Header
 	respBody.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
 	respBody.ContentLength = resp.ContentLength
 	if respBody.Success {
@@ -79,6 +81,7 @@ func StreamGet(req GetRequest) (Response, error) {
 	}
 
 	respBody.StatusCode = resp.StatusCode
+	respBody.Header = resp.Header
 	respBody.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
 	respBody.ContentLength = resp.ContentLength
 	if respBody.Success {
@@ -127,6 +130,7 @@ func Post(req PostRequest) (Response, error) {
 	}
 
 	respBody.StatusCode = resp.StatusCode
+	respBody.Header = resp.Header
 	respBody.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
 	respBody.ContentLength = resp.ContentLength
 	if respBody.Success {
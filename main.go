@@ -39,6 +39,7 @@ func main() {
 	// Create Cancel Context
 	ctx, cancel := context.WithCancel(context.Background())
 	var waitGroup sync.WaitGroup
+	service.SetServerContext(ctx, &waitGroup)
 
 	// Load environment variables
 	log.Info("Initializing environments...")
@@ -117,7 +118,8 @@ func main() {
 	// Start data explorer
 	log.Info("Starting data explorer...")
 	de := service.NewDataExplorer()
-	go de.Loop()
+	waitGroup.Add(1)
+	go de.Loop(ctx, &waitGroup)
 
 	// initialize statistics repository
 	log.Info("Initializing statistics repository...")
@@ -125,9 +127,11 @@ func main() {
 
 	// start statistics repository loop
 	log.Info("Starting statistics repository loops...")
-	go statistics.ChampionDetailStatisticsRepo.Loop()
-	go statistics.TierStatisticsRepo.Loop()
-	go statistics.MasteryStatisticsRepo.Loop()
+	waitGroup.Add(4)
+	go statistics.ChampionDetailStatisticsRepo.Loop(ctx, &waitGroup)
+	go statistics.TierStatisticsRepo.Loop(ctx, &waitGroup)
+	go statistics.MasteryStatisticsRepo.Loop(ctx, &waitGroup)
+	go statistics.ChampionPositionShareStatisticsRepo.Loop(ctx, &waitGroup)
 
 	// Run web server with gin
 	waitGroup.Add(1)
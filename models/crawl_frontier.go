@@ -0,0 +1,146 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"team.gg-server/libs/db"
+	"time"
+)
+
+// CrawlFrontierDAO is a summoner waiting to be crawled by data explorer.
+// row remains after crawling, and is crawled again after next_crawl_at (staleness).
+type CrawlFrontierDAO struct {
+	Puuid           string     `db:"puuid" json:"puuid"`
+	Platform        string     `db:"platform" json:"platform"`
+	Priority        int        `db:"priority" json:"priority"`
+	Reason          string     `db:"reason" json:"reason"`
+	CreatedAt       time.Time  `db:"created_at" json:"createdAt"`
+	NextCrawlAt     time.Time  `db:"next_crawl_at" json:"nextCrawlAt"`
+	LockedUntil     *time.Time `db:"locked_until" json:"lockedUntil"`
+	LastAttemptedAt *time.Time `db:"last_attempted_at" json:"lastAttemptedAt"`
+	Attempts        int        `db:"attempts" json:"attempts"`
+	LastError       *string    `db:"last_error" json:"lastError"`
+}
+
+// Enqueue inserts summoner into frontier.
+// if already exists, keeps the higher priority (and its reason & crawl time).
+func (c *CrawlFrontierDAO) Enqueue(db db.Context) error {
+	if _, err := db.Exec(`
+		INSERT INTO crawl_frontier
+		    (puuid, platform, priority, reason, created_at, next_crawl_at, attempts) 
+		VALUES (?, ?, ?, ?, ?, ?, 0)
+		ON DUPLICATE KEY UPDATE
+		    reason = IF(VALUES(priority) > priority, VALUES(reason), reason),
+		    next_crawl_at = IF(VALUES(priority) > priority, LEAST(next_crawl_at, VALUES(next_crawl_at)), next_crawl_at),
+		    priority = GREATEST(priority, VALUES(priority))`,
+		c.Puuid, c.Platform, c.Priority, c.Reason, c.CreatedAt, c.NextCrawlAt,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ClaimNextCrawlFrontierDAO locks the most prior summoner which is due to crawl.
+// should be called in transaction, so that other workers skip locked row.
+func ClaimNextCrawlFrontierDAO(db db.Context, now time.Time, lease time.Duration) (*CrawlFrontierDAO, bool, error) {
+	var frontier CrawlFrontierDAO
+	if err := db.Get(&frontier, `
+		SELECT * FROM crawl_frontier
+		WHERE next_crawl_at <= ? AND (locked_until IS NULL OR locked_until < ?)
+		ORDER BY priority DESC, next_crawl_at ASC
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`, now, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	lockedUntil := now.Add(lease)
+	frontier.LockedUntil = &lockedUntil
+	frontier.LastAttemptedAt = &now
+	frontier.Attempts++
+	if _, err := db.Exec(`
+		UPDATE crawl_frontier SET locked_until = ?, last_attempted_at = ?, attempts = ? 
+		WHERE puuid = ?`,
+		frontier.LockedUntil, frontier.LastAttemptedAt, frontier.Attempts, frontier.Puuid,
+	); err != nil {
+		return nil, false, err
+	}
+	return &frontier, true, nil
+}
+
+// MarkCrawlFrontierSucceeded releases lock and schedules re-crawl (staleness)
+func MarkCrawlFrontierSucceeded(db db.Context, puuid string, nextCrawlAt time.Time) error {
+	if _, err := db.Exec(`
+		UPDATE crawl_frontier SET locked_until = NULL, attempts = 0, last_error = NULL, next_crawl_at = ? 
+		WHERE puuid = ?`, nextCrawlAt, puuid); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarkCrawlFrontierFailed releases lock and schedules retry (backoff)
+func MarkCrawlFrontierFailed(db db.Context, puuid string, lastError string, nextCrawlAt time.Time) error {
+	if _, err := db.Exec(`
+		UPDATE crawl_frontier SET locked_until = NULL, last_error = ?, next_crawl_at = ? 
+		WHERE puuid = ?`, lastError, nextCrawlAt, puuid); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReleaseCrawlFrontier releases lock without counting attempt (e.g. crawling is stopped by shutdown)
+func ReleaseCrawlFrontier(db db.Context, puuid string) error {
+	if _, err := db.Exec(`
+		UPDATE crawl_frontier SET locked_until = NULL, attempts = GREATEST(attempts - 1, 0) 
+		WHERE puuid = ?`, puuid); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetDueCrawlFrontierCount(db db.Context, now time.Time) (int, error) {
+	var count int
+	if err := db.Get(&count, `
+		SELECT COUNT(*) FROM crawl_frontier 
+		WHERE next_crawl_at <= ? AND (locked_until IS NULL OR locked_until < ?)`, now, now); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// EnqueueCrawlFrontiers_byMatchParticipants enqueues unknown participants of matches of given summoner
+func EnqueueCrawlFrontiers_byMatchParticipants(db db.Context, puuid, platform string, priority int, reason string, now time.Time) error {
+	if _, err := db.Exec(`
+		INSERT IGNORE INTO crawl_frontier
+		    (puuid, platform, priority, reason, created_at, next_crawl_at, attempts)
+		SELECT DISTINCT mp.puuid, ?, ?, ?, ?, ?, 0
+		FROM summoner_matches sm
+		JOIN match_participants mp ON sm.match_id = mp.match_id
+		LEFT JOIN summoners s ON mp.puuid = s.puuid
+		WHERE sm.puuid = ? AND s.puuid IS NULL`,
+		platform, priority, reason, now, now, puuid,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+// EnqueueCrawlFrontiers_byStaleSummoners enqueues known summoners (not in frontier) which are not updated for a while
+func EnqueueCrawlFrontiers_byStaleSummoners(db db.Context, staleBefore time.Time, priority int, reason string, now time.Time, limit int) error {
+	if _, err := db.Exec(`
+		INSERT IGNORE INTO crawl_frontier
+		    (puuid, platform, priority, reason, created_at, next_crawl_at, attempts)
+		SELECT s.puuid, s.platform, ?, ?, ?, ?, 0
+		FROM summoners s
+		LEFT JOIN crawl_frontier cf ON s.puuid = cf.puuid
+		WHERE s.last_updated_at < ? AND cf.puuid IS NULL
+		ORDER BY s.last_updated_at
+		LIMIT ?`,
+		priority, reason, now, now, staleBefore, limit,
+	); err != nil {
+		return err
+	}
+	return nil
+}
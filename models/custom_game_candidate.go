@@ -16,15 +16,19 @@ type CustomGameCandidateDAO struct {
 	FlavorMid          int     `db:"flavor_mid" json:"flavorMid"`
 	FlavorAdc          int     `db:"flavor_adc" json:"flavorAdc"`
 	FlavorSupport      int     `db:"flavor_support" json:"flavorSupport"`
+	MustPlay           bool    `db:"must_play" json:"mustPlay"`
+	BenchStreak        int     `db:"bench_streak" json:"benchStreak"` // consecutive rounds benched
+	PlayedCount        int     `db:"played_count" json:"playedCount"`
 }
 
 func (c *CustomGameCandidateDAO) Upsert(db db.Context) error {
 	if _, err := db.Exec(`
 	INSERT INTO custom_game_candidates (
 		custom_game_config_id, puuid, custom_tier, custom_rank, 
-		flavor_top, flavor_jungle, flavor_mid, flavor_adc, flavor_support
+		flavor_top, flavor_jungle, flavor_mid, flavor_adc, flavor_support,
+		must_play, bench_streak, played_count
 	) VALUES (
-		?, ?, ?, ?, ?, ?, ?, ?, ?
+		?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 	) ON DUPLICATE KEY UPDATE
 	    custom_game_config_id = ?,
 		puuid = ?,
@@ -34,11 +38,16 @@ func (c *CustomGameCandidateDAO) Upsert(db db.Context) error {
 		flavor_jungle = ?,
 		flavor_mid = ?,
 		flavor_adc = ?,
-		flavor_support = ?`,
+		flavor_support = ?,
+		must_play = ?,
+		bench_streak = ?,
+		played_count = ?`,
 		c.CustomGameConfigId, c.Puuid, c.CustomTier, c.CustomRank,
 		c.FlavorTop, c.FlavorJungle, c.FlavorMid, c.FlavorAdc, c.FlavorSupport,
+		c.MustPlay, c.BenchStreak, c.PlayedCount,
 		c.CustomGameConfigId, c.Puuid, c.CustomTier, c.CustomRank,
 		c.FlavorTop, c.FlavorJungle, c.FlavorMid, c.FlavorAdc, c.FlavorSupport,
+		c.MustPlay, c.BenchStreak, c.PlayedCount,
 	); err != nil {
 		return err
 	}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"database/sql"
+	"errors"
 	"team.gg-server/libs/db"
 	"time"
 )
@@ -26,6 +28,21 @@ type CustomGameConfigurationDAO struct {
 	MidInfluenceWeight     float64 `db:"mid_influence_weight" json:"midInfluenceWeight"`
 	AdcInfluenceWeight     float64 `db:"adc_influence_weight" json:"adcInfluenceWeight"`
 	SupportInfluenceWeight float64 `db:"support_influence_weight" json:"supportInfluenceWeight"`
+
+	// blend of in-house rating into rating point (0: tier only, 1: in-house rating only)
+	InHouseRatingWeight float64 `db:"in_house_rating_weight" json:"inHouseRatingWeight"`
+
+	// private configuration is visible to members only (shared view is still available by share token)
+	IsPrivate bool `db:"is_private" json:"isPrivate"`
+
+	// increased on every change, not updated by Upsert (see IncreaseCustomGameVersion)
+	Version int `db:"version" json:"version"`
+
+	// fairness model used for balance & optimization (see service.GetCustomGameFairnessModel)
+	FairnessModel string `db:"fairness_model" json:"fairnessModel"`
+
+	// penalty of arrangement where players lack champion pool on their positions (0: ignored)
+	ChampionPoolWeight float64 `db:"champion_pool_weight" json:"championPoolWeight"`
 }
 
 func (c *CustomGameConfigurationDAO) Upsert(db db.Context) error {
@@ -33,9 +50,10 @@ func (c *CustomGameConfigurationDAO) Upsert(db db.Context) error {
 	INSERT INTO custom_game_configurations (
 		id, name, creator_uid, created_at, last_updated_at, is_public, fairness, line_fairness, tier_fairness, line_satisfaction,
 		line_fairness_weight, tier_fairness_weight, line_satisfaction_weight,
-		top_influence_weight, jungle_influence_weight, mid_influence_weight, adc_influence_weight, support_influence_weight
+		top_influence_weight, jungle_influence_weight, mid_influence_weight, adc_influence_weight, support_influence_weight,
+		in_house_rating_weight, is_private, version, fairness_model, champion_pool_weight
 	) VALUES (
-		?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 	) ON DUPLICATE KEY UPDATE
 	    name = ?,
 		last_updated_at = ?,
@@ -51,19 +69,49 @@ func (c *CustomGameConfigurationDAO) Upsert(db db.Context) error {
 		jungle_influence_weight = ?,
 		mid_influence_weight = ?,
 		adc_influence_weight = ?,
-		support_influence_weight = ?`,
+		support_influence_weight = ?,
+		in_house_rating_weight = ?,
+		is_private = ?,
+		fairness_model = ?,
+		champion_pool_weight = ?`,
 		c.Id, c.Name, c.CreatorUid, c.CreatedAt, c.LastUpdatedAt, c.IsPublic, c.Fairness, c.LineFairness, c.TierFairness, c.LineSatisfaction,
 		c.LineFairnessWeight, c.TierFairnessWeight, c.LineSatisfactionWeight,
 		c.TopInfluenceWeight, c.JungleInfluenceWeight, c.MidInfluenceWeight, c.AdcInfluenceWeight, c.SupportInfluenceWeight,
+		c.InHouseRatingWeight, c.IsPrivate, c.Version, c.FairnessModel, c.ChampionPoolWeight,
 		c.Name, c.LastUpdatedAt, c.IsPublic, c.Fairness, c.LineFairness, c.TierFairness, c.LineSatisfaction,
 		c.LineFairnessWeight, c.TierFairnessWeight, c.LineSatisfactionWeight,
 		c.TopInfluenceWeight, c.JungleInfluenceWeight, c.MidInfluenceWeight, c.AdcInfluenceWeight, c.SupportInfluenceWeight,
+		c.InHouseRatingWeight, c.IsPrivate, c.FairnessModel, c.ChampionPoolWeight,
 	); err != nil {
 		return err
 	}
 	return nil
 }
 
+// IncreaseCustomGameVersion increases version of configuration if it equals to expected version (any version if nil),
+// returns false if version mismatches. row stays locked until transaction ends.
+func IncreaseCustomGameVersion(db db.Context, id string, expectedVersion *int) (bool, error) {
+	var result sql.Result
+	var err error
+	if expectedVersion == nil {
+		result, err = db.Exec(`
+			UPDATE custom_game_configurations SET version = version + 1 WHERE id = ?
+		`, id)
+	} else {
+		result, err = db.Exec(`
+			UPDATE custom_game_configurations SET version = version + 1 WHERE id = ? AND version = ?
+		`, id, *expectedVersion)
+	}
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
 func GetCustomGameDAOs_byCreatorUid(db db.Context, uid string) ([]CustomGameConfigurationDAO, error) {
 	var customGameDAOs []CustomGameConfigurationDAO
 	if err := db.Select(&customGameDAOs, `
@@ -74,11 +122,26 @@ func GetCustomGameDAOs_byCreatorUid(db db.Context, uid string) ([]CustomGameConf
 	return customGameDAOs, nil
 }
 
+// GetCustomGameDAOs_byMemberUid returns configurations user created or joined as member
+func GetCustomGameDAOs_byMemberUid(db db.Context, uid string) ([]CustomGameConfigurationDAO, error) {
+	var customGameDAOs []CustomGameConfigurationDAO
+	if err := db.Select(&customGameDAOs, `
+		SELECT * FROM custom_game_configurations
+		WHERE creator_uid = ? OR id IN (SELECT custom_game_config_id FROM custom_game_members WHERE uid = ?)
+	`, uid, uid); err != nil {
+		return nil, err
+	}
+	return customGameDAOs, nil
+}
+
 func GetCustomGameDAO_byId(db db.Context, id string) (*CustomGameConfigurationDAO, bool, error) {
 	var customGameDAO CustomGameConfigurationDAO
 	if err := db.Get(&customGameDAO, `
 		SELECT * FROM custom_game_configurations WHERE id = ?
 	`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
 		return nil, false, err
 	}
 	return &customGameDAO, true, nil
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"team.gg-server/libs/db"
+	"time"
+)
+
+type CustomGameConstraintDAO struct {
+	Id                 string    `db:"id" json:"id"`
+	CustomGameConfigId string    `db:"custom_game_config_id" json:"customGameConfigId"`
+	Type               string    `db:"type" json:"type"`
+	Puuid              string    `db:"puuid" json:"puuid"`
+	TargetPuuid        *string   `db:"target_puuid" json:"targetPuuid"` // together, apart
+	Team               *int      `db:"team" json:"team"`                // locked team
+	Position           *string   `db:"position" json:"position"`        // locked position, forbidden position
+	CreatedAt          time.Time `db:"created_at" json:"createdAt"`
+}
+
+func (c *CustomGameConstraintDAO) Insert(db db.Context) error {
+	if _, err := db.Exec(`
+	INSERT INTO custom_game_constraints (
+		id, custom_game_config_id, type, puuid, target_puuid, team, position, created_at
+	) VALUES (
+		?, ?, ?, ?, ?, ?, ?, ?
+	)`,
+		c.Id, c.CustomGameConfigId, c.Type, c.Puuid, c.TargetPuuid, c.Team, c.Position, c.CreatedAt,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *CustomGameConstraintDAO) Delete(db db.Context) error {
+	if _, err := db.Exec(`
+		DELETE FROM custom_game_constraints WHERE id = ?
+	`, c.Id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetCustomGameConstraintDAOs_byCustomGameConfigId(db db.Context, customGameConfigId string) ([]CustomGameConstraintDAO, error) {
+	var customGameConstraintsDAO []CustomGameConstraintDAO
+	if err := db.Select(&customGameConstraintsDAO, `
+		SELECT * FROM custom_game_constraints WHERE custom_game_config_id = ? ORDER BY created_at
+	`, customGameConfigId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]CustomGameConstraintDAO, 0), nil
+		}
+		return nil, err
+	}
+	return customGameConstraintsDAO, nil
+}
+
+func GetCustomGameConstraintDAO_byId(db db.Context, customGameConfigId, id string) (*CustomGameConstraintDAO, bool, error) {
+	var customGameConstraintDAO CustomGameConstraintDAO
+	if err := db.Get(&customGameConstraintDAO, `
+		SELECT * FROM custom_game_constraints WHERE custom_game_config_id = ? AND id = ?
+	`, customGameConfigId, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return &customGameConstraintDAO, true, nil
+}
+
+// DeleteCustomGameConstraintDAOs_byPuuid deletes constraints involving player
+func DeleteCustomGameConstraintDAOs_byPuuid(db db.Context, customGameConfigId, puuid string) error {
+	if _, err := db.Exec(`
+		DELETE FROM custom_game_constraints WHERE custom_game_config_id = ? AND (puuid = ? OR target_puuid = ?)
+	`, customGameConfigId, puuid, puuid); err != nil {
+		return err
+	}
+	return nil
+}
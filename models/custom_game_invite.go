@@ -0,0 +1,76 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"team.gg-server/libs/db"
+	"time"
+)
+
+type CustomGameInviteDAO struct {
+	Token              string    `db:"token" json:"token"`
+	CustomGameConfigId string    `db:"custom_game_config_id" json:"customGameConfigId"`
+	Role               string    `db:"role" json:"role"`
+	CreatorUid         string    `db:"creator_uid" json:"creatorUid"`
+	CreatedAt          time.Time `db:"created_at" json:"createdAt"`
+	ExpiresAt          time.Time `db:"expires_at" json:"expiresAt"`
+}
+
+func (i *CustomGameInviteDAO) Insert(db db.Context) error {
+	if _, err := db.Exec(`
+	INSERT INTO custom_game_invites (
+		token, custom_game_config_id, role, creator_uid, created_at, expires_at
+	) VALUES (
+		?, ?, ?, ?, ?, ?
+	)`,
+		i.Token, i.CustomGameConfigId, i.Role, i.CreatorUid, i.CreatedAt, i.ExpiresAt,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i *CustomGameInviteDAO) Delete(db db.Context) error {
+	if _, err := db.Exec(`
+		DELETE FROM custom_game_invites WHERE token = ?
+	`, i.Token); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetCustomGameInviteDAO_byToken(db db.Context, token string) (*CustomGameInviteDAO, bool, error) {
+	var customGameInviteDAO CustomGameInviteDAO
+	if err := db.Get(&customGameInviteDAO, `
+		SELECT * FROM custom_game_invites WHERE token = ?
+	`, token); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return &customGameInviteDAO, true, nil
+}
+
+// GetCustomGameInviteDAOs_byCustomGameConfigId returns invites not expired yet
+func GetCustomGameInviteDAOs_byCustomGameConfigId(db db.Context, customGameConfigId string, now time.Time) ([]CustomGameInviteDAO, error) {
+	var customGameInviteDAOs []CustomGameInviteDAO
+	if err := db.Select(&customGameInviteDAOs, `
+		SELECT * FROM custom_game_invites WHERE custom_game_config_id = ? AND expires_at > ? ORDER BY created_at
+	`, customGameConfigId, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]CustomGameInviteDAO, 0), nil
+		}
+		return nil, err
+	}
+	return customGameInviteDAOs, nil
+}
+
+func DeleteExpiredCustomGameInviteDAOs(db db.Context, now time.Time) error {
+	if _, err := db.Exec(`
+		DELETE FROM custom_game_invites WHERE expires_at <= ?
+	`, now); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"team.gg-server/libs/db"
+	"time"
+)
+
+type CustomGameMemberDAO struct {
+	CustomGameConfigId string    `db:"custom_game_config_id" json:"customGameConfigId"`
+	Uid                string    `db:"uid" json:"uid"`
+	Role               string    `db:"role" json:"role"`
+	JoinedAt           time.Time `db:"joined_at" json:"joinedAt"`
+}
+
+func (m *CustomGameMemberDAO) Upsert(db db.Context) error {
+	if _, err := db.Exec(`
+	INSERT INTO custom_game_members (
+		custom_game_config_id, uid, role, joined_at
+	) VALUES (
+		?, ?, ?, ?
+	) ON DUPLICATE KEY UPDATE
+		role = ?`,
+		m.CustomGameConfigId, m.Uid, m.Role, m.JoinedAt,
+		m.Role,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *CustomGameMemberDAO) Delete(db db.Context) error {
+	if _, err := db.Exec(`
+		DELETE FROM custom_game_members WHERE custom_game_config_id = ? AND uid = ?
+	`, m.CustomGameConfigId, m.Uid); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetCustomGameMemberDAOs_byCustomGameConfigId(db db.Context, customGameConfigId string) ([]CustomGameMemberDAO, error) {
+	var customGameMemberDAOs []CustomGameMemberDAO
+	if err := db.Select(&customGameMemberDAOs, `
+		SELECT * FROM custom_game_members WHERE custom_game_config_id = ? ORDER BY joined_at
+	`, customGameConfigId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]CustomGameMemberDAO, 0), nil
+		}
+		return nil, err
+	}
+	return customGameMemberDAOs, nil
+}
+
+func GetCustomGameMemberDAO(db db.Context, customGameConfigId, uid string) (*CustomGameMemberDAO, bool, error) {
+	var customGameMemberDAO CustomGameMemberDAO
+	if err := db.Get(&customGameMemberDAO, `
+		SELECT * FROM custom_game_members WHERE custom_game_config_id = ? AND uid = ?
+	`, customGameConfigId, uid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return &customGameMemberDAO, true, nil
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"team.gg-server/libs/db"
+	"time"
+)
+
+type CustomGameRatingDAO struct {
+	GroupUid     string     `db:"group_uid" json:"groupUid"`
+	Puuid        string     `db:"puuid" json:"puuid"`
+	Position     string     `db:"position" json:"position"` // ALL for overall rating
+	Rating       float64    `db:"rating" json:"rating"`
+	Deviation    float64    `db:"deviation" json:"deviation"`
+	Volatility   float64    `db:"volatility" json:"volatility"`
+	GameCount    int        `db:"game_count" json:"gameCount"`
+	WinCount     int        `db:"win_count" json:"winCount"`
+	LastPlayedAt *time.Time `db:"last_played_at" json:"lastPlayedAt"`
+}
+
+func (c *CustomGameRatingDAO) Upsert(db db.Context) error {
+	if _, err := db.Exec(`
+	INSERT INTO custom_game_ratings (
+		group_uid, puuid, position, rating, deviation, volatility, game_count, win_count, last_played_at
+	) VALUES (
+		?, ?, ?, ?, ?, ?, ?, ?, ?
+	) ON DUPLICATE KEY UPDATE
+		rating = ?,
+		deviation = ?,
+		volatility = ?,
+		game_count = ?,
+		win_count = ?,
+		last_played_at = ?`,
+		c.GroupUid, c.Puuid, c.Position, c.Rating, c.Deviation, c.Volatility, c.GameCount, c.WinCount, c.LastPlayedAt,
+		c.Rating, c.Deviation, c.Volatility, c.GameCount, c.WinCount, c.LastPlayedAt,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetCustomGameRatingDAOs_byGroupUid(db db.Context, groupUid string) ([]CustomGameRatingDAO, error) {
+	var customGameRatingsDAO []CustomGameRatingDAO
+	if err := db.Select(&customGameRatingsDAO, `
+		SELECT * FROM custom_game_ratings WHERE group_uid = ? ORDER BY rating DESC
+	`, groupUid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]CustomGameRatingDAO, 0), nil
+		}
+		return nil, err
+	}
+	return customGameRatingsDAO, nil
+}
+
+func GetCustomGameRatingDAOs_byPuuid(db db.Context, groupUid, puuid string) ([]CustomGameRatingDAO, error) {
+	var customGameRatingsDAO []CustomGameRatingDAO
+	if err := db.Select(&customGameRatingsDAO, `
+		SELECT * FROM custom_game_ratings WHERE group_uid = ? AND puuid = ?
+	`, groupUid, puuid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]CustomGameRatingDAO, 0), nil
+		}
+		return nil, err
+	}
+	return customGameRatingsDAO, nil
+}
+
+// GetCustomGameRatingDAOs_byCustomGameConfigId returns ratings of player in group of configuration (owner)
+func GetCustomGameRatingDAOs_byCustomGameConfigId(db db.Context, customGameConfigId, puuid string) ([]CustomGameRatingDAO, error) {
+	var customGameRatingsDAO []CustomGameRatingDAO
+	if err := db.Select(&customGameRatingsDAO, `
+		SELECT r.* FROM custom_game_ratings r
+		    JOIN custom_game_configurations c ON c.creator_uid = r.group_uid
+		WHERE c.id = ? AND r.puuid = ?
+	`, customGameConfigId, puuid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]CustomGameRatingDAO, 0), nil
+		}
+		return nil, err
+	}
+	return customGameRatingsDAO, nil
+}
+
+func DeleteCustomGameRatingDAOs_byGroupUid(db db.Context, groupUid string) error {
+	if _, err := db.Exec(`
+		DELETE FROM custom_game_ratings WHERE group_uid = ?
+	`, groupUid); err != nil {
+		return err
+	}
+	return nil
+}
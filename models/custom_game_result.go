@@ -0,0 +1,150 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"team.gg-server/libs/db"
+	"time"
+)
+
+type CustomGameResultDAO struct {
+	Id                 string    `db:"id" json:"id"`
+	CustomGameConfigId *string   `db:"custom_game_config_id" json:"customGameConfigId"` // null if configuration is deleted
+	GroupUid           string    `db:"group_uid" json:"groupUid"`                       // owner of in-house rating
+	Winner             int       `db:"winner" json:"winner"`
+	RiotMatchId        *string   `db:"riot_match_id" json:"riotMatchId"`
+	PlayedAt           time.Time `db:"played_at" json:"playedAt"`
+	CreatedAt          time.Time `db:"created_at" json:"createdAt"`
+}
+
+func (c *CustomGameResultDAO) Insert(db db.Context) error {
+	if _, err := db.Exec(`
+	INSERT INTO custom_game_results (
+		id, custom_game_config_id, group_uid, winner, riot_match_id, played_at, created_at
+	) VALUES (
+		?, ?, ?, ?, ?, ?, ?
+	)`,
+		c.Id, c.CustomGameConfigId, c.GroupUid, c.Winner, c.RiotMatchId, c.PlayedAt, c.CreatedAt,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *CustomGameResultDAO) Delete(db db.Context) error {
+	if _, err := db.Exec(`
+		DELETE FROM custom_game_results WHERE id = ?
+	`, c.Id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetCustomGameResultDAOs_byCustomGameConfigId(db db.Context, customGameConfigId string) ([]CustomGameResultDAO, error) {
+	var customGameResultsDAO []CustomGameResultDAO
+	if err := db.Select(&customGameResultsDAO, `
+		SELECT * FROM custom_game_results WHERE custom_game_config_id = ? ORDER BY played_at DESC, created_at DESC
+	`, customGameConfigId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]CustomGameResultDAO, 0), nil
+		}
+		return nil, err
+	}
+	return customGameResultsDAO, nil
+}
+
+// GetCustomGameResultDAOs_byGroupUid returns results of group in played order
+func GetCustomGameResultDAOs_byGroupUid(db db.Context, groupUid string) ([]CustomGameResultDAO, error) {
+	var customGameResultsDAO []CustomGameResultDAO
+	if err := db.Select(&customGameResultsDAO, `
+		SELECT * FROM custom_game_results WHERE group_uid = ? ORDER BY played_at, created_at
+	`, groupUid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]CustomGameResultDAO, 0), nil
+		}
+		return nil, err
+	}
+	return customGameResultsDAO, nil
+}
+
+func GetCustomGameResultDAO_byId(db db.Context, id string) (*CustomGameResultDAO, bool, error) {
+	var customGameResultDAO CustomGameResultDAO
+	if err := db.Get(&customGameResultDAO, `
+		SELECT * FROM custom_game_results WHERE id = ?
+	`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return &customGameResultDAO, true, nil
+}
+
+func GetCustomGameResultDAO_byRiotMatchId(db db.Context, groupUid, riotMatchId string) (*CustomGameResultDAO, bool, error) {
+	var customGameResultDAO CustomGameResultDAO
+	if err := db.Get(&customGameResultDAO, `
+		SELECT * FROM custom_game_results WHERE group_uid = ? AND riot_match_id = ?
+	`, groupUid, riotMatchId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return &customGameResultDAO, true, nil
+}
+
+type CustomGameResultParticipantDAO struct {
+	ResultId     string  `db:"result_id" json:"resultId"`
+	Puuid        string  `db:"puuid" json:"puuid"`
+	Team         int     `db:"team" json:"team"`
+	Position     string  `db:"position" json:"position"`
+	RatingPoint  float64 `db:"rating_point" json:"ratingPoint"` // representative rating point when played
+	RatingBefore float64 `db:"rating_before" json:"ratingBefore"`
+	RatingAfter  float64 `db:"rating_after" json:"ratingAfter"`
+}
+
+func (c *CustomGameResultParticipantDAO) Upsert(db db.Context) error {
+	if _, err := db.Exec(`
+	INSERT INTO custom_game_result_participants (
+		result_id, puuid, team, position, rating_point, rating_before, rating_after
+	) VALUES (
+		?, ?, ?, ?, ?, ?, ?
+	) ON DUPLICATE KEY UPDATE
+		team = ?,
+		position = ?,
+		rating_point = ?,
+		rating_before = ?,
+		rating_after = ?`,
+		c.ResultId, c.Puuid, c.Team, c.Position, c.RatingPoint, c.RatingBefore, c.RatingAfter,
+		c.Team, c.Position, c.RatingPoint, c.RatingBefore, c.RatingAfter,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetCustomGameResultParticipantDAOs_byResultId(db db.Context, resultId string) ([]CustomGameResultParticipantDAO, error) {
+	var customGameResultParticipantsDAO []CustomGameResultParticipantDAO
+	if err := db.Select(&customGameResultParticipantsDAO, `
+		SELECT * FROM custom_game_result_participants WHERE result_id = ?
+	`, resultId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]CustomGameResultParticipantDAO, 0), nil
+		}
+		return nil, err
+	}
+	return customGameResultParticipantsDAO, nil
+}
+
+func GetLastCustomGameResultDAO_byGroupUid(db db.Context, groupUid string) (*CustomGameResultDAO, bool, error) {
+	var customGameResultDAO CustomGameResultDAO
+	if err := db.Get(&customGameResultDAO, `
+		SELECT * FROM custom_game_results WHERE group_uid = ? ORDER BY played_at DESC, created_at DESC LIMIT 1
+	`, groupUid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return &customGameResultDAO, true, nil
+}
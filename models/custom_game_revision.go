@@ -0,0 +1,121 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"team.gg-server/libs/db"
+	"time"
+)
+
+type CustomGameRevisionDAO struct {
+	Id                 string    `db:"id" json:"id"`
+	CustomGameConfigId string    `db:"custom_game_config_id" json:"customGameConfigId"`
+	Seq                int       `db:"seq" json:"seq"`
+	ActorUid           *string   `db:"actor_uid" json:"actorUid"`
+	Operation          string    `db:"operation" json:"operation"`
+	BeforeState        string    `db:"before_state" json:"beforeState"` // json of arrangement & weights
+	AfterState         string    `db:"after_state" json:"afterState"`
+	Undone             bool      `db:"undone" json:"undone"`
+	CreatedAt          time.Time `db:"created_at" json:"createdAt"`
+}
+
+func (r *CustomGameRevisionDAO) Upsert(db db.Context) error {
+	if _, err := db.Exec(`
+	INSERT INTO custom_game_revisions (
+		id, custom_game_config_id, seq, actor_uid, operation, before_state, after_state, undone, created_at
+	) VALUES (
+		?, ?, ?, ?, ?, ?, ?, ?, ?
+	) ON DUPLICATE KEY UPDATE
+		undone = ?`,
+		r.Id, r.CustomGameConfigId, r.Seq, r.ActorUid, r.Operation, r.BeforeState, r.AfterState, r.Undone, r.CreatedAt,
+		r.Undone,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetCustomGameRevisionDAOs_byCustomGameConfigId returns revisions of configuration, latest first
+func GetCustomGameRevisionDAOs_byCustomGameConfigId(db db.Context, customGameConfigId string) ([]CustomGameRevisionDAO, error) {
+	var customGameRevisionDAOs []CustomGameRevisionDAO
+	if err := db.Select(&customGameRevisionDAOs, `
+		SELECT * FROM custom_game_revisions WHERE custom_game_config_id = ? ORDER BY seq DESC
+	`, customGameConfigId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]CustomGameRevisionDAO, 0), nil
+		}
+		return nil, err
+	}
+	return customGameRevisionDAOs, nil
+}
+
+func GetCustomGameRevisionDAO_byId(db db.Context, customGameConfigId, id string) (*CustomGameRevisionDAO, bool, error) {
+	var customGameRevisionDAO CustomGameRevisionDAO
+	if err := db.Get(&customGameRevisionDAO, `
+		SELECT * FROM custom_game_revisions WHERE custom_game_config_id = ? AND id = ?
+	`, customGameConfigId, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return &customGameRevisionDAO, true, nil
+}
+
+// GetLastAppliedCustomGameRevisionDAO returns latest revision not undone (target of undo)
+func GetLastAppliedCustomGameRevisionDAO(db db.Context, customGameConfigId string) (*CustomGameRevisionDAO, bool, error) {
+	var customGameRevisionDAO CustomGameRevisionDAO
+	if err := db.Get(&customGameRevisionDAO, `
+		SELECT * FROM custom_game_revisions WHERE custom_game_config_id = ? AND undone = false ORDER BY seq DESC LIMIT 1
+	`, customGameConfigId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return &customGameRevisionDAO, true, nil
+}
+
+// GetFirstUndoneCustomGameRevisionDAO returns earliest undone revision (target of redo)
+func GetFirstUndoneCustomGameRevisionDAO(db db.Context, customGameConfigId string) (*CustomGameRevisionDAO, bool, error) {
+	var customGameRevisionDAO CustomGameRevisionDAO
+	if err := db.Get(&customGameRevisionDAO, `
+		SELECT * FROM custom_game_revisions WHERE custom_game_config_id = ? AND undone = true ORDER BY seq LIMIT 1
+	`, customGameConfigId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return &customGameRevisionDAO, true, nil
+}
+
+func GetMaxCustomGameRevisionSeq(db db.Context, customGameConfigId string) (int, error) {
+	var seq sql.NullInt64
+	if err := db.Get(&seq, `
+		SELECT MAX(seq) FROM custom_game_revisions WHERE custom_game_config_id = ?
+	`, customGameConfigId); err != nil {
+		return 0, err
+	}
+	return int(seq.Int64), nil
+}
+
+// DeleteUndoneCustomGameRevisionDAOs discards redo history
+func DeleteUndoneCustomGameRevisionDAOs(db db.Context, customGameConfigId string) error {
+	if _, err := db.Exec(`
+		DELETE FROM custom_game_revisions WHERE custom_game_config_id = ? AND undone = true
+	`, customGameConfigId); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteOldCustomGameRevisionDAOs deletes revisions older than given seq
+func DeleteOldCustomGameRevisionDAOs(db db.Context, customGameConfigId string, seq int) error {
+	if _, err := db.Exec(`
+		DELETE FROM custom_game_revisions WHERE custom_game_config_id = ? AND seq < ?
+	`, customGameConfigId, seq); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"team.gg-server/libs/db"
+	"time"
+)
+
+type CustomGameShareTokenDAO struct {
+	Token              string     `db:"token" json:"token"`
+	CustomGameConfigId string     `db:"custom_game_config_id" json:"customGameConfigId"`
+	CreatorUid         string     `db:"creator_uid" json:"creatorUid"`
+	CreatedAt          time.Time  `db:"created_at" json:"createdAt"`
+	ExpiresAt          *time.Time `db:"expires_at" json:"expiresAt"` // never expires if nil
+}
+
+func (t *CustomGameShareTokenDAO) Insert(db db.Context) error {
+	if _, err := db.Exec(`
+	INSERT INTO custom_game_share_tokens (
+		token, custom_game_config_id, creator_uid, created_at, expires_at
+	) VALUES (
+		?, ?, ?, ?, ?
+	)`,
+		t.Token, t.CustomGameConfigId, t.CreatorUid, t.CreatedAt, t.ExpiresAt,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *CustomGameShareTokenDAO) Delete(db db.Context) error {
+	if _, err := db.Exec(`
+		DELETE FROM custom_game_share_tokens WHERE token = ?
+	`, t.Token); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *CustomGameShareTokenDAO) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !t.ExpiresAt.After(now)
+}
+
+func GetCustomGameShareTokenDAO_byToken(db db.Context, token string) (*CustomGameShareTokenDAO, bool, error) {
+	var customGameShareTokenDAO CustomGameShareTokenDAO
+	if err := db.Get(&customGameShareTokenDAO, `
+		SELECT * FROM custom_game_share_tokens WHERE token = ?
+	`, token); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return &customGameShareTokenDAO, true, nil
+}
+
+func GetCustomGameShareTokenDAOs_byCustomGameConfigId(db db.Context, customGameConfigId string) ([]CustomGameShareTokenDAO, error) {
+	var customGameShareTokenDAOs []CustomGameShareTokenDAO
+	if err := db.Select(&customGameShareTokenDAOs, `
+		SELECT * FROM custom_game_share_tokens WHERE custom_game_config_id = ? ORDER BY created_at
+	`, customGameConfigId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]CustomGameShareTokenDAO, 0), nil
+		}
+		return nil, err
+	}
+	return customGameShareTokenDAOs, nil
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"team.gg-server/libs/db"
+)
+
+// MatchParticipantLaneDiffDAO is gold/xp/cs differential against lane opponent (from match timeline)
+// null if game ended before the minute
+type MatchParticipantLaneDiffDAO struct {
+	MatchParticipantId         string `db:"match_participant_id" json:"matchParticipantId"`
+	MatchId                    string `db:"match_id" json:"matchId"`
+	OpponentMatchParticipantId string `db:"opponent_match_participant_id" json:"opponentMatchParticipantId"`
+
+	GoldDiffAt10 *int `db:"gold_diff_at_10" json:"goldDiffAt10"`
+	GoldDiffAt15 *int `db:"gold_diff_at_15" json:"goldDiffAt15"`
+	XpDiffAt10   *int `db:"xp_diff_at_10" json:"xpDiffAt10"`
+	XpDiffAt15   *int `db:"xp_diff_at_15" json:"xpDiffAt15"`
+	CsDiffAt10   *int `db:"cs_diff_at_10" json:"csDiffAt10"`
+	CsDiffAt15   *int `db:"cs_diff_at_15" json:"csDiffAt15"`
+}
+
+func (m *MatchParticipantLaneDiffDAO) Insert(db db.Context) error {
+	if _, err := db.Exec(`
+		INSERT INTO match_participant_lane_diffs
+		    (match_participant_id, match_id, opponent_match_participant_id, 
+		     gold_diff_at_10, gold_diff_at_15, xp_diff_at_10, xp_diff_at_15, cs_diff_at_10, cs_diff_at_15) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.MatchParticipantId, m.MatchId, m.OpponentMatchParticipantId,
+		m.GoldDiffAt10, m.GoldDiffAt15, m.XpDiffAt10, m.XpDiffAt15, m.CsDiffAt10, m.CsDiffAt15,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetMatchParticipantLaneDiffDAO(db db.Context, matchParticipantId string) (*MatchParticipantLaneDiffDAO, bool, error) {
+	var laneDiff MatchParticipantLaneDiffDAO
+	if err := db.Get(&laneDiff,
+		"SELECT * FROM match_participant_lane_diffs WHERE match_participant_id = ?", matchParticipantId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return &laneDiff, true, nil
+}
+
+func GetMatchParticipantLaneDiffDAOs_byMatchId(db db.Context, matchId string) ([]MatchParticipantLaneDiffDAO, error) {
+	laneDiffs := make([]MatchParticipantLaneDiffDAO, 0)
+	if err := db.Select(&laneDiffs,
+		"SELECT * FROM match_participant_lane_diffs WHERE match_id = ?", matchId); err != nil {
+		return nil, err
+	}
+	return laneDiffs, nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"team.gg-server/libs/db"
+	"time"
+)
+
+// MatchTimelineBacklogDAO is a match saved without its timeline (fetched by user-facing request),
+// timeline is backfilled later by data explorer.
+type MatchTimelineBacklogDAO struct {
+	MatchId   string    `db:"match_id" json:"matchId"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+func (m *MatchTimelineBacklogDAO) Insert(db db.Context) error {
+	if _, err := db.Exec(`
+		INSERT IGNORE INTO match_timeline_backlogs (match_id, created_at) 
+		VALUES (?, ?)`,
+		m.MatchId, m.CreatedAt,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ClaimMatchTimelineBacklogDAOs locks oldest backlogs.
+// should be called in transaction, so that other workers skip locked rows.
+func ClaimMatchTimelineBacklogDAOs(db db.Context, limit int) ([]MatchTimelineBacklogDAO, error) {
+	backlogs := make([]MatchTimelineBacklogDAO, 0)
+	if err := db.Select(&backlogs, `
+		SELECT * FROM match_timeline_backlogs
+		ORDER BY created_at ASC
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED`, limit); err != nil {
+		return nil, err
+	}
+	return backlogs, nil
+}
+
+func DeleteMatchTimelineBacklog(db db.Context, matchId string) error {
+	if _, err := db.Exec(`DELETE FROM match_timeline_backlogs WHERE match_id = ?`, matchId); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"team.gg-server/libs/db"
+)
+
+type MatchTimelineItemPurchaseDAO struct {
+	MatchId       string `db:"match_id" json:"matchId"`
+	ParticipantId int    `db:"participant_id" json:"participantId"`
+	PurchaseOrder int    `db:"purchase_order" json:"purchaseOrder"`
+	ItemId        int    `db:"item_id" json:"itemId"`
+	Timestamp     int64  `db:"timestamp" json:"timestamp"`
+}
+
+func (m *MatchTimelineItemPurchaseDAO) Insert(db db.Context) error {
+	if _, err := db.Exec(`
+		INSERT INTO match_timeline_item_purchases
+		    (match_id, participant_id, purchase_order, item_id, timestamp) 
+		VALUES (?, ?, ?, ?, ?)`,
+		m.MatchId, m.ParticipantId, m.PurchaseOrder, m.ItemId, m.Timestamp,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetMatchTimelineItemPurchaseDAOs_byMatchId(db db.Context, matchId string) ([]MatchTimelineItemPurchaseDAO, error) {
+	purchases := make([]MatchTimelineItemPurchaseDAO, 0)
+	if err := db.Select(&purchases, `
+		SELECT * FROM match_timeline_item_purchases 
+		WHERE match_id = ? 
+		ORDER BY participant_id, purchase_order`, matchId); err != nil {
+		return nil, err
+	}
+	return purchases, nil
+}
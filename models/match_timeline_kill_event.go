@@ -0,0 +1,42 @@
+package models
+
+import (
+	"team.gg-server/libs/db"
+)
+
+type MatchTimelineKillEventDAO struct {
+	MatchId                 string `db:"match_id" json:"matchId"`
+	EventOrder              int    `db:"event_order" json:"eventOrder"`
+	Timestamp               int64  `db:"timestamp" json:"timestamp"`
+	KillerParticipantId     int    `db:"killer_participant_id" json:"killerParticipantId"` // 0 if executed
+	VictimParticipantId     int    `db:"victim_participant_id" json:"victimParticipantId"`
+	AssistingParticipantIds string `db:"assisting_participant_ids" json:"assistingParticipantIds"` // comma separated
+	Bounty                  int    `db:"bounty" json:"bounty"`
+	ShutdownBounty          int    `db:"shutdown_bounty" json:"shutdownBounty"`
+	PositionX               int    `db:"position_x" json:"positionX"`
+	PositionY               int    `db:"position_y" json:"positionY"`
+}
+
+func (m *MatchTimelineKillEventDAO) Insert(db db.Context) error {
+	if _, err := db.Exec(`
+		INSERT INTO match_timeline_kill_events
+		    (match_id, event_order, timestamp, killer_participant_id, victim_participant_id, assisting_participant_ids, bounty, shutdown_bounty, position_x, position_y) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.MatchId, m.EventOrder, m.Timestamp, m.KillerParticipantId, m.VictimParticipantId,
+		m.AssistingParticipantIds, m.Bounty, m.ShutdownBounty, m.PositionX, m.PositionY,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetMatchTimelineKillEventDAOs_byMatchId(db db.Context, matchId string) ([]MatchTimelineKillEventDAO, error) {
+	events := make([]MatchTimelineKillEventDAO, 0)
+	if err := db.Select(&events, `
+		SELECT * FROM match_timeline_kill_events 
+		WHERE match_id = ? 
+		ORDER BY event_order`, matchId); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
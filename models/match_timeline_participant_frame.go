@@ -0,0 +1,43 @@
+package models
+
+import (
+	"team.gg-server/libs/db"
+)
+
+type MatchTimelineParticipantFrameDAO struct {
+	MatchId             string `db:"match_id" json:"matchId"`
+	ParticipantId       int    `db:"participant_id" json:"participantId"`
+	Minute              int    `db:"minute" json:"minute"`
+	CurrentGold         int    `db:"current_gold" json:"currentGold"`
+	TotalGold           int    `db:"total_gold" json:"totalGold"`
+	Xp                  int    `db:"xp" json:"xp"`
+	Level               int    `db:"level" json:"level"`
+	MinionsKilled       int    `db:"minions_killed" json:"minionsKilled"`
+	JungleMinionsKilled int    `db:"jungle_minions_killed" json:"jungleMinionsKilled"`
+	PositionX           int    `db:"position_x" json:"positionX"`
+	PositionY           int    `db:"position_y" json:"positionY"`
+}
+
+func (m *MatchTimelineParticipantFrameDAO) Insert(db db.Context) error {
+	if _, err := db.Exec(`
+		INSERT INTO match_timeline_participant_frames
+		    (match_id, participant_id, minute, current_gold, total_gold, xp, level, minions_killed, jungle_minions_killed, position_x, position_y) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.MatchId, m.ParticipantId, m.Minute, m.CurrentGold, m.TotalGold, m.Xp, m.Level,
+		m.MinionsKilled, m.JungleMinionsKilled, m.PositionX, m.PositionY,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetMatchTimelineParticipantFrameDAOs_byMatchId(db db.Context, matchId string) ([]MatchTimelineParticipantFrameDAO, error) {
+	frames := make([]MatchTimelineParticipantFrameDAO, 0)
+	if err := db.Select(&frames, `
+		SELECT * FROM match_timeline_participant_frames 
+		WHERE match_id = ? 
+		ORDER BY participant_id, minute`, matchId); err != nil {
+		return nil, err
+	}
+	return frames, nil
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"team.gg-server/libs/db"
+)
+
+type MatchTimelineSkillLevelUpDAO struct {
+	MatchId       string `db:"match_id" json:"matchId"`
+	ParticipantId int    `db:"participant_id" json:"participantId"`
+	SkillOrder    int    `db:"skill_order" json:"skillOrder"`
+	SkillSlot     int    `db:"skill_slot" json:"skillSlot"`
+	LevelUpType   string `db:"level_up_type" json:"levelUpType"`
+	Timestamp     int64  `db:"timestamp" json:"timestamp"`
+}
+
+func (m *MatchTimelineSkillLevelUpDAO) Insert(db db.Context) error {
+	if _, err := db.Exec(`
+		INSERT INTO match_timeline_skill_level_ups
+		    (match_id, participant_id, skill_order, skill_slot, level_up_type, timestamp) 
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		m.MatchId, m.ParticipantId, m.SkillOrder, m.SkillSlot, m.LevelUpType, m.Timestamp,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetMatchTimelineSkillLevelUpDAOs_byMatchId(db db.Context, matchId string) ([]MatchTimelineSkillLevelUpDAO, error) {
+	skillLevelUps := make([]MatchTimelineSkillLevelUpDAO, 0)
+	if err := db.Select(&skillLevelUps, `
+		SELECT * FROM match_timeline_skill_level_ups 
+		WHERE match_id = ? 
+		ORDER BY participant_id, skill_order`, matchId); err != nil {
+		return nil, err
+	}
+	return skillLevelUps, nil
+}
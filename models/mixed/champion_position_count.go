@@ -0,0 +1,49 @@
+package mixed
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"team.gg-server/libs/db"
+)
+
+type RecentChampionPositionCountMXDAO struct {
+	Puuid        string `db:"puuid" json:"puuid"`
+	ChampionId   int64  `db:"champion_id" json:"championId"`
+	TeamPosition string `db:"team_position" json:"teamPosition"`
+	Count        int    `db:"count" json:"count"`
+}
+
+// GetRecentChampionPositionCountMXDAOs_byPuuids returns count of picks by champion & team position
+// of each summoner's recent matches (remakes excluded)
+func GetRecentChampionPositionCountMXDAOs_byPuuids(db db.Context, puuids []string, count int) ([]RecentChampionPositionCountMXDAO, error) {
+	if len(puuids) == 0 {
+		return make([]RecentChampionPositionCountMXDAO, 0), nil
+	}
+	var counts []RecentChampionPositionCountMXDAO
+	query, args, err := sqlx.In(`
+		SELECT r.puuid, r.champion_id, r.team_position, COUNT(*) AS count
+		FROM (
+			SELECT mp.puuid, mp.champion_id, mp.team_position,
+				ROW_NUMBER() OVER (PARTITION BY mp.puuid ORDER BY m.game_end_timestamp DESC) AS recent_rank
+			FROM match_participants mp
+			LEFT JOIN matches m ON m.match_id = mp.match_id
+			WHERE mp.puuid IN (?) AND mp.team_position != '' AND mp.game_ended_in_early_surrender = 0
+		) r
+		WHERE r.recent_rank <= ?
+		GROUP BY r.puuid, r.champion_id, r.team_position;
+	`, puuids, count)
+	if err != nil {
+		return nil, err
+	}
+
+	query = db.Rebind(query)
+
+	if err := db.Select(&counts, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]RecentChampionPositionCountMXDAO, 0), nil
+		}
+		return nil, err
+	}
+	return counts, nil
+}
@@ -0,0 +1,29 @@
+package mixed
+
+import (
+	"database/sql"
+	"errors"
+	"team.gg-server/libs/db"
+)
+
+type LeagueTierCountMXDAO struct {
+	Tier  string `db:"tier" json:"tier"`
+	Count int    `db:"count" json:"count"`
+}
+
+// GetLeagueTierCountMXDAOs returns count of summoners (collected) by tier of given queue type
+func GetLeagueTierCountMXDAOs(db db.Context, queueType string) ([]LeagueTierCountMXDAO, error) {
+	var tierCounts []LeagueTierCountMXDAO
+	if err := db.Select(&tierCounts, `
+		SELECT tier, COUNT(*) AS count
+		FROM leagues
+		WHERE queue_type = ?
+		GROUP BY tier;
+	`, queueType); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]LeagueTierCountMXDAO, 0), nil
+		}
+		return nil, err
+	}
+	return tierCounts, nil
+}
@@ -271,3 +271,24 @@ func GetMatchParticipantExtraMXDAOs_byQueueId(puuid string, queueId, count int)
 	}
 	return details, nil
 }
+
+// GetPositionedMatchParticipantExtraMXDAOs returns recent matches having team position (summoner's rift games)
+func GetPositionedMatchParticipantExtraMXDAOs(puuid string, count int) ([]MatchParticipantExtraMXDAO, error) {
+	var details []MatchParticipantExtraMXDAO
+	if err := db.Root.Select(&details, `
+		SELECT m.*, mp.*, mpd.*
+		FROM summoners s
+		LEFT JOIN match_participants mp ON s.puuid = mp.puuid
+		LEFT JOIN match_participant_details mpd ON mp.match_participant_id = mpd.match_participant_id
+		LEFT JOIN matches m on m.match_id = mp.match_id
+		WHERE s.puuid = ? AND mp.team_position != ''
+		ORDER BY m.game_end_timestamp DESC
+		LIMIT ?;
+	`, puuid, count); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]MatchParticipantExtraMXDAO, 0), nil
+		}
+		return nil, err
+	}
+	return details, nil
+}
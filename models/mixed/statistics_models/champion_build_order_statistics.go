@@ -0,0 +1,67 @@
+package statistics_models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"team.gg-server/libs/db"
+)
+
+// ChampionBuildOrderStatisticsMXDAO is first 3 completed items in purchase order (from match timeline)
+type ChampionBuildOrderStatisticsMXDAO struct {
+	ChampionId   int    `db:"champion_id" json:"championId"`
+	TeamPosition string `db:"team_position" json:"teamPosition"`
+	Item0Id      int    `db:"item0_id" json:"item0Id"`
+	Item1Id      int    `db:"item1_id" json:"item1Id"`
+	Item2Id      int    `db:"item2_id" json:"item2Id"`
+	Win          int    `db:"win" json:"win"`
+	Total        int    `db:"total" json:"total"`
+}
+
+func GetChampionBuildOrderStatisticsMXDAOs(ctx context.Context, db db.Context, versions []string) ([]ChampionBuildOrderStatisticsMXDAO, error) {
+	var statistics []ChampionBuildOrderStatisticsMXDAO
+	query, args, err := sqlx.In(`
+		WITH CompletedPurchases AS (
+			SELECT mp.champion_id, mp.team_position, mp.win, tip.match_id, tip.participant_id, tip.item_id,
+				   ROW_NUMBER() OVER (PARTITION BY tip.match_id, tip.participant_id ORDER BY tip.purchase_order) AS build_rank
+			FROM match_timeline_item_purchases tip
+			JOIN match_participants mp ON tip.match_id = mp.match_id AND tip.participant_id = mp.participant_id
+			JOIN matches m ON tip.match_id = m.match_id
+			JOIN static_items si ON tip.item_id = si.id
+			WHERE si.depth >= 3
+			  AND si.gold_purchasable IS TRUE
+			  AND si.required_ally IS NULL
+			  AND mp.team_position != ''
+			  AND m.game_version IN (?)
+		), BuildOrders AS (
+			SELECT champion_id, team_position, win, match_id, participant_id,
+				   MAX(CASE WHEN build_rank = 1 THEN item_id END) AS item0_id,
+				   MAX(CASE WHEN build_rank = 2 THEN item_id END) AS item1_id,
+				   MAX(CASE WHEN build_rank = 3 THEN item_id END) AS item2_id
+			FROM CompletedPurchases
+			WHERE build_rank <= 3
+			GROUP BY champion_id, team_position, win, match_id, participant_id
+			HAVING COUNT(*) = 3
+		)
+		SELECT champion_id, team_position, item0_id, item1_id, item2_id,
+			   SUM(win) AS win,
+			   COUNT(*) AS total
+		FROM BuildOrders
+		GROUP BY champion_id, team_position, item0_id, item1_id, item2_id;
+	`, versions)
+	if err != nil {
+		return nil, err
+	}
+
+	query = db.Rebind(query)
+
+	if err := db.SelectContext(ctx, &statistics, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]ChampionBuildOrderStatisticsMXDAO, 0), nil
+		}
+		return nil, err
+	}
+
+	return statistics, nil
+}
@@ -1,6 +1,7 @@
 package statistics_models
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"github.com/jmoiron/sqlx"
@@ -38,7 +39,7 @@ type ChampionDetailStatisticMXDAO struct {
 	AvgDamageSelfMitigatedPerSec float64 `db:"avg_damage_self_mitigated_per_sec" json:"avgDamageSelfMitigatedPerSec"`
 }
 
-func GetChampionDetailStatisticMXDAOs(db db.Context, versions []string) ([]ChampionDetailStatisticMXDAO, error) {
+func GetChampionDetailStatisticMXDAOs(ctx context.Context, db db.Context, versions []string) ([]ChampionDetailStatisticMXDAO, error) {
 	var statistics []ChampionDetailStatisticMXDAO
 
 	query, args, err := sqlx.In(`
@@ -97,7 +98,7 @@ func GetChampionDetailStatisticMXDAOs(db db.Context, versions []string) ([]Champ
 	}
 
 	query = db.Rebind(query)
-	if err := db.Select(&statistics, query, args...); err != nil {
+	if err := db.SelectContext(ctx, &statistics, query, args...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return make([]ChampionDetailStatisticMXDAO, 0), nil
 		}
@@ -1,6 +1,7 @@
 package statistics_models
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	log "github.com/shyunku-libraries/go-logger"
@@ -9,7 +10,7 @@ import (
 	"team.gg-server/util"
 )
 
-func CreateTemporaryTables(db db.Context, matchGameVersions []string) error {
+func CreateTemporaryTables(ctx context.Context, db db.Context, matchGameVersions []string) error {
 	if len(matchGameVersions) == 0 {
 		return errors.New("match game versions are required")
 	}
@@ -288,7 +289,7 @@ func CreateTemporaryTables(db db.Context, matchGameVersions []string) error {
 
 		timer := util.NewTimer()
 		timer.Start()
-		if _, err := db.Exec(totalSql); err != nil {
+		if _, err := db.ExecContext(ctx, totalSql); err != nil {
 			log.Errorf("error occurred while creating %s", tableName)
 			log.Error(err)
 			return err
@@ -387,9 +388,9 @@ type ChampionDetailStatisticsMetaMXDAO struct {
 	MetaRank int `db:"meta_rank" json:"metaRank"`
 }
 
-func GetChampionDetailStatisticsMetaMXDAOs(db db.Context) ([]ChampionDetailStatisticsMetaMXDAO, error) {
+func GetChampionDetailStatisticsMetaMXDAOs(ctx context.Context, db db.Context) ([]ChampionDetailStatisticsMetaMXDAO, error) {
 	var result []ChampionDetailStatisticsMetaMXDAO
-	if err := db.Select(&result, `
+	if err := db.SelectContext(ctx, &result, `
 		SELECT *
 		FROM FinalRankedMetas
 		WHERE meta_rank <= 15 OR (win_rate > 0.5 AND total >= 50)
@@ -451,9 +452,9 @@ type ChampionCounterStatisticsMXDAO struct {
 	EnemyWinRate    *float64 `db:"enemy_win_rate" json:"enemyWinRate"`
 }
 
-func GetChampionCounterStatisticsMXDAOs(db db.Context) ([]ChampionCounterStatisticsMXDAO, error) {
+func GetChampionCounterStatisticsMXDAOs(ctx context.Context, db db.Context) ([]ChampionCounterStatisticsMXDAO, error) {
 	var result []ChampionCounterStatisticsMXDAO
-	if err := db.Select(&result, `
+	if err := db.SelectContext(ctx, &result, `
 		SELECT cmg.champion_id,
 			   cmg.champion_name,
 			   cmg.team_position,
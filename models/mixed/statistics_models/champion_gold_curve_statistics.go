@@ -0,0 +1,53 @@
+package statistics_models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"team.gg-server/libs/db"
+)
+
+// ChampionGoldCurveStatisticsMXDAO is average gold/xp/cs of each minute (from match timeline)
+type ChampionGoldCurveStatisticsMXDAO struct {
+	ChampionId   int     `db:"champion_id" json:"championId"`
+	TeamPosition string  `db:"team_position" json:"teamPosition"`
+	Minute       int     `db:"minute" json:"minute"`
+	AvgTotalGold float64 `db:"avg_total_gold" json:"avgTotalGold"`
+	AvgXp        float64 `db:"avg_xp" json:"avgXp"`
+	AvgCs        float64 `db:"avg_cs" json:"avgCs"`
+	Total        int     `db:"total" json:"total"`
+}
+
+func GetChampionGoldCurveStatisticsMXDAOs(ctx context.Context, db db.Context, versions []string, maxMinute int) ([]ChampionGoldCurveStatisticsMXDAO, error) {
+	var statistics []ChampionGoldCurveStatisticsMXDAO
+	query, args, err := sqlx.In(`
+		SELECT
+			mp.champion_id,
+			mp.team_position,
+			f.minute,
+			AVG(f.total_gold) AS avg_total_gold,
+			AVG(f.xp) AS avg_xp,
+			AVG(f.minions_killed + f.jungle_minions_killed) AS avg_cs,
+			COUNT(*) AS total
+		FROM match_timeline_participant_frames f
+		JOIN match_participants mp ON f.match_id = mp.match_id AND f.participant_id = mp.participant_id
+		JOIN matches m ON f.match_id = m.match_id
+		WHERE mp.team_position != '' AND m.game_version IN (?) AND f.minute <= ?
+		GROUP BY mp.champion_id, mp.team_position, f.minute;
+	`, versions, maxMinute)
+	if err != nil {
+		return nil, err
+	}
+
+	query = db.Rebind(query)
+
+	if err := db.SelectContext(ctx, &statistics, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]ChampionGoldCurveStatisticsMXDAO, 0), nil
+		}
+		return nil, err
+	}
+
+	return statistics, nil
+}
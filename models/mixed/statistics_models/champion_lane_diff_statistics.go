@@ -0,0 +1,57 @@
+package statistics_models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"team.gg-server/libs/db"
+)
+
+// ChampionLaneDiffStatisticsMXDAO is average gold/xp/cs differential against lane opponent
+type ChampionLaneDiffStatisticsMXDAO struct {
+	ChampionId      int      `db:"champion_id" json:"championId"`
+	TeamPosition    string   `db:"team_position" json:"teamPosition"`
+	AvgGoldDiffAt10 *float64 `db:"avg_gold_diff_at_10" json:"avgGoldDiffAt10"`
+	AvgGoldDiffAt15 *float64 `db:"avg_gold_diff_at_15" json:"avgGoldDiffAt15"`
+	AvgXpDiffAt10   *float64 `db:"avg_xp_diff_at_10" json:"avgXpDiffAt10"`
+	AvgXpDiffAt15   *float64 `db:"avg_xp_diff_at_15" json:"avgXpDiffAt15"`
+	AvgCsDiffAt10   *float64 `db:"avg_cs_diff_at_10" json:"avgCsDiffAt10"`
+	AvgCsDiffAt15   *float64 `db:"avg_cs_diff_at_15" json:"avgCsDiffAt15"`
+	Total           int      `db:"total" json:"total"`
+}
+
+func GetChampionLaneDiffStatisticsMXDAOs(ctx context.Context, db db.Context, versions []string) ([]ChampionLaneDiffStatisticsMXDAO, error) {
+	var statistics []ChampionLaneDiffStatisticsMXDAO
+	query, args, err := sqlx.In(`
+		SELECT
+			mp.champion_id,
+			mp.team_position,
+			AVG(mpld.gold_diff_at_10) AS avg_gold_diff_at_10,
+			AVG(mpld.gold_diff_at_15) AS avg_gold_diff_at_15,
+			AVG(mpld.xp_diff_at_10) AS avg_xp_diff_at_10,
+			AVG(mpld.xp_diff_at_15) AS avg_xp_diff_at_15,
+			AVG(mpld.cs_diff_at_10) AS avg_cs_diff_at_10,
+			AVG(mpld.cs_diff_at_15) AS avg_cs_diff_at_15,
+			COUNT(*) AS total
+		FROM match_participant_lane_diffs mpld
+		JOIN match_participants mp ON mpld.match_participant_id = mp.match_participant_id
+		JOIN matches m ON mpld.match_id = m.match_id
+		WHERE mp.team_position != '' AND m.game_version IN (?)
+		GROUP BY mp.champion_id, mp.team_position;
+	`, versions)
+	if err != nil {
+		return nil, err
+	}
+
+	query = db.Rebind(query)
+
+	if err := db.SelectContext(ctx, &statistics, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]ChampionLaneDiffStatisticsMXDAO, 0), nil
+		}
+		return nil, err
+	}
+
+	return statistics, nil
+}
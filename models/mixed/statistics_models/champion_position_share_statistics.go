@@ -0,0 +1,31 @@
+package statistics_models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"team.gg-server/libs/db"
+)
+
+type ChampionPositionCountMXDAO struct {
+	ChampionId   int64  `db:"champion_id" json:"championId"`
+	TeamPosition string `db:"team_position" json:"teamPosition"`
+	Count        int    `db:"count" json:"count"`
+}
+
+// GetChampionPositionCountMXDAOs returns count of picks by champion & team position of all collected matches
+func GetChampionPositionCountMXDAOs(ctx context.Context, db db.Context) ([]ChampionPositionCountMXDAO, error) {
+	var counts []ChampionPositionCountMXDAO
+	if err := db.SelectContext(ctx, &counts, `
+		SELECT champion_id, team_position, COUNT(*) AS count
+		FROM match_participants
+		WHERE team_position != ''
+		GROUP BY champion_id, team_position;
+	`); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]ChampionPositionCountMXDAO, 0), nil
+		}
+		return nil, err
+	}
+	return counts, nil
+}
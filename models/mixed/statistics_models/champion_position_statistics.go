@@ -1,6 +1,7 @@
 package statistics_models
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"github.com/jmoiron/sqlx"
@@ -14,7 +15,7 @@ type ChampionPositionStatisticsMXDAO struct {
 	Total        int    `db:"total" json:"total"`
 }
 
-func GetChampionPositionStatisticsMXDAOs(db db.Context, versions []string) ([]ChampionPositionStatisticsMXDAO, error) {
+func GetChampionPositionStatisticsMXDAOs(ctx context.Context, db db.Context, versions []string) ([]ChampionPositionStatisticsMXDAO, error) {
 	var statistics []ChampionPositionStatisticsMXDAO
 	query, args, err := sqlx.In(`
 		SELECT
@@ -33,7 +34,7 @@ func GetChampionPositionStatisticsMXDAOs(db db.Context, versions []string) ([]Ch
 
 	query = db.Rebind(query)
 
-	if err := db.Select(&statistics, query, args...); err != nil {
+	if err := db.SelectContext(ctx, &statistics, query, args...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return make([]ChampionPositionStatisticsMXDAO, 0), nil
 		}
@@ -0,0 +1,55 @@
+package statistics_models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"team.gg-server/libs/db"
+	"team.gg-server/types"
+)
+
+// ChampionSkillSequenceStatisticsMXDAO is skill level up sequence of each participant (from match timeline)
+// skill sequence is concatenated skill slots (e.g. "1231114...")
+type ChampionSkillSequenceStatisticsMXDAO struct {
+	ChampionId    int    `db:"champion_id" json:"championId"`
+	TeamPosition  string `db:"team_position" json:"teamPosition"`
+	SkillSequence string `db:"skill_sequence" json:"skillSequence"`
+	Win           int    `db:"win" json:"win"`
+	Total         int    `db:"total" json:"total"`
+}
+
+func GetChampionSkillSequenceStatisticsMXDAOs(ctx context.Context, db db.Context, versions []string) ([]ChampionSkillSequenceStatisticsMXDAO, error) {
+	var statistics []ChampionSkillSequenceStatisticsMXDAO
+	query, args, err := sqlx.In(`
+		SELECT champion_id, team_position, skill_sequence,
+			   SUM(win) AS win,
+			   COUNT(*) AS total
+		FROM (
+			SELECT mp.champion_id, mp.team_position, mp.win,
+				   GROUP_CONCAT(su.skill_slot ORDER BY su.timestamp, su.skill_order SEPARATOR '') AS skill_sequence
+			FROM match_timeline_skill_level_ups su
+			JOIN match_participants mp ON su.match_id = mp.match_id AND su.participant_id = mp.participant_id
+			JOIN matches m ON su.match_id = m.match_id
+			WHERE su.level_up_type = ?
+			  AND mp.team_position != ''
+			  AND m.game_version IN (?)
+			GROUP BY su.match_id, su.participant_id, mp.champion_id, mp.team_position, mp.win
+		) AS skill_sequences
+		GROUP BY champion_id, team_position, skill_sequence;
+	`, types.SkillLevelUpTypeNormal, versions)
+	if err != nil {
+		return nil, err
+	}
+
+	query = db.Rebind(query)
+
+	if err := db.SelectContext(ctx, &statistics, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]ChampionSkillSequenceStatisticsMXDAO, 0), nil
+		}
+		return nil, err
+	}
+
+	return statistics, nil
+}
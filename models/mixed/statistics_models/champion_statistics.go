@@ -1,6 +1,7 @@
 package statistics_models
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"team.gg-server/libs/db"
@@ -21,9 +22,9 @@ type ChampionStatisticMXDAO struct {
 	AvgGoldEarned    float64 `db:"avg_gold_earned" json:"avgGoldEarned"`
 }
 
-func GetChampionStatisticMXDAOs(db db.Context) ([]*ChampionStatisticMXDAO, error) {
+func GetChampionStatisticMXDAOs(ctx context.Context, db db.Context) ([]*ChampionStatisticMXDAO, error) {
 	var statistics []*ChampionStatisticMXDAO
-	if err := db.Select(&statistics, `
+	if err := db.SelectContext(ctx, &statistics, `
 		WITH ChampionStats AS (
 			SELECT
 				mp.champion_id AS champion_id,
@@ -1,6 +1,7 @@
 package statistics_models
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"team.gg-server/libs/db"
@@ -15,9 +16,9 @@ type MasteryStatisticsMXDAO struct {
 	Count         int     `db:"count" json:"count"`
 }
 
-func GetMasteryStatisticsMXDAOs(db db.Context) ([]*MasteryStatisticsMXDAO, error) {
+func GetMasteryStatisticsMXDAOs(ctx context.Context, db db.Context) ([]*MasteryStatisticsMXDAO, error) {
 	var statistics []*MasteryStatisticsMXDAO
-	if err := db.Select(&statistics, `
+	if err := db.SelectContext(ctx, &statistics, `
 		SELECT
 			m.champion_id,
 			MAX(m.champion_points) as max_mastery,
@@ -1,6 +1,7 @@
 package statistics_models
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"team.gg-server/libs/db"
@@ -18,9 +19,9 @@ type MasteryStatisticsTopRankersMXDAO struct {
 	ChampionPoints int `db:"champion_points" json:"championPoints"`
 }
 
-func GetMasteryStatisticsTopRankersMXDAOs(db db.Context, topRanks int) ([]*MasteryStatisticsTopRankersMXDAO, error) {
+func GetMasteryStatisticsTopRankersMXDAOs(ctx context.Context, db db.Context, topRanks int) ([]*MasteryStatisticsTopRankersMXDAO, error) {
 	var topRankers []*MasteryStatisticsTopRankersMXDAO
-	if err := db.Select(&topRankers, `
+	if err := db.SelectContext(ctx, &topRankers, `
 		WITH RankedMasteries AS (
 			SELECT
 			    puuid,
@@ -1,6 +1,7 @@
 package statistics_models
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"team.gg-server/libs/db"
@@ -13,9 +14,9 @@ type TierStatisticsTierCountMXDAO struct {
 	Count      int    `db:"count" json:"count"`
 }
 
-func GetTierStatisticsTierCountMXDAOs(db db.Context) ([]*TierStatisticsTierCountMXDAO, error) {
+func GetTierStatisticsTierCountMXDAOs(ctx context.Context, db db.Context) ([]*TierStatisticsTierCountMXDAO, error) {
 	var tierCounts []*TierStatisticsTierCountMXDAO
-	if err := db.Select(&tierCounts, `
+	if err := db.SelectContext(ctx, &tierCounts, `
 		SELECT l.queue_type, l.tier, l.league_rank, COUNT(*) AS count
 		FROM leagues l
 		LEFT JOIN summoners s ON l.puuid = s.puuid
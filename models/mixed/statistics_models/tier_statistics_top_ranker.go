@@ -1,6 +1,7 @@
 package statistics_models
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"team.gg-server/libs/db"
@@ -22,9 +23,9 @@ type TierStatisticsTopRankersMXDAO struct {
 	Ranks        int `db:"ranks" json:"ranks"`
 }
 
-func GetTierStatisticsTopRankersMXDAOs(db db.Context, topRanks int) ([]*TierStatisticsTopRankersMXDAO, error) {
+func GetTierStatisticsTopRankersMXDAOs(ctx context.Context, db db.Context, topRanks int) ([]*TierStatisticsTopRankersMXDAO, error) {
 	var topRankers []*TierStatisticsTopRankersMXDAO
-	if err := db.Select(&topRankers, `
+	if err := db.SelectContext(ctx, &topRankers, `
 		WITH RankedLeagues AS (
 			SELECT
 				l.queue_type,
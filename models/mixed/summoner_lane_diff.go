@@ -0,0 +1,42 @@
+package mixed
+
+import (
+	"team.gg-server/libs/db"
+)
+
+// SummonerLaneDiffMXDAO is average lane differential of recent matches of summoner
+type SummonerLaneDiffMXDAO struct {
+	AvgGoldDiffAt10 *float64 `db:"avg_gold_diff_at_10" json:"avgGoldDiffAt10"`
+	AvgGoldDiffAt15 *float64 `db:"avg_gold_diff_at_15" json:"avgGoldDiffAt15"`
+	AvgXpDiffAt10   *float64 `db:"avg_xp_diff_at_10" json:"avgXpDiffAt10"`
+	AvgXpDiffAt15   *float64 `db:"avg_xp_diff_at_15" json:"avgXpDiffAt15"`
+	AvgCsDiffAt10   *float64 `db:"avg_cs_diff_at_10" json:"avgCsDiffAt10"`
+	AvgCsDiffAt15   *float64 `db:"avg_cs_diff_at_15" json:"avgCsDiffAt15"`
+	Count           int      `db:"count" json:"count"`
+}
+
+func GetSummonerLaneDiffMXDAO(db db.Context, puuid string, count int) (*SummonerLaneDiffMXDAO, error) {
+	var laneDiff SummonerLaneDiffMXDAO
+	if err := db.Get(&laneDiff, `
+		SELECT
+			AVG(recent.gold_diff_at_10) AS avg_gold_diff_at_10,
+			AVG(recent.gold_diff_at_15) AS avg_gold_diff_at_15,
+			AVG(recent.xp_diff_at_10) AS avg_xp_diff_at_10,
+			AVG(recent.xp_diff_at_15) AS avg_xp_diff_at_15,
+			AVG(recent.cs_diff_at_10) AS avg_cs_diff_at_10,
+			AVG(recent.cs_diff_at_15) AS avg_cs_diff_at_15,
+			COUNT(*) AS count
+		FROM (
+			SELECT mpld.*
+			FROM match_participants mp
+			JOIN match_participant_lane_diffs mpld ON mp.match_participant_id = mpld.match_participant_id
+			JOIN matches m ON m.match_id = mp.match_id
+			WHERE mp.puuid = ?
+			ORDER BY m.game_end_timestamp DESC
+			LIMIT ?
+		) AS recent;
+	`, puuid, count); err != nil {
+		return nil, err
+	}
+	return &laneDiff, nil
+}
@@ -18,6 +18,7 @@ type SummonerDAO struct {
 	Name            string    `db:"name" json:"name"`
 	Id              string    `db:"id" json:"id"`
 	Puuid           string    `db:"puuid" json:"puuid"`
+	Platform        string    `db:"platform" json:"platform"`
 	SummonerLevel   int64     `db:"summoner_level" json:"summonerLevel"`
 	ShortenGameName string    `db:"shorten_game_name" json:"shortenGameName"`
 	ShortenName     string    `db:"shorten_name" json:"shortenName"`
@@ -27,18 +28,18 @@ type SummonerDAO struct {
 func (s *SummonerDAO) Upsert(db db.Context) error {
 	if _, err := db.Exec(`
 		INSERT INTO summoners
-		    (account_id, profile_icon_id, revision_date, game_name, tag_line, name, id, puuid, summoner_level, shorten_game_name, shorten_name, last_updated_at) 
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) 
+		    (account_id, profile_icon_id, revision_date, game_name, tag_line, name, id, puuid, platform, summoner_level, shorten_game_name, shorten_name, last_updated_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) 
 		ON DUPLICATE KEY UPDATE 
 			account_id = ?, profile_icon_id = ?, revision_date = ?, 
 		    game_name = ?, tag_line = ?,
-		    name = ?, id = ?, puuid = ?, summoner_level = ?, 
+		    name = ?, id = ?, puuid = ?, platform = ?, summoner_level = ?, 
 		    shorten_game_name = ?, shorten_name = ?, last_updated_at = ?`,
 		s.AccountId, s.ProfileIconId, s.RevisionDate,
-		s.GameName, s.TagLine, s.Name, s.Id, s.Puuid,
+		s.GameName, s.TagLine, s.Name, s.Id, s.Puuid, s.Platform,
 		s.SummonerLevel, s.ShortenGameName, s.ShortenName, s.LastUpdatedAt,
 		s.AccountId, s.ProfileIconId, s.RevisionDate,
-		s.GameName, s.TagLine, s.Name, s.Id, s.Puuid,
+		s.GameName, s.TagLine, s.Name, s.Id, s.Puuid, s.Platform,
 		s.SummonerLevel, s.ShortenGameName, s.ShortenName, s.LastUpdatedAt,
 	); err != nil {
 		return err
@@ -59,7 +60,7 @@ func GetSummonerDAO_byName(db db.Context, name string) (*SummonerDAO, bool, erro
 	return &summonerEntity, true, nil
 }
 
-func GetSummonerDAO_byNameTag(db db.Context, gameName string, tagLine string) (*SummonerDAO, bool, error) {
+func GetSummonerDAO_byNameTag(db db.Context, platform string, gameName string, tagLine string) (*SummonerDAO, bool, error) {
 	if core.DebugOnProd {
 		defer util.InspectFunctionExecutionTime()()
 	}
@@ -68,8 +69,8 @@ func GetSummonerDAO_byNameTag(db db.Context, gameName string, tagLine string) (*
 	// check if summoner exists in db
 	var summonerEntity SummonerDAO
 	if err := db.Get(&summonerEntity,
-		"SELECT * FROM summoners WHERE shorten_game_name = ? AND tag_line = ?",
-		shortenName, tagLine); err != nil {
+		"SELECT * FROM summoners WHERE platform = ? AND shorten_game_name = ? AND tag_line = ?",
+		platform, shortenName, tagLine); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, false, nil
 		}
@@ -1,17 +1,19 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	log "github.com/shyunku-libraries/go-logger"
-	"math"
-	"net/http"
-	"team.gg-server/controllers/socket"
+	"sort"
+	"strconv"
+	"strings"
 	"team.gg-server/core"
 	"team.gg-server/libs/db"
 	"team.gg-server/models"
+	"team.gg-server/third_party/riot"
 	"team.gg-server/third_party/riot/api"
 	"team.gg-server/types"
 	"team.gg-server/util"
@@ -20,32 +22,32 @@ import (
 
 // RenewSummonerTotal updates summoner info, league, mastery, matches
 // you should use db context with transaction (to prevent inconsistency)
-func RenewSummonerTotal(tx *sqlx.Tx, puuid string) error {
+func RenewSummonerTotal(ctx context.Context, tx *sqlx.Tx, platform, puuid string) error {
 	if core.DebugOnProd {
 		defer util.InspectFunctionExecutionTime()()
 	}
 
 	// update summoner info
-	summonerDAO, _, err := RenewSummonerInfoByPuuid(tx, puuid)
+	summonerDAO, err := RenewSummonerInfoByPuuid(ctx, tx, platform, puuid)
 	if err != nil {
 		log.Error(err)
 		return err
 	}
 
 	// update summoner league
-	if err := RenewSummonerLeague(tx, summonerDAO.Id, summonerDAO.Puuid); err != nil {
+	if err := RenewSummonerLeague(ctx, tx, summonerDAO.Platform, summonerDAO.Id, summonerDAO.Puuid); err != nil {
 		log.Error(err)
 		return err
 	}
 
 	// update summoner mastery
-	if err := RenewSummonerMastery(tx, summonerDAO.Id, summonerDAO.Puuid); err != nil {
+	if err := RenewSummonerMastery(ctx, tx, summonerDAO.Platform, summonerDAO.Id, summonerDAO.Puuid); err != nil {
 		log.Error(err)
 		return err
 	}
 
 	// update summoner recent matches
-	if err := RenewSummonerMatches(tx, summonerDAO.Puuid, nil); err != nil {
+	if err := RenewSummonerMatches(ctx, tx, summonerDAO.Platform, summonerDAO.Puuid, nil); err != nil {
 		log.Error(err)
 		return err
 	}
@@ -53,35 +55,29 @@ func RenewSummonerTotal(tx *sqlx.Tx, puuid string) error {
 	return nil
 }
 
-func RenewSummonerInfoByPuuid(db db.Context, puuid string) (*models.SummonerDAO, bool, error) {
-	summoner, status, err := api.GetSummonerByPuuid(puuid)
+// RenewSummonerInfoByPuuid fetches summoner & account info from riot.
+// returns riot.ErrNotFound (wrapped) if there's no such summoner.
+func RenewSummonerInfoByPuuid(ctx context.Context, db db.Context, platform, puuid string) (*models.SummonerDAO, error) {
+	summoner, err := api.GetSummonerByPuuid(ctx, platform, puuid)
 	if err != nil {
-		log.Error(err)
-		if status == http.StatusNotFound {
-			return nil, false, fmt.Errorf("summoner not found with puuid (%s)", puuid)
-		}
-		return nil, true, err
+		return nil, fmt.Errorf("failed to get summoner by puuid (%s): %w", puuid, err)
 	}
 
-	account, status, err := api.GetAccountByPuuid(puuid)
+	account, err := api.GetAccountByPuuid(ctx, platform, puuid)
 	if err != nil {
-		log.Error(err)
-		if status == http.StatusNotFound {
-			return nil, false, fmt.Errorf("account not found with puuid (%s)", puuid)
-		}
-		return nil, true, err
+		return nil, fmt.Errorf("failed to get account by puuid (%s): %w", puuid, err)
 	}
 
-	summonerDAO, err := renewSummonerInfo(db, summoner, account)
+	summonerDAO, err := renewSummonerInfo(db, platform, summoner, account)
 	if err != nil {
 		log.Error(err)
-		return nil, true, err
+		return nil, err
 	}
 
-	return summonerDAO, true, nil
+	return summonerDAO, nil
 }
 
-func renewSummonerInfo(db db.Context, summoner *api.SummonerDto, account *api.AccountByRiotIdDto) (*models.SummonerDAO, error) {
+func renewSummonerInfo(db db.Context, platform string, summoner *api.SummonerDto, account *api.AccountByRiotIdDto) (*models.SummonerDAO, error) {
 	// make new summoner DAO
 	summonerDao := &models.SummonerDAO{
 		AccountId:       summoner.AccountId,
@@ -92,6 +88,7 @@ func renewSummonerInfo(db db.Context, summoner *api.SummonerDto, account *api.Ac
 		Name:            summoner.Name,
 		Id:              summoner.Id,
 		Puuid:           summoner.Puuid,
+		Platform:        riot.NormalizePlatform(platform),
 		SummonerLevel:   summoner.SummonerLevel,
 		ShortenGameName: util.ShortenSummonerName(account.GameName),
 		ShortenName:     util.ShortenSummonerName(summoner.Name),
@@ -109,8 +106,8 @@ func renewSummonerInfo(db db.Context, summoner *api.SummonerDto, account *api.Ac
 
 // RenewSummonerLeague updates summoner league info
 // this assumes that summoner info is already stored in this context.
-func RenewSummonerLeague(db db.Context, summonerId string, puuid string) error {
-	leagues, err := api.GetLeaguesBySummonerId(summonerId)
+func RenewSummonerLeague(ctx context.Context, db db.Context, platform, summonerId, puuid string) error {
+	leagues, err := api.GetLeaguesBySummonerId(ctx, platform, summonerId)
 	if err != nil {
 		log.Warnf("failed to get league by summoner id (%s) - %s", summonerId, puuid)
 		return err
@@ -152,8 +149,8 @@ func RenewSummonerLeague(db db.Context, summonerId string, puuid string) error {
 	return nil
 }
 
-func RenewSummonerMastery(db db.Context, summonerId string, puuid string) error {
-	masteries, err := api.GetMasteryByPuuid(puuid)
+func RenewSummonerMastery(ctx context.Context, db db.Context, platform, summonerId, puuid string) error {
+	masteries, err := api.GetMasteryByPuuid(ctx, platform, puuid)
 	if err != nil {
 		log.Warnf("failed to get mastery by summoner id (%s)", summonerId)
 		return err
@@ -186,14 +183,14 @@ func RenewSummonerMastery(db db.Context, summonerId string, puuid string) error
 	return nil
 }
 
-func RenewSummonerMatches(db db.Context, puuid string, option *api.MatchIdsReqOption) error {
-	matches, err := api.GetMatchIdsInterval(puuid, option)
+func RenewSummonerMatches(ctx context.Context, db db.Context, platform, puuid string, option *api.MatchIdsReqOption) error {
+	matches, err := api.GetMatchIdsInterval(ctx, platform, puuid, option)
 	if err != nil {
 		log.Warnf("failed to get match ids by puuid (%s)", puuid)
 		return err
 	}
 
-	if err := RenewSummonerMatchesIfNecessary(db, puuid, *matches); err != nil {
+	if err := RenewSummonerMatchesIfNecessary(ctx, db, platform, puuid, *matches); err != nil {
 		log.Error(err)
 		return err
 	}
@@ -206,7 +203,7 @@ type riotSummonerMatchResult struct {
 	err   error
 }
 
-func RenewSummonerMatchesIfNecessary(db db.Context, puuid string, matchIdList []string) error {
+func RenewSummonerMatchesIfNecessary(ctx context.Context, db db.Context, platform, puuid string, matchIdList []string) error {
 	cachedMatchIds := make([]string, 0)
 	uncachedMatchIds := make([]string, 0)
 	for _, matchId := range matchIdList {
@@ -226,12 +223,14 @@ func RenewSummonerMatchesIfNecessary(db db.Context, puuid string, matchIdList []
 		timer := util.NewTimerWithName("summoner_match_renewal")
 		timer.Start()
 
-		promise := util.NewPromise[string, api.MatchDto]()
+		// timelines double riot calls, so user-facing requests leave them to data explorer (see backfillMatchTimelines)
+		withTimeline := riot.PriorityOf(ctx) == riot.PriorityBackground
+		promise := util.NewPromise[string, riotMatchWithTimeline]()
 		for _, matchId := range uncachedMatchIds {
-			promise.Add(fetchSummonerMatchesFromRiot, matchId)
+			promise.Add(fetchSummonerMatchesFromRiot(ctx, platform, withTimeline), matchId)
 		}
 
-		uncachedMatches := make([]api.MatchDto, 0)
+		uncachedMatches := make([]riotMatchWithTimeline, 0)
 		for _, result := range promise.All() {
 			if result.Err != nil {
 				log.Error(result.Err)
@@ -246,10 +245,25 @@ func RenewSummonerMatchesIfNecessary(db db.Context, puuid string, matchIdList []
 		//}
 
 		for _, match := range uncachedMatches {
-			if err := saveMatchToLocalDB(db, puuid, match); err != nil {
+			if err := saveMatchToLocalDB(db, puuid, match.match); err != nil {
 				log.Error(err)
 				return err
 			}
+			if match.timeline != nil {
+				if err := saveMatchTimelineToLocalDB(db, *match.timeline); err != nil {
+					log.Error(err)
+					return err
+				}
+			} else if !withTimeline {
+				backlogDAO := models.MatchTimelineBacklogDAO{
+					MatchId:   match.match.Metadata.MatchId,
+					CreatedAt: time.Now(),
+				}
+				if err := backlogDAO.Insert(db); err != nil {
+					log.Error(err)
+					return err
+				}
+			}
 		}
 	}
 
@@ -263,13 +277,42 @@ func RenewSummonerMatchesIfNecessary(db db.Context, puuid string, matchIdList []
 	return nil
 }
 
-func fetchSummonerMatchesFromRiot(resolve chan<- api.MatchDto, reject chan<- error, matchId string) {
-	match, err := api.GetMatchByMatchId(matchId)
-	if err != nil {
-		log.Error(err)
-		reject <- err
-	} else {
-		resolve <- *match
+type riotMatchWithTimeline struct {
+	match    api.MatchDto
+	timeline *api.MatchTimelineDto
+}
+
+func fetchSummonerMatchesFromRiot(ctx context.Context, platform string, withTimeline bool) util.PromiseFunction[string, riotMatchWithTimeline] {
+	return func(resolve chan<- riotMatchWithTimeline, reject chan<- error, matchId string) {
+		// match id has its own platform prefix (e.g. KR_1234)
+		matchPlatform, ok := riot.GetPlatformByMatchId(matchId)
+		if !ok {
+			matchPlatform = platform
+		}
+		match, err := api.GetMatchByMatchId(ctx, matchPlatform, matchId)
+		if err != nil {
+			log.Error(err)
+			reject <- err
+			return
+		}
+		if !withTimeline {
+			resolve <- riotMatchWithTimeline{match: *match}
+			return
+		}
+
+		// timeline is not provided for some game modes, so match is saved without it only if riot has none
+		timeline, err := api.GetMatchTimelineByMatchId(ctx, matchPlatform, matchId)
+		if err != nil {
+			if !errors.Is(err, riot.ErrNotFound) {
+				log.Error(err)
+				reject <- err
+				return
+			}
+			log.Warnf("match timeline not found (%s)", matchId)
+			timeline = nil
+		}
+
+		resolve <- riotMatchWithTimeline{match: *match, timeline: timeline}
 	}
 }
 
@@ -522,6 +565,190 @@ func saveMatchToLocalDB(db db.Context, puuid string, match api.MatchDto) error {
 	return nil
 }
 
+func saveMatchTimelineToLocalDB(db db.Context, timeline api.MatchTimelineDto) error {
+	matchId := timeline.Metadata.MatchId
+
+	purchases := make(map[int][]models.MatchTimelineItemPurchaseDAO) // key: participant id
+	skillLevelUps := make(map[int][]models.MatchTimelineSkillLevelUpDAO)
+	killOrder := 0
+	for minute, frame := range timeline.Info.Frames {
+		// insert participant frames (1 frame per minute)
+		for _, pf := range frame.ParticipantFrames {
+			frameEntity := models.MatchTimelineParticipantFrameDAO{
+				MatchId:             matchId,
+				ParticipantId:       pf.ParticipantId,
+				Minute:              minute,
+				CurrentGold:         pf.CurrentGold,
+				TotalGold:           pf.TotalGold,
+				Xp:                  pf.Xp,
+				Level:               pf.Level,
+				MinionsKilled:       pf.MinionsKilled,
+				JungleMinionsKilled: pf.JungleMinionsKilled,
+				PositionX:           pf.Position.X,
+				PositionY:           pf.Position.Y,
+			}
+			if err := frameEntity.Insert(db); err != nil {
+				log.Error(err)
+				return err
+			}
+		}
+
+		for _, e := range frame.Events {
+			switch e.Type {
+			case types.TimelineEventItemPurchased:
+				purchases[e.ParticipantId] = append(purchases[e.ParticipantId], models.MatchTimelineItemPurchaseDAO{
+					MatchId:       matchId,
+					ParticipantId: e.ParticipantId,
+					ItemId:        e.ItemId,
+					Timestamp:     e.Timestamp,
+				})
+			case types.TimelineEventItemUndo:
+				// remove last purchase of undone item
+				participantPurchases := purchases[e.ParticipantId]
+				for i := len(participantPurchases) - 1; i >= 0; i-- {
+					if participantPurchases[i].ItemId == e.BeforeId {
+						purchases[e.ParticipantId] = append(participantPurchases[:i], participantPurchases[i+1:]...)
+						break
+					}
+				}
+			case types.TimelineEventSkillLevelUp:
+				skillLevelUps[e.ParticipantId] = append(skillLevelUps[e.ParticipantId], models.MatchTimelineSkillLevelUpDAO{
+					MatchId:       matchId,
+					ParticipantId: e.ParticipantId,
+					SkillSlot:     e.SkillSlot,
+					LevelUpType:   e.LevelUpType,
+					Timestamp:     e.Timestamp,
+				})
+			case types.TimelineEventChampionKill:
+				killOrder++
+				assists := make([]string, 0)
+				for _, assistId := range e.AssistingParticipantIds {
+					assists = append(assists, strconv.Itoa(assistId))
+				}
+				killEventEntity := models.MatchTimelineKillEventDAO{
+					MatchId:                 matchId,
+					EventOrder:              killOrder,
+					Timestamp:               e.Timestamp,
+					KillerParticipantId:     e.KillerId,
+					VictimParticipantId:     e.VictimId,
+					AssistingParticipantIds: strings.Join(assists, ","),
+					Bounty:                  e.Bounty,
+					ShutdownBounty:          e.ShutdownBounty,
+				}
+				if e.Position != nil {
+					killEventEntity.PositionX = e.Position.X
+					killEventEntity.PositionY = e.Position.Y
+				}
+				if err := killEventEntity.Insert(db); err != nil {
+					log.Error(err)
+					return err
+				}
+			}
+		}
+	}
+
+	// insert item purchases (after applying undo)
+	for _, participantPurchases := range purchases {
+		for i, purchase := range participantPurchases {
+			purchase.PurchaseOrder = i + 1
+			if err := purchase.Insert(db); err != nil {
+				log.Error(err)
+				return err
+			}
+		}
+	}
+
+	// insert skill level ups (ordered by time, events may arrive out of order)
+	for _, participantSkillLevelUps := range skillLevelUps {
+		for _, skillLevelUp := range orderSkillLevelUps(participantSkillLevelUps) {
+			if err := skillLevelUp.Insert(db); err != nil {
+				log.Error(err)
+				return err
+			}
+		}
+	}
+
+	if err := saveMatchLaneDiffsToLocalDB(db, timeline); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// orderSkillLevelUps sorts skill level ups of a participant by timestamp (arrival order on tie) and numbers skill order from 1
+func orderSkillLevelUps(skillLevelUps []models.MatchTimelineSkillLevelUpDAO) []models.MatchTimelineSkillLevelUpDAO {
+	sort.SliceStable(skillLevelUps, func(i, j int) bool {
+		return skillLevelUps[i].Timestamp < skillLevelUps[j].Timestamp
+	})
+	for i := range skillLevelUps {
+		skillLevelUps[i].SkillOrder = i + 1
+	}
+	return skillLevelUps
+}
+
+// saveMatchLaneDiffsToLocalDB saves gold/xp/cs differential against lane opponent (same team position of enemy team)
+func saveMatchLaneDiffsToLocalDB(db db.Context, timeline api.MatchTimelineDto) error {
+	matchId := timeline.Metadata.MatchId
+	participants, err := models.GetMatchParticipantDAOs(db, matchId)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	frameAt := func(participantId, minute int) *api.MatchTimelineParticipantFrameDto {
+		if minute >= len(timeline.Info.Frames) {
+			return nil
+		}
+		frame, exists := timeline.Info.Frames[minute].ParticipantFrames[strconv.Itoa(participantId)]
+		if !exists {
+			return nil
+		}
+		return &frame
+	}
+	diffAt := func(participantId, opponentId, minute int) (*int, *int, *int) {
+		frame, opponentFrame := frameAt(participantId, minute), frameAt(opponentId, minute)
+		if frame == nil || opponentFrame == nil {
+			return nil, nil, nil
+		}
+		goldDiff := frame.TotalGold - opponentFrame.TotalGold
+		xpDiff := frame.Xp - opponentFrame.Xp
+		csDiff := (frame.MinionsKilled + frame.JungleMinionsKilled) - (opponentFrame.MinionsKilled + opponentFrame.JungleMinionsKilled)
+		return &goldDiff, &xpDiff, &csDiff
+	}
+
+	for _, participant := range participants {
+		if participant.TeamPosition == "" {
+			continue
+		}
+		for _, opponent := range participants {
+			if opponent.TeamId == participant.TeamId || opponent.TeamPosition != participant.TeamPosition {
+				continue
+			}
+			goldDiffAt10, xpDiffAt10, csDiffAt10 := diffAt(participant.ParticipantId, opponent.ParticipantId, types.LaneDiffEarlyMinute)
+			goldDiffAt15, xpDiffAt15, csDiffAt15 := diffAt(participant.ParticipantId, opponent.ParticipantId, types.LaneDiffLateMinute)
+			laneDiffEntity := models.MatchParticipantLaneDiffDAO{
+				MatchParticipantId:         participant.MatchParticipantId,
+				MatchId:                    matchId,
+				OpponentMatchParticipantId: opponent.MatchParticipantId,
+				GoldDiffAt10:               goldDiffAt10,
+				GoldDiffAt15:               goldDiffAt15,
+				XpDiffAt10:                 xpDiffAt10,
+				XpDiffAt15:                 xpDiffAt15,
+				CsDiffAt10:                 csDiffAt10,
+				CsDiffAt15:                 csDiffAt15,
+			}
+			if err := laneDiffEntity.Insert(db); err != nil {
+				log.Error(err)
+				return err
+			}
+			break
+		}
+	}
+
+	return nil
+}
+
 func renewMatchInLocalDB(db db.Context, puuid string, matchId string) error {
 	// ok, match exists in local db
 	// check if match is connected -> summoner
@@ -562,7 +789,8 @@ func RecalculateCustomGameBalance(db db.Context, configId string) error {
 
 	// calculate fairness
 	weightsVO := CustomGameConfigurationWeightsMixer(*configDAO)
-	fairnessVO, err := calculateCustomGameConfigFairness(participantVOsMap, weightsVO)
+	fairnessModel := GetCustomGameFairnessModel(configDAO.FairnessModel)
+	fairnessVO, err := calculateCustomGameConfigFairness(participantVOsMap, weightsVO, fairnessModel)
 	if err != nil {
 		log.Error(err)
 		return err
@@ -602,15 +830,19 @@ func GetCurrentCustomGameTeamParticipantVOMap(db db.Context, configId string) (m
 		return nil, err
 	}
 
-	participantVOsMap := make(map[string]CustomGameTeamParticipantVO)
+	participantCandidateDAOs := make([]models.CustomGameCandidateDAO, 0, len(participantDAOs))
 	for _, participantDAO := range participantDAOs {
-		candidateDAO := candidatesMap[participantDAO.Puuid]
-		candidateVO, err := GetCustomGameCandidateVO(candidateDAO)
-		if err != nil {
-			return nil, err
-		}
+		participantCandidateDAOs = append(participantCandidateDAOs, candidatesMap[participantDAO.Puuid])
+	}
+	candidateVOs, err := getCustomGameCandidateVOs(db, participantCandidateDAOs)
+	if err != nil {
+		return nil, err
+	}
+
+	participantVOsMap := make(map[string]CustomGameTeamParticipantVO)
+	for i, participantDAO := range participantDAOs {
 		participantVOsMap[participantDAO.Puuid] = CustomGameTeamParticipantVO{
-			CustomGameCandidateVO: *candidateVO,
+			CustomGameCandidateVO: candidateVOs[i],
 			Team:                  participantDAO.Team,
 			Position:              participantDAO.Position,
 		}
@@ -619,329 +851,61 @@ func GetCurrentCustomGameTeamParticipantVOMap(db db.Context, configId string) (m
 	return participantVOsMap, nil
 }
 
-func FindBalancedCustomGameConfig(
-	configId string,
-	originalTeamParticipantMap map[string]CustomGameTeamParticipantVO,
-	weights CustomGameConfigurationWeightsVO,
-	colorMap map[string]int,
-) (*map[string]CustomGameTeamParticipantVO, error) {
-	participants := make([]CustomGameTeamParticipantVO, 0)
-	for _, participant := range originalTeamParticipantMap {
-		participants = append(participants, participant)
+// GetCustomGameCandidatePoolVOMap returns every candidate (team 0 if not arranged) of configuration
+func GetCustomGameCandidatePoolVOMap(db db.Context, configId string) (map[string]CustomGameTeamParticipantVO, error) {
+	candidateDAOs, err := models.GetCustomGameCandidateDAOs_byCustomGameConfigId(db, configId)
+	if err != nil {
+		log.Error(err)
+		return nil, err
 	}
 
-	if len(participants) > 10 {
-		return nil, fmt.Errorf("too many participants (%d)", len(participants))
+	candidateVOs, err := getCustomGameCandidateVOs(db, candidateDAOs)
+	if err != nil {
+		return nil, err
 	}
 
-	possibleTeamPositions := GetPossibleTeamPositions()
-
-	participantCount := len(participants)
-	predictedAllCasesCount := util.Permutation(int64(len(possibleTeamPositions)), int64(participantCount))
-	totalProcessibleCount := predictedAllCasesCount
-
-	getFairness := func(combination []CustomGameTeamPositionVO) (float64, error) {
-		teamParticipantMap := make(map[string]CustomGameTeamParticipantVO)
-		for i, participant := range participants {
-			teamPosition := combination[i]
-			participant.Team = teamPosition.Team
-			participant.Position = teamPosition.Position
-			teamParticipantMap[participant.Summary.Puuid] = participant
+	candidateVOsMap := make(map[string]CustomGameTeamParticipantVO)
+	for i, candidateDAO := range candidateDAOs {
+		candidateVOsMap[candidateDAO.Puuid] = CustomGameTeamParticipantVO{
+			CustomGameCandidateVO: candidateVOs[i],
 		}
-
-		fairnessVO, err := calculateCustomGameConfigFairness(teamParticipantMap, weights)
-		if err != nil {
-			return 0, err
-		}
-
-		return fairnessVO.Fairness, nil
 	}
-
-	// find most balanced team participant map
-	var highestFairness float64 = 0
-	var highestFairnessConfig map[string]CustomGameTeamParticipantVO = nil
-	index := 0
-	var combinate func(arr []CustomGameTeamPositionVO, n int) [][]CustomGameTeamPositionVO
-	combinate = func(arr []CustomGameTeamPositionVO, n int) [][]CustomGameTeamPositionVO {
-		if n == 1 {
-			returnArr := make([][]CustomGameTeamPositionVO, 0)
-			for _, v := range arr {
-				returnArr = append(returnArr, []CustomGameTeamPositionVO{v})
-			}
-			return returnArr
-		}
-
-		result := make([][]CustomGameTeamPositionVO, 0)
-		for i := 0; i < len(arr); i++ {
-			picked := arr[i]
-			// except picked
-			remain := make([]CustomGameTeamPositionVO, 0)
-			for j := 0; j < len(arr); j++ {
-				if i == j {
-					continue
-				}
-				remain = append(remain, arr[j])
-			}
-
-			subCombinations := combinate(remain, n-1)
-			for _, x := range subCombinations {
-				combination := append(x, picked)
-				if len(combination) == participantCount {
-					if index%10000 == 0 {
-						//log.Debugf("combinating... (%d/%d)", index, totalProcessibleCount)
-						socket.SocketIO.BroadcastToCustomConfigRoom(
-							configId,
-							socket.EventCustomConfigOptimizeProcess,
-							socket.CustomConfigOptimizeProcessData{
-								Type:     socket.TypeCustomConfigOptimizeProcessCombinating,
-								Progress: float64(index) / float64(totalProcessibleCount),
-								Current:  int64(index),
-								Total:    totalProcessibleCount,
-							},
-						)
-					}
-					index += 1
-
-					// color code
-					team1ColorMap := make(map[int]int)
-					team2ColorMap := make(map[int]int)
-					for i, participant := range participants {
-						colorCode, exists := colorMap[participant.Summary.Puuid]
-						if !exists || colorCode == 0 {
-							continue
-						}
-						if combination[i].Team == 1 {
-							if _, exists := team1ColorMap[colorCode]; !exists {
-								team1ColorMap[colorCode] = 0
-							}
-							team1ColorMap[colorCode] += 1
-						} else {
-							if _, exists := team2ColorMap[colorCode]; !exists {
-								team2ColorMap[colorCode] = 0
-							}
-							team2ColorMap[colorCode] += 1
-						}
-					}
-
-					colorCodeMatched := true
-					for i := 1; i <= 5; i++ {
-						team1ColorCount, exists1 := team1ColorMap[i]
-						team2ColorCount, exists2 := team2ColorMap[i]
-						if !exists1 {
-							team1ColorCount = 0
-						}
-						if !exists2 {
-							team2ColorCount = 0
-						}
-						if team1ColorCount > 0 && team2ColorCount > 0 {
-							colorCodeMatched = false
-							break
-						}
-					}
-
-					if colorCodeMatched {
-						fairness, err := getFairness(combination)
-						if err != nil {
-							log.Error(err)
-							return nil
-						}
-						if fairness > highestFairness {
-							highestFairness = fairness
-							highestFairnessConfig = make(map[string]CustomGameTeamParticipantVO)
-							for i, participant := range participants {
-								participant.Team = combination[i].Team
-								participant.Position = combination[i].Position
-								highestFairnessConfig[participant.Summary.Puuid] = participant
-							}
-						}
-					}
-				} else {
-					result = append(result, combination)
-				}
-			}
-		}
-		return result
-	}
-
-	// get all possible team participant maps
-	_ = combinate(possibleTeamPositions, participantCount)
-	log.Debugf("found balanced team participant combination")
-
-	if highestFairnessConfig == nil {
-		return nil, fmt.Errorf("failed to find balanced team participant combination")
-	}
-
-	log.Debugf("highest fairness: %.5f", highestFairness)
-	return &highestFairnessConfig, nil
+	return candidateVOsMap, nil
 }
 
-func calculateCustomGameConfigFairness(
-	teamParticipantMap map[string]CustomGameTeamParticipantVO,
-	weights CustomGameConfigurationWeightsVO) (*CustomGameConfigurationBalanceVO, error) {
-	// calculate line score
-	var team1LineScore float64 = 0
-	var team2LineScore float64 = 0
-
-	var team1TierScore float64 = 0
-	var team2TierScore float64 = 0
-
-	// calculate each line score
-	var team1TopScore float64 = 0
-	var team1JungleScore float64 = 0
-	var team1MidScore float64 = 0
-	var team1AdcScore float64 = 0
-	var team1SupportScore float64 = 0
-	var team2TopScore float64 = 0
-	var team2JungleScore float64 = 0
-	var team2MidScore float64 = 0
-	var team2AdcScore float64 = 0
-	var team2SupportScore float64 = 0
-
-	var lineSatisfaction float64 = 0
-
-	favorWeight := func(favor int) float64 {
-		switch favor {
-		case -1:
-			return 0.0
-		case 0:
-			return 1.0
-		case 1:
-			return 2.0
-		case 2:
-			return 4.0
-		default:
-			return 0.0
-		}
-	}
-
-	for _, participant := range teamParticipantMap {
-		//log.Debugf("team %d - %s: %s", participant.Team, participant.Position, participant.Summary.GameName)
-		var score float64 = 0
-		switch participant.Position {
-		case types.PositionTop:
-			lineSatisfaction += favorWeight(participant.PositionFavor.Top)
-			score = favorWeight(participant.PositionFavor.Top) * float64(participant.GetRepresentativeRatingPoint()) * weights.TopInfluence
-			if participant.Team == 1 {
-				team1TopScore = score
-			} else {
-				team2TopScore = score
-			}
-		case types.PositionJungle:
-			lineSatisfaction += favorWeight(participant.PositionFavor.Jungle)
-			score = favorWeight(participant.PositionFavor.Jungle) * float64(participant.GetRepresentativeRatingPoint()) * weights.JungleInfluence
-			if participant.Team == 1 {
-				team1JungleScore = score
-			} else {
-				team2JungleScore = score
-			}
-		case types.PositionMid:
-			lineSatisfaction += favorWeight(participant.PositionFavor.Mid)
-			score = favorWeight(participant.PositionFavor.Mid) * float64(participant.GetRepresentativeRatingPoint()) * weights.MidInfluence
-			if participant.Team == 1 {
-				team1MidScore = score
-			} else {
-				team2MidScore = score
-			}
-		case types.PositionAdc:
-			lineSatisfaction += favorWeight(participant.PositionFavor.Adc)
-			score = favorWeight(participant.PositionFavor.Adc) * float64(participant.GetRepresentativeRatingPoint()) * weights.AdcInfluence
-			if participant.Team == 1 {
-				team1AdcScore = score
-			} else {
-				team2AdcScore = score
-			}
-		case types.PositionSupport:
-			lineSatisfaction += favorWeight(participant.PositionFavor.Support)
-			score = favorWeight(participant.PositionFavor.Support) * float64(participant.GetRepresentativeRatingPoint()) * weights.SupportInfluence
-			if participant.Team == 1 {
-				team1SupportScore = score
-			} else {
-				team2SupportScore = score
-			}
-		}
-
-		if participant.Team == 1 {
-			team1LineScore += score
-			team1TierScore += float64(participant.GetRepresentativeRatingPoint())
-		} else {
-			team2LineScore += score
-			team2TierScore += float64(participant.GetRepresentativeRatingPoint())
-		}
-	}
-
-	// positive: team1 is better
-	topScoreDiff := math.Abs(team1TopScore - team2TopScore)
-	jungleScoreDiff := math.Abs(team1JungleScore - team2JungleScore)
-	midScoreDiff := math.Abs(team1MidScore - team2MidScore)
-	//adcScoreDiff := math.Abs(team1AdcScore - team2AdcScore)
-	//supportScoreDiff := math.Abs(team1SupportScore - team2SupportScore)
-	team1BottomScore := (team1AdcScore*weights.AdcInfluence + team1SupportScore*weights.SupportInfluence) / (weights.AdcInfluence + weights.SupportInfluence)
-	team2BottomScore := (team2AdcScore*weights.AdcInfluence + team2SupportScore*weights.SupportInfluence) / (weights.AdcInfluence + weights.SupportInfluence)
-	bottomScoreDiff := math.Abs(team1BottomScore - team2BottomScore)
-
-	var lineScoreDiffSum float64 = 0
-	lineScoreDiffSum += math.Pow(topScoreDiff, 2.0)
-	lineScoreDiffSum += math.Pow(jungleScoreDiff, 2.0)
-	lineScoreDiffSum += math.Pow(midScoreDiff, 2.0)
-	//lineScoreDiffSum += math.Pow(adcScoreDiff, 2.0)
-	//lineScoreDiffSum += math.Pow(supportScoreDiff, 2.0)
-	lineScoreDiffSum += math.Pow(bottomScoreDiff, 2.0)
-
-	// regularize (0~inf) -> (0~1)
-	var lineFairness float64 = 0
-	lineScoreDiffSum = math.Sqrt(lineScoreDiffSum)
-	if team1LineScore == 0 || team2LineScore == 0 {
-		lineFairness = 0
-	} else {
-		lineFairness = util.LogisticNormalize(lineScoreDiffSum, 700)
-	}
-	//log.Debugf("Line fairness: %.5f, score: %.5f", lineScoreDiffSum, lineFairness)
-
-	// regularize line satisfaction (0~20) * 2 -> (0~1) -> (0~1) (biased to 1)
-	lineSatisfactionScore := math.Sqrt(lineSatisfaction / 40.0)
-	//log.Debugf("Line satisfaction: %.5f, score: %.5f", lineSatisfaction, lineSatisfactionScore)
-
-	// calculate tierFairness
-	var tierFairness float64 = 0
-	tierScoreDiff := math.Abs(team1TierScore - team2TierScore)
-	maxTierScore := math.Max(team1TierScore, team2TierScore)
-	if maxTierScore != 0 {
-		tierScoreDiffRate := tierScoreDiff / maxTierScore
-		if tierScoreDiffRate == 1 {
-			tierFairness = 0
-		} else {
-			scaledDiffRate := util.PolynomialToInfiniteScale(tierScoreDiffRate)
-			tierFairness = util.LogisticNormalize(scaledDiffRate, 0.35)
-		}
+func getPositionFavor(positionFavor CustomGameCandidatePositionFavorVO, position string) int {
+	switch position {
+	case types.PositionTop:
+		return positionFavor.Top
+	case types.PositionJungle:
+		return positionFavor.Jungle
+	case types.PositionMid:
+		return positionFavor.Mid
+	case types.PositionAdc:
+		return positionFavor.Adc
+	case types.PositionSupport:
+		return positionFavor.Support
+	default:
+		return -1
 	}
-
-	totalFairness := lineFairness*weights.LineFairness + tierFairness*weights.TierFairness + lineSatisfactionScore*weights.LineSatisfaction
-
-	//log.Debugf("team1 top: %.5f, jungle: %.5f, mid: %.5f, adc: %.5f, support: %.5f", team1TopScore, team1JungleScore, team1MidScore, team1AdcScore, team1SupportScore)
-	//log.Debugf("team2 top: %.5f, jungle: %.5f, mid: %.5f, adc: %.5f, support: %.5f", team2TopScore, team2JungleScore, team2MidScore, team2AdcScore, team2SupportScore)
-	//log.Debugf("line fairness: %.5f, tier fairness: %.5f, total fairness: %.5f", lineFairness, tierFairness, totalFairness)
-
-	//log.Debugf("line satisfaction: %.5f, line satisfaction score: %.5f", lineSatisfaction, lineSatisfactionScore)
-	return &CustomGameConfigurationBalanceVO{
-		Fairness:         totalFairness,
-		LineFairness:     lineFairness,
-		TierFairness:     tierFairness,
-		LineSatisfaction: lineSatisfactionScore,
-	}, nil
 }
 
+// CheckPermissionForCustomGameConfig checks user can edit configuration (editor or owner)
 func CheckPermissionForCustomGameConfig(db db.Context, configId string, uid string) (bool, error) {
-	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(db, configId)
+	permitted, err := CheckRoleForCustomGameConfig(db, configId, uid, types.CustomGameRoleEditor)
 	if err != nil {
 		log.Error(err)
 		return false, err
 	}
-	if !exists {
-		return false, nil
-	}
-	if customGameConfigurationDAO.CreatorUid != uid {
-		return false, nil
-	}
+	return permitted, nil
+}
 
-	return true, nil
+// CheckOwnershipForCustomGameConfig checks user can manage members and invites of configuration
+func CheckOwnershipForCustomGameConfig(db db.Context, configId string, uid string) (bool, error) {
+	permitted, err := CheckRoleForCustomGameConfig(db, configId, uid, types.CustomGameRoleOwner)
+	if err != nil {
+		log.Error(err)
+		return false, err
+	}
+	return permitted, nil
 }
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"team.gg-server/models"
+	"testing"
+)
+
+func TestOrderSkillLevelUps(t *testing.T) {
+	tests := []struct {
+		name       string
+		timestamps []int64
+		slots      []int
+		wantSlots  []int
+	}{
+		{name: "empty timeline", timestamps: nil, slots: nil, wantSlots: []int{}},
+		{name: "in order", timestamps: []int64{1000, 2000, 3000}, slots: []int{1, 2, 3}, wantSlots: []int{1, 2, 3}},
+		{name: "out of order", timestamps: []int64{3000, 1000, 2000}, slots: []int{2, 1, 3}, wantSlots: []int{1, 3, 2}},
+		{name: "same timestamp keeps arrival order", timestamps: []int64{2000, 1000, 2000}, slots: []int{4, 1, 2}, wantSlots: []int{1, 4, 2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			skillLevelUps := make([]models.MatchTimelineSkillLevelUpDAO, 0, len(test.slots))
+			for i, slot := range test.slots {
+				skillLevelUps = append(skillLevelUps, models.MatchTimelineSkillLevelUpDAO{SkillSlot: slot, Timestamp: test.timestamps[i]})
+			}
+			ordered := orderSkillLevelUps(skillLevelUps)
+			if len(ordered) != len(test.wantSlots) {
+				t.Fatalf("len = %d, want %d", len(ordered), len(test.wantSlots))
+			}
+			for i, skillLevelUp := range ordered {
+				if skillLevelUp.SkillSlot != test.wantSlots[i] {
+					t.Errorf("slot at %d = %d, want %d", i, skillLevelUp.SkillSlot, test.wantSlots[i])
+				}
+				if skillLevelUp.SkillOrder != i+1 {
+					t.Errorf("skill order at %d = %d, want %d", i, skillLevelUp.SkillOrder, i+1)
+				}
+			}
+		})
+	}
+}
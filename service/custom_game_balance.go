@@ -0,0 +1,169 @@
+package service
+
+import (
+	"sort"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/types"
+)
+
+// balance detail explains why current arrangement is (un)fair: estimated win probability, rating difference of each lane,
+// participants unsatisfied with their positions, and single swaps which would improve fairness the most.
+// win probability & lane differences come from win probability model, regardless of fairness model of configuration.
+
+// GetCustomGameBalanceDetailVO returns balance of current arrangement with its breakdown
+func GetCustomGameBalanceDetailVO(db db.Context, configId string) (*CustomGameBalanceDetailVO, error) {
+	configDAO, exists, err := models.GetCustomGameDAO_byId(db, configId)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCustomGameConfigurationNotExists
+	}
+	participantVOsMap, err := GetCurrentCustomGameTeamParticipantVOMap(db, configId)
+	if err != nil {
+		return nil, err
+	}
+	constraintVOs, err := GetCustomGameConstraintVOs(db, configId)
+	if err != nil {
+		return nil, err
+	}
+	colorLabelDAOs, err := models.GetCustomGameParticipantColorLabelDAOs_byCustomGameConfigId(db, configId)
+	if err != nil {
+		return nil, err
+	}
+	colorMap := make(map[string]int)
+	for _, colorLabelDAO := range colorLabelDAOs {
+		if colorLabelDAO.ColorCode == 0 {
+			continue
+		}
+		colorMap[colorLabelDAO.Puuid] = colorLabelDAO.ColorCode
+	}
+
+	weights := CustomGameConfigurationWeightsMixer(*configDAO)
+	model := GetCustomGameFairnessModel(configDAO.FairnessModel)
+	pool := sortedCustomGamePool(participantVOsMap)
+	slots := make([]customGameFairnessSlot, len(pool))
+	pools := make([]CustomGameChampionPoolVO, len(pool))
+	for i, participant := range pool {
+		slots[i] = newCustomGameFairnessSlot(participant, weights)
+		pools[i] = participant.ChampionPool
+	}
+	setCustomGameChampionPoolTable(slots, pools)
+
+	detailVO := CustomGameBalanceDetailVO{
+		CustomGameConfigurationBalanceVO: model.Evaluate(slots, weights),
+		Unsatisfied:                      getCustomGameUnsatisfiedParticipants(pool, model),
+		SwapSuggestions:                  getCustomGameSwapSuggestions(pool, slots, weights, model, colorMap, constraintVOs),
+		ChampionPoolPenalty:              customGameChampionPoolPenalty(slots),
+	}
+
+	winProbabilityModel := winProbabilityCustomGameFairnessModel
+	ratingDiffs, bothTeamsExist := winProbabilityModel.positionRatingDiffs(slots)
+	detailVO.LaneDiffs = CustomGameLaneDiffVO{
+		Top:    ratingDiffs[0],
+		Jungle: ratingDiffs[1],
+		Mid:    ratingDiffs[2],
+	}
+	if bottomInfluence := weights.AdcInfluence + weights.SupportInfluence; bottomInfluence > 0 {
+		detailVO.LaneDiffs.Bottom = (ratingDiffs[3]*weights.AdcInfluence + ratingDiffs[4]*weights.SupportInfluence) / bottomInfluence
+	}
+	if bothTeamsExist {
+		ratingDiff, _ := winProbabilityModel.laneRatingDiff(slots, weights)
+		team1WinProbability := winProbabilityModel.winProbability(ratingDiff)
+		detailVO.Team1WinProbability = &team1WinProbability
+	}
+	return &detailVO, nil
+}
+
+// getCustomGameUnsatisfiedParticipants returns participants not on their favorite position, most unsatisfied first
+func getCustomGameUnsatisfiedParticipants(pool []CustomGameTeamParticipantVO, model FairnessModel) []CustomGameUnsatisfiedParticipantVO {
+	unsatisfied := make([]CustomGameUnsatisfiedParticipantVO, 0)
+	for _, participant := range pool {
+		favor := getPositionFavor(participant.PositionFavor, participant.Position)
+		favoritePosition := participant.Position
+		favoriteFavor := favor
+		for _, position := range GetSupportedPositions {
+			if positionFavor := getPositionFavor(participant.PositionFavor, position); positionFavor > favoriteFavor {
+				favoritePosition = position
+				favoriteFavor = positionFavor
+			}
+		}
+		if favoriteFavor == favor {
+			continue
+		}
+		unsatisfied = append(unsatisfied, CustomGameUnsatisfiedParticipantVO{
+			Puuid:            participant.Summary.Puuid,
+			Team:             participant.Team,
+			Position:         participant.Position,
+			Favor:            favor,
+			FavoritePosition: favoritePosition,
+			FavoriteFavor:    favoriteFavor,
+			SatisfactionLoss: model.FavorWeight(favoriteFavor) - model.FavorWeight(favor),
+		})
+	}
+	sort.SliceStable(unsatisfied, func(i, j int) bool {
+		return unsatisfied[i].SatisfactionLoss > unsatisfied[j].SatisfactionLoss
+	})
+	return unsatisfied
+}
+
+// getCustomGameSwapSuggestions tries every swap of 2 participants' team & position (satisfying constraints & color labels),
+// and returns ones improving fairness the most
+func getCustomGameSwapSuggestions(
+	pool []CustomGameTeamParticipantVO,
+	slots []customGameFairnessSlot,
+	weights CustomGameConfigurationWeightsVO,
+	model FairnessModel,
+	colorMap map[string]int,
+	constraints []CustomGameConstraintVO,
+) []CustomGameSwapSuggestionVO {
+	suggestions := make([]CustomGameSwapSuggestionVO, 0)
+	currentFairness := model.Evaluate(slots, weights).Fairness
+
+	puuids := make([]string, len(pool))
+	for i, participant := range pool {
+		puuids[i] = participant.Summary.Puuid
+	}
+	checker := newCustomGameConstraintChecker(puuids, constraints)
+	teamOf := make([]int, len(pool))
+	swapped := make([]customGameFairnessSlot, len(slots))
+	for i := 0; i < len(slots); i++ {
+		for j := i + 1; j < len(slots); j++ {
+			copy(swapped, slots)
+			swapped[i].Team, swapped[j].Team = slots[j].Team, slots[i].Team
+			swapped[i].Position, swapped[j].Position = slots[j].Position, slots[i].Position
+
+			// check constraints on swapped arrangement
+			for k := range swapped {
+				teamOf[k] = swapped[k].Team
+			}
+			if !satisfiesCustomGameColorLabels(puuids, teamOf, colorMap) || !checker.satisfiesTeams(teamOf) {
+				continue
+			}
+			if !checker.isPositionAllowed(i, getPositionIndex(swapped[i].Position)) ||
+				!checker.isPositionAllowed(j, getPositionIndex(swapped[j].Position)) {
+				continue
+			}
+
+			balance := model.Evaluate(swapped, weights)
+			if balance.Fairness <= currentFairness {
+				continue
+			}
+			suggestions = append(suggestions, CustomGameSwapSuggestionVO{
+				Puuid1:       puuids[i],
+				Puuid2:       puuids[j],
+				Balance:      balance,
+				FairnessGain: balance.Fairness - currentFairness,
+			})
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].FairnessGain > suggestions[j].FairnessGain
+	})
+	if len(suggestions) > types.CustomGameBalanceSwapSuggestionCount {
+		suggestions = suggestions[:types.CustomGameBalanceSwapSuggestionCount]
+	}
+	return suggestions
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/models/mixed"
+	"team.gg-server/models/mixed/statistics_models"
+	"team.gg-server/types"
+)
+
+// champion pool tells how well candidate can play champions on each position.
+// proficiency of champion on position combines mastery points (spread over positions the champion is played on)
+// and recent matches with the champion on that position. masteries have no position, so champion is assumed to be
+// played on positions as candidate recently did, or as everyone does if candidate didn't play it recently.
+
+var (
+	championPositionSharesMutex sync.RWMutex
+	// share of picks on each position (by position index) of every champion
+	championPositionShares map[int64][5]float64
+)
+
+// SetChampionPositionShares replaces position shares of champions with picks counted by statistics repository
+func SetChampionPositionShares(countDAOs []statistics_models.ChampionPositionCountMXDAO) {
+	shares := buildChampionPositionShares(countDAOs)
+
+	championPositionSharesMutex.Lock()
+	defer championPositionSharesMutex.Unlock()
+	championPositionShares = shares
+}
+
+// getChampionPositionShares returns last position shares of champions (empty until statistics are loaded)
+func getChampionPositionShares() map[int64][5]float64 {
+	championPositionSharesMutex.RLock()
+	defer championPositionSharesMutex.RUnlock()
+	return championPositionShares
+}
+
+func buildChampionPositionShares(countDAOs []statistics_models.ChampionPositionCountMXDAO) map[int64][5]float64 {
+	counts := make(map[int64][5]float64)
+	for _, countDAO := range countDAOs {
+		position, ok := teamPositionToPosition(countDAO.TeamPosition)
+		if !ok {
+			continue
+		}
+		championCounts := counts[countDAO.ChampionId]
+		championCounts[getPositionIndex(position)] += float64(countDAO.Count)
+		counts[countDAO.ChampionId] = championCounts
+	}
+	for championId, championCounts := range counts {
+		total := 0.0
+		for _, count := range championCounts {
+			total += count
+		}
+		for p := range championCounts {
+			championCounts[p] /= total
+		}
+		counts[championId] = championCounts
+	}
+	return counts
+}
+
+// getRecentChampionPositionCounts returns count of recent picks by champion & team position of each summoner (by puuid),
+// counted at once for all summoners
+func getRecentChampionPositionCounts(db db.Context, puuids []string) (map[string][]mixed.RecentChampionPositionCountMXDAO, error) {
+	countDAOs, err := mixed.GetRecentChampionPositionCountMXDAOs_byPuuids(db, puuids, types.CustomGameChampionPoolMatchCount)
+	if err != nil {
+		return nil, err
+	}
+	countsMap := make(map[string][]mixed.RecentChampionPositionCountMXDAO)
+	for _, countDAO := range countDAOs {
+		countsMap[countDAO.Puuid] = append(countsMap[countDAO.Puuid], countDAO)
+	}
+	return countsMap, nil
+}
+
+// GetCustomGameChampionPoolVO builds champion pool of summoner on each position from masteries & recent picks
+func GetCustomGameChampionPoolVO(masteryDAOs []*models.MasteryDAO, recentCountDAOs []mixed.RecentChampionPositionCountMXDAO) CustomGameChampionPoolVO {
+	shares := getChampionPositionShares()
+
+	// recent matches of each champion by position index
+	recentCounts := make(map[int64][5]int)
+	for _, countDAO := range recentCountDAOs {
+		position, ok := teamPositionToPosition(countDAO.TeamPosition)
+		if !ok {
+			continue
+		}
+		championCounts := recentCounts[countDAO.ChampionId]
+		championCounts[getPositionIndex(position)] += countDAO.Count
+		recentCounts[countDAO.ChampionId] = championCounts
+	}
+	championPoints := make(map[int64]int)
+	for _, masteryDAO := range masteryDAOs {
+		championPoints[masteryDAO.ChampionId] = masteryDAO.ChampionPoints
+	}
+
+	championIds := make([]int64, 0, len(championPoints)+len(recentCounts))
+	for championId := range championPoints {
+		championIds = append(championIds, championId)
+	}
+	for championId := range recentCounts {
+		if _, exists := championPoints[championId]; !exists {
+			championIds = append(championIds, championId)
+		}
+	}
+
+	poolVO := CustomGameChampionPoolVO{Known: len(masteryDAOs) > 0 || len(recentCountDAOs) > 0}
+	for p, position := range GetSupportedPositions {
+		positionPoolVO := poolVO.byPosition(position)
+		positionPoolVO.Champions = make([]CustomGamePoolChampionVO, 0)
+		for _, championId := range championIds {
+			championRecentCounts := recentCounts[championId]
+			positionShare := shares[championId][p]
+			if recentTotal := sumRecentCounts(championRecentCounts); recentTotal > 0 {
+				positionShare = float64(championRecentCounts[p]) / float64(recentTotal)
+			}
+
+			masteryPart := math.Min(float64(championPoints[championId])/types.CustomGameChampionPoolMasteryPoints, 1) * positionShare
+			recentPart := math.Min(float64(championRecentCounts[p])/types.CustomGameChampionPoolRecentCount, 1)
+			proficiency := 1 - (1-masteryPart)*(1-recentPart)
+			if proficiency < types.CustomGameChampionPoolMinProficiency {
+				continue
+			}
+
+			var championName *string
+			if champion, ok := Champions[strconv.FormatInt(championId, 10)]; ok {
+				championName = &champion.Name
+			}
+			positionPoolVO.Depth += proficiency
+			positionPoolVO.Champions = append(positionPoolVO.Champions, CustomGamePoolChampionVO{
+				ChampionId:       championId,
+				ChampionName:     championName,
+				Proficiency:      proficiency,
+				ChampionPoints:   championPoints[championId],
+				RecentMatchCount: championRecentCounts[p],
+			})
+		}
+		sort.SliceStable(positionPoolVO.Champions, func(i, j int) bool {
+			if positionPoolVO.Champions[i].Proficiency != positionPoolVO.Champions[j].Proficiency {
+				return positionPoolVO.Champions[i].Proficiency > positionPoolVO.Champions[j].Proficiency
+			}
+			return positionPoolVO.Champions[i].ChampionId < positionPoolVO.Champions[j].ChampionId
+		})
+		if len(positionPoolVO.Champions) > types.CustomGameChampionPoolSize {
+			positionPoolVO.Champions = positionPoolVO.Champions[:types.CustomGameChampionPoolSize]
+		}
+	}
+	return poolVO
+}
+
+func sumRecentCounts(counts [5]int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
+
+// customGameChampionPoolTable holds champion pool penalties of participants on each position,
+// computed once (per optimization or balance) so that evaluating arrangement only looks them up.
+// key of participant on position is participant index * 5 + position index.
+type customGameChampionPoolTable struct {
+	keyCount  int
+	thin      []float64 // by key, penalty of playing position of thin pool
+	conflicts []float64 // by key * keyCount + key of teammate, see sharedChampionPoolConflict
+}
+
+// newCustomGameChampionPoolTable precomputes penalties of participants (by index), unknown pools are never penalized
+func newCustomGameChampionPoolTable(pools []CustomGameChampionPoolVO) *customGameChampionPoolTable {
+	keyCount := len(pools) * len(GetSupportedPositions)
+	table := &customGameChampionPoolTable{
+		keyCount:  keyCount,
+		thin:      make([]float64, keyCount),
+		conflicts: make([]float64, keyCount*keyCount),
+	}
+	positionPool := func(key int) *CustomGamePositionChampionPoolVO {
+		pool := &pools[key/len(GetSupportedPositions)]
+		if !pool.Known {
+			return nil
+		}
+		return pool.byPosition(GetSupportedPositions[key%len(GetSupportedPositions)])
+	}
+
+	for key1 := 0; key1 < keyCount; key1++ {
+		pool1 := positionPool(key1)
+		if pool1 == nil {
+			continue
+		}
+		if pool1.Depth < types.CustomGameChampionPoolThinDepth {
+			table.thin[key1] = (types.CustomGameChampionPoolThinDepth - pool1.Depth) / types.CustomGameChampionPoolThinDepth
+		}
+		for key2 := 0; key2 < key1; key2++ {
+			pool2 := positionPool(key2)
+			if pool2 == nil || key1/len(GetSupportedPositions) == key2/len(GetSupportedPositions) {
+				continue
+			}
+			conflict := sharedChampionPoolConflict(pool1, pool2)
+			table.conflicts[key1*keyCount+key2] = conflict
+			table.conflicts[key2*keyCount+key1] = conflict
+		}
+	}
+	return table
+}
+
+// setCustomGameChampionPoolTable precomputes penalties of participants of slots (same order), shared by slots
+func setCustomGameChampionPoolTable(slots []customGameFairnessSlot, pools []CustomGameChampionPoolVO) {
+	table := newCustomGameChampionPoolTable(pools)
+	for i := range slots {
+		slots[i].ChampionPoolTable = table
+		slots[i].ChampionPoolIndex = i
+	}
+}
+
+// customGameChampionPoolPenalty scores how much arrangement suffers from champion pools (0~1, lower is better):
+// players on positions of thin pool, and teammates whose narrow pools depend on same champions.
+func customGameChampionPoolPenalty(slots []customGameFairnessSlot) float64 {
+	if len(slots) == 0 {
+		return 0
+	}
+
+	penalty := 0.0
+	for i := range slots {
+		table := slots[i].ChampionPoolTable
+		key1 := slots[i].championPoolKey()
+		if table == nil || key1 < 0 {
+			continue
+		}
+		penalty += table.thin[key1]
+		for j := i + 1; j < len(slots); j++ {
+			if slots[j].Team != slots[i].Team || slots[j].ChampionPoolTable != table {
+				continue
+			}
+			if key2 := slots[j].championPoolKey(); key2 >= 0 {
+				penalty += table.conflicts[key1*table.keyCount+key2]
+			}
+		}
+	}
+	return math.Min(penalty/float64(len(slots)), 1)
+}
+
+// sharedChampionPoolConflict returns how much narrower pool of two teammates overlaps with the other (0~1),
+// 0 if both pools are deep enough to give way
+func sharedChampionPoolConflict(pool1, pool2 *CustomGamePositionChampionPoolVO) float64 {
+	narrowDepth := math.Min(pool1.Depth, pool2.Depth)
+	if narrowDepth <= 0 || narrowDepth >= types.CustomGameChampionPoolNarrowDepth {
+		return 0
+	}
+	shared := 0.0
+	for _, champion1 := range pool1.Champions {
+		for _, champion2 := range pool2.Champions {
+			if champion1.ChampionId == champion2.ChampionId {
+				shared += math.Min(champion1.Proficiency, champion2.Proficiency)
+			}
+		}
+	}
+	return math.Min(shared/narrowDepth, 1)
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"fmt"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/types"
+)
+
+// constraints restrict arrangements of players who play (constraints of benched players are ignored).
+// team constraints are checked on team split, position constraints on position assignment.
+
+type CustomGameConstraintConflictError struct {
+	// minimal set of constraints which can't be satisfied together (empty if color labels can't be satisfied)
+	Constraints []CustomGameConstraintVO
+}
+
+func (e *CustomGameConstraintConflictError) Error() string {
+	if len(e.Constraints) == 0 {
+		return "no arrangement satisfies color labels"
+	}
+	return fmt.Sprintf("no arrangement satisfies constraints (%d conflicting)", len(e.Constraints))
+}
+
+func GetCustomGameConstraintVOs(db db.Context, configId string) ([]CustomGameConstraintVO, error) {
+	constraintDAOs, err := models.GetCustomGameConstraintDAOs_byCustomGameConfigId(db, configId)
+	if err != nil {
+		return nil, err
+	}
+	constraintVOs := make([]CustomGameConstraintVO, 0)
+	for _, constraintDAO := range constraintDAOs {
+		constraintVOs = append(constraintVOs, CustomGameConstraintMixer(constraintDAO))
+	}
+	return constraintVOs, nil
+}
+
+// ValidateCustomGameConstraint checks whether constraint is well-formed
+func ValidateCustomGameConstraint(constraint CustomGameConstraintVO) error {
+	switch constraint.Type {
+	case types.CustomGameConstraintTogether, types.CustomGameConstraintApart:
+		if constraint.TargetPuuid == nil {
+			return fmt.Errorf("target player is required")
+		}
+		if *constraint.TargetPuuid == constraint.Puuid {
+			return fmt.Errorf("target player should be another player")
+		}
+	case types.CustomGameConstraintLockedTeam:
+		if constraint.Team == nil || (*constraint.Team != 1 && *constraint.Team != 2) {
+			return fmt.Errorf("invalid team")
+		}
+	case types.CustomGameConstraintLockedPosition, types.CustomGameConstraintForbiddenPosition:
+		if constraint.Position == nil || getPositionIndex(*constraint.Position) < 0 {
+			return fmt.Errorf("invalid position")
+		}
+	default:
+		return fmt.Errorf("invalid constraint type")
+	}
+	return nil
+}
+
+// FindCustomGameConstraintConflict returns conflicting constraints if players involved in constraints can't be arranged.
+// returns empty if involved players can't play at once (constraints may be satisfied depending on who plays).
+func FindCustomGameConstraintConflict(constraints []CustomGameConstraintVO, colorMap map[string]int) []CustomGameConstraintVO {
+	puuids := make([]string, 0)
+	involved := make(map[string]bool)
+	for _, constraint := range constraints {
+		for _, puuid := range constraint.getPlayers() {
+			if !involved[puuid] {
+				involved[puuid] = true
+				puuids = append(puuids, puuid)
+			}
+		}
+	}
+	if len(puuids) > customGameLobbySeatCount {
+		return make([]CustomGameConstraintVO, 0)
+	}
+	return findCustomGameConstraintConflict(puuids, colorMap, constraints)
+}
+
+// findCustomGameConstraintConflict returns minimal conflicting constraints of players (all playing), nil if satisfiable
+func findCustomGameConstraintConflict(puuids []string, colorMap map[string]int, constraints []CustomGameConstraintVO) []CustomGameConstraintVO {
+	if isCustomGameArrangementFeasible(puuids, colorMap, constraints) {
+		return nil
+	}
+
+	// drop constraints one by one while the rest still conflict
+	conflict := make([]CustomGameConstraintVO, len(constraints))
+	copy(conflict, constraints)
+	for k := 0; k < len(conflict); {
+		rest := make([]CustomGameConstraintVO, 0, len(conflict)-1)
+		rest = append(rest, conflict[:k]...)
+		rest = append(rest, conflict[k+1:]...)
+		if !isCustomGameArrangementFeasible(puuids, colorMap, rest) {
+			conflict = rest
+		} else {
+			k++
+		}
+	}
+	return conflict
+}
+
+func isCustomGameArrangementFeasible(puuids []string, colorMap map[string]int, constraints []CustomGameConstraintVO) bool {
+	checker := newCustomGameConstraintChecker(puuids, constraints)
+	teamOf := make([]int, len(puuids))
+	team1 := make([]int, 0, 5)
+	team2 := make([]int, 0, 5)
+	for mask := 0; mask < 1<<len(puuids); mask++ {
+		team1, team2 = team1[:0], team2[:0]
+		for i := range puuids {
+			if mask&(1<<i) != 0 {
+				teamOf[i] = 1
+				team1 = append(team1, i)
+			} else {
+				teamOf[i] = 2
+				team2 = append(team2, i)
+			}
+		}
+		if len(team1) > 5 || len(team2) > 5 {
+			continue
+		}
+		if !satisfiesCustomGameColorLabels(puuids, teamOf, colorMap) || !checker.satisfiesTeams(teamOf) {
+			continue
+		}
+		if len(checker.getAllowedPermutations(team1)) > 0 && len(checker.getAllowedPermutations(team2)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// satisfiesCustomGameColorLabels checks players with same color label are on the same team (team 0: not playing)
+func satisfiesCustomGameColorLabels(puuids []string, teamOf []int, colorMap map[string]int) bool {
+	colorTeams := make(map[int]int)
+	for i, puuid := range puuids {
+		colorCode := colorMap[puuid]
+		if colorCode == 0 || teamOf[i] == 0 {
+			continue
+		}
+		if colorTeam, exists := colorTeams[colorCode]; exists && colorTeam != teamOf[i] {
+			return false
+		}
+		colorTeams[colorCode] = teamOf[i]
+	}
+	return true
+}
+
+func (c CustomGameConstraintVO) getPlayers() []string {
+	if c.TargetPuuid != nil {
+		return []string{c.Puuid, *c.TargetPuuid}
+	}
+	return []string{c.Puuid}
+}
+
+func getPositionIndex(position string) int {
+	for p, supportedPosition := range GetSupportedPositions {
+		if supportedPosition == position {
+			return p
+		}
+	}
+	return -1
+}
+
+type customGameTeamConstraint struct {
+	constraintType string
+	player         int
+	target         int
+	team           int
+}
+
+// customGameConstraintChecker is constraints compiled to player indices
+type customGameConstraintChecker struct {
+	teamConstraints  []customGameTeamConstraint
+	allowedPositions []int // bit mask of position indices, by player index
+	hasLockedTeam    bool
+}
+
+func newCustomGameConstraintChecker(puuids []string, constraints []CustomGameConstraintVO) *customGameConstraintChecker {
+	indices := make(map[string]int)
+	for i, puuid := range puuids {
+		indices[puuid] = i
+	}
+
+	c := &customGameConstraintChecker{
+		teamConstraints:  make([]customGameTeamConstraint, 0),
+		allowedPositions: make([]int, len(puuids)),
+	}
+	for i := range c.allowedPositions {
+		c.allowedPositions[i] = 1<<len(GetSupportedPositions) - 1
+	}
+
+	for _, constraint := range constraints {
+		player, exists := indices[constraint.Puuid]
+		if !exists {
+			continue
+		}
+		switch constraint.Type {
+		case types.CustomGameConstraintTogether, types.CustomGameConstraintApart:
+			target, exists := indices[*constraint.TargetPuuid]
+			if !exists {
+				continue
+			}
+			c.teamConstraints = append(c.teamConstraints, customGameTeamConstraint{
+				constraintType: constraint.Type,
+				player:         player,
+				target:         target,
+			})
+		case types.CustomGameConstraintLockedTeam:
+			c.teamConstraints = append(c.teamConstraints, customGameTeamConstraint{
+				constraintType: constraint.Type,
+				player:         player,
+				team:           *constraint.Team,
+			})
+			c.hasLockedTeam = true
+		case types.CustomGameConstraintLockedPosition:
+			c.allowedPositions[player] &= 1 << getPositionIndex(*constraint.Position)
+		case types.CustomGameConstraintForbiddenPosition:
+			c.allowedPositions[player] &^= 1 << getPositionIndex(*constraint.Position)
+		}
+	}
+	return c
+}
+
+// satisfiesTeams checks team constraints (teamOf: team by player index, 0 if not playing)
+func (c *customGameConstraintChecker) satisfiesTeams(teamOf []int) bool {
+	for _, constraint := range c.teamConstraints {
+		playerTeam := teamOf[constraint.player]
+		if playerTeam == 0 {
+			continue
+		}
+		switch constraint.constraintType {
+		case types.CustomGameConstraintTogether:
+			if targetTeam := teamOf[constraint.target]; targetTeam != 0 && targetTeam != playerTeam {
+				return false
+			}
+		case types.CustomGameConstraintApart:
+			if targetTeam := teamOf[constraint.target]; targetTeam != 0 && targetTeam == playerTeam {
+				return false
+			}
+		case types.CustomGameConstraintLockedTeam:
+			if playerTeam != constraint.team {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (c *customGameConstraintChecker) isPositionAllowed(player, position int) bool {
+	return c.allowedPositions[player]&(1<<position) != 0
+}
+
+// getAllowedPermutations returns position permutations of team members satisfying position constraints
+func (c *customGameConstraintChecker) getAllowedPermutations(members []int) [][]int {
+	permutations := make([][]int, 0)
+	for _, permutation := range getPositionPermutations(len(members)) {
+		allowed := true
+		for k, i := range members {
+			if !c.isPositionAllowed(i, permutation[k]) {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			permutations = append(permutations, permutation)
+		}
+	}
+	return permutations
+}
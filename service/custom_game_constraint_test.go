@@ -0,0 +1,143 @@
+package service
+
+import (
+	"team.gg-server/types"
+	"testing"
+)
+
+func newTestCustomGameConstraint(id, constraintType, puuid string, target string, team int, position string) CustomGameConstraintVO {
+	constraint := CustomGameConstraintVO{Id: id, Type: constraintType, Puuid: puuid}
+	if target != "" {
+		constraint.TargetPuuid = &target
+	}
+	if team != 0 {
+		constraint.Team = &team
+	}
+	if position != "" {
+		constraint.Position = &position
+	}
+	return constraint
+}
+
+func TestFindCustomGameConstraintConflict(t *testing.T) {
+	together := func(id, puuid, target string) CustomGameConstraintVO {
+		return newTestCustomGameConstraint(id, types.CustomGameConstraintTogether, puuid, target, 0, "")
+	}
+	apart := func(id, puuid, target string) CustomGameConstraintVO {
+		return newTestCustomGameConstraint(id, types.CustomGameConstraintApart, puuid, target, 0, "")
+	}
+	lockedTeam := func(id, puuid string, team int) CustomGameConstraintVO {
+		return newTestCustomGameConstraint(id, types.CustomGameConstraintLockedTeam, puuid, "", team, "")
+	}
+	lockedPosition := func(id, puuid, position string) CustomGameConstraintVO {
+		return newTestCustomGameConstraint(id, types.CustomGameConstraintLockedPosition, puuid, "", 0, position)
+	}
+	forbiddenPosition := func(id, puuid, position string) CustomGameConstraintVO {
+		return newTestCustomGameConstraint(id, types.CustomGameConstraintForbiddenPosition, puuid, "", 0, position)
+	}
+
+	tests := []struct {
+		name        string
+		puuids      []string
+		colorMap    map[string]int
+		constraints []CustomGameConstraintVO
+		// ids of minimal conflict, nil if satisfiable
+		expected []string
+	}{
+		{
+			name:   "satisfiable",
+			puuids: []string{"a", "b", "c", "d"},
+			constraints: []CustomGameConstraintVO{
+				together("c1", "a", "b"),
+				apart("c2", "b", "c"),
+				lockedTeam("c3", "a", 1),
+				lockedPosition("c4", "c", types.PositionTop),
+			},
+		},
+		{
+			// a & b together, b & c apart, but a & c are locked on the same team
+			name:   "together, apart & locked team",
+			puuids: []string{"a", "b", "c", "d", "e"},
+			constraints: []CustomGameConstraintVO{
+				lockedPosition("x1", "d", types.PositionTop),
+				together("c1", "a", "b"),
+				together("x2", "d", "e"),
+				apart("c2", "b", "c"),
+				forbiddenPosition("x3", "e", types.PositionMid),
+				lockedTeam("c3", "a", 1),
+				lockedTeam("c4", "c", 1),
+				apart("x4", "a", "e"),
+			},
+			expected: []string{"c1", "c2", "c3", "c4"},
+		},
+		{
+			name:   "together with same locked position",
+			puuids: []string{"a", "b", "c"},
+			constraints: []CustomGameConstraintVO{
+				lockedPosition("c1", "a", types.PositionAdc),
+				lockedTeam("x1", "c", 2),
+				lockedPosition("c2", "b", types.PositionAdc),
+				together("c3", "a", "b"),
+			},
+			expected: []string{"c1", "c2", "c3"},
+		},
+		{
+			name:     "apart with same color label",
+			puuids:   []string{"a", "b", "c"},
+			colorMap: map[string]int{"a": 1, "c": 1},
+			constraints: []CustomGameConstraintVO{
+				together("x1", "a", "b"),
+				apart("c1", "a", "c"),
+			},
+			expected: []string{"c1"},
+		},
+		{
+			name:   "too many players locked on a team",
+			puuids: []string{"a", "b", "c", "d", "e", "f", "g"},
+			constraints: []CustomGameConstraintVO{
+				lockedTeam("c1", "a", 1),
+				lockedTeam("c2", "b", 1),
+				lockedTeam("c3", "c", 1),
+				lockedTeam("x1", "g", 2),
+				lockedTeam("c4", "d", 1),
+				lockedTeam("c5", "e", 1),
+				lockedTeam("c6", "f", 1),
+			},
+			expected: []string{"c1", "c2", "c3", "c4", "c5", "c6"},
+		},
+		{
+			name:     "color labels only",
+			puuids:   []string{"a", "b", "c", "d", "e", "f"},
+			colorMap: map[string]int{"a": 1, "b": 1, "c": 1, "d": 1, "e": 1, "f": 1},
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		conflict := findCustomGameConstraintConflict(test.puuids, test.colorMap, test.constraints)
+		if test.expected == nil {
+			if conflict != nil {
+				t.Errorf("%s: expected no conflict, got %v", test.name, conflict)
+			}
+			continue
+		}
+		if conflict == nil {
+			t.Errorf("%s: expected conflict %v, got none", test.name, test.expected)
+			continue
+		}
+		ids := make([]string, 0, len(conflict))
+		for _, constraint := range conflict {
+			ids = append(ids, constraint.Id)
+		}
+		if len(ids) != len(test.expected) {
+			t.Errorf("%s: expected conflict %v, got %v", test.name, test.expected, ids)
+			continue
+		}
+		for k := range ids {
+			if ids[k] != test.expected[k] {
+				t.Errorf("%s: expected conflict %v, got %v", test.name, test.expected, ids)
+				break
+			}
+		}
+	}
+}
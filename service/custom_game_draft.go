@@ -0,0 +1,554 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	log "github.com/shyunku-libraries/go-logger"
+	"sync"
+	"team.gg-server/controllers/socket"
+	"team.gg-server/libs/db"
+	"team.gg-server/types"
+	"time"
+)
+
+// captain draft runs as server-side session (at most one running per configuration):
+// 2 captains (candidates, controlled by members) pick remaining players turn by turn, with position of each pick.
+// every pick is broadcast to custom config room, captain who runs out of turn time gets auto pick,
+// and teams are written into participants of configuration when all players are picked.
+// configuration can't be edited while drafting (see IsCustomGameDrafting), draft is cancelled on server shutdown.
+
+const (
+	CustomGameDraftStatusDrafting  = "drafting"
+	CustomGameDraftStatusCompleted = "completed"
+	CustomGameDraftStatusFailed    = "failed"
+	CustomGameDraftStatusCancelled = "cancelled"
+)
+
+var (
+	ErrCustomGameDraftAlreadyRunning = errors.New("draft is already running")
+	ErrCustomGameDraftNotFound       = errors.New("draft not found")
+	ErrCustomGameDraftNotYourTurn    = errors.New("not your turn to pick")
+	ErrCustomGameDraftInvalidPick    = errors.New("invalid pick")
+	ErrCustomGameDraftInvalidCaptain = errors.New("invalid captain")
+	ErrCustomGameDraftInvalidOrder   = errors.New("invalid draft order")
+	ErrCustomGameDraftNotEnough      = errors.New("not enough candidates to draft")
+)
+
+type CustomGameDraftOptions struct {
+	Order       string // types.CustomGameDraftOrderSnake or types.CustomGameDraftOrderAlternating
+	TurnSeconds int
+	Captains    [2]CustomGameDraftCaptainVO // captain of team 1 picks first
+	ActorUid    string                      // user who started draft (recorded on revision)
+	// version of configuration when draft started, drafted teams are not applied if configuration is changed while drafting
+	Version int
+}
+
+type CustomGameDraftCaptainVO struct {
+	Team     int    `json:"team"`
+	Puuid    string `json:"puuid"`
+	Uid      string `json:"uid"`      // member who picks for captain
+	Position string `json:"position"` // position captain plays
+}
+
+type CustomGameDraftPickVO struct {
+	Team     int       `json:"team"`
+	Puuid    string    `json:"puuid"`
+	Position string    `json:"position"`
+	Auto     bool      `json:"auto"` // picked by server on timeout
+	PickedAt time.Time `json:"pickedAt"`
+}
+
+type CustomGameDraftVO struct {
+	Id          string                     `json:"id"`
+	ConfigId    string                     `json:"configId"`
+	Status      string                     `json:"status"`
+	Order       string                     `json:"order"`
+	TurnSeconds int                        `json:"turnSeconds"`
+	Captains    []CustomGameDraftCaptainVO `json:"captains"`
+	Picks       []CustomGameDraftPickVO    `json:"picks"`
+	Available   []string                   `json:"available"` // puuids of candidates not picked yet
+	Turn        int                        `json:"turn"`      // index of pick to be made next
+	TurnTeam    int                        `json:"turnTeam"`  // team of captain to pick next, 0 if all picked
+	TurnEndsAt  *time.Time                 `json:"turnEndsAt"`
+	StartedAt   time.Time                  `json:"startedAt"`
+	EndedAt     *time.Time                 `json:"endedAt"`
+	Error       *string                    `json:"error"`
+}
+
+type customGameDraft struct {
+	CustomGameDraftVO
+	options    CustomGameDraftOptions
+	pickOrder  []int                                  // team to pick on each turn
+	candidates map[string]CustomGameTeamParticipantVO // for auto pick
+	timer      *time.Timer
+	ctx        context.Context
+	cancel     context.CancelFunc
+	done       func() // called when draft ends, shutdown waits for it (teams may be being applied)
+}
+
+var (
+	// key: config id (running or last finished draft of the configuration)
+	customGameDrafts      = make(map[string]*customGameDraft)
+	customGameDraftsMutex sync.Mutex
+)
+
+// getCustomGameDraftPickOrder returns team to pick on each turn, until both teams have 5 players (captains included)
+func getCustomGameDraftPickOrder(order string) []int {
+	pickCount := len(GetSupportedPositions)*2 - 2
+	pickOrder := make([]int, pickCount)
+	for i := range pickOrder {
+		if order == types.CustomGameDraftOrderSnake {
+			// 1 2 2 1 1 2 2 1
+			pickOrder[i] = 1 + ((i+1)/2)%2
+		} else {
+			pickOrder[i] = 1 + i%2
+		}
+	}
+	return pickOrder
+}
+
+// IsCustomGameDrafting returns whether draft of configuration is running
+func IsCustomGameDrafting(configId string) bool {
+	customGameDraftsMutex.Lock()
+	defer customGameDraftsMutex.Unlock()
+
+	draft, exists := customGameDrafts[configId]
+	return exists && draft.Status == CustomGameDraftStatusDrafting
+}
+
+// StartCustomGameDraft starts captain draft among candidates of configuration
+func StartCustomGameDraft(db db.Context, configId string, options CustomGameDraftOptions) (*CustomGameDraftVO, error) {
+	if options.Order != types.CustomGameDraftOrderSnake && options.Order != types.CustomGameDraftOrderAlternating {
+		return nil, ErrCustomGameDraftInvalidOrder
+	}
+	captain1, captain2 := options.Captains[0], options.Captains[1]
+	if captain1.Puuid == captain2.Puuid || getPositionIndex(captain1.Position) < 0 || getPositionIndex(captain2.Position) < 0 {
+		return nil, ErrCustomGameDraftInvalidCaptain
+	}
+	for _, captain := range options.Captains {
+		// captains are picked by members
+		isMember, err := CheckRoleForCustomGameConfig(db, configId, captain.Uid, types.CustomGameRoleViewer)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return nil, ErrCustomGameDraftInvalidCaptain
+		}
+	}
+
+	candidateVOsMap, err := GetCustomGameCandidatePoolVOMap(db, configId)
+	if err != nil {
+		return nil, err
+	}
+	for _, captain := range options.Captains {
+		if _, exists := candidateVOsMap[captain.Puuid]; !exists {
+			return nil, ErrCustomGameDraftInvalidCaptain
+		}
+	}
+	pickOrder := getCustomGameDraftPickOrder(options.Order)
+	if len(candidateVOsMap) < len(pickOrder)+2 {
+		return nil, ErrCustomGameDraftNotEnough
+	}
+
+	available := make([]string, 0, len(candidateVOsMap))
+	for _, candidate := range sortedCustomGamePool(candidateVOsMap) {
+		puuid := candidate.Summary.Puuid
+		if puuid != captain1.Puuid && puuid != captain2.Puuid {
+			available = append(available, puuid)
+		}
+	}
+
+	customGameDraftsMutex.Lock()
+	if draft, exists := customGameDrafts[configId]; exists && draft.Status == CustomGameDraftStatusDrafting {
+		customGameDraftsMutex.Unlock()
+		return nil, ErrCustomGameDraftAlreadyRunning
+	}
+	captain1.Team, captain2.Team = 1, 2
+	options.Captains = [2]CustomGameDraftCaptainVO{captain1, captain2}
+	// draft is cancelled on server shutdown too
+	ctx, cancel := context.WithCancel(ServerContext)
+	draft := &customGameDraft{
+		CustomGameDraftVO: CustomGameDraftVO{
+			Id:          uuid.NewString(),
+			ConfigId:    configId,
+			Status:      CustomGameDraftStatusDrafting,
+			Order:       options.Order,
+			TurnSeconds: options.TurnSeconds,
+			Captains:    []CustomGameDraftCaptainVO{captain1, captain2},
+			Picks:       make([]CustomGameDraftPickVO, 0, len(pickOrder)),
+			Available:   available,
+			StartedAt:   time.Now(),
+		},
+		options:    options,
+		pickOrder:  pickOrder,
+		candidates: candidateVOsMap,
+		ctx:        ctx,
+		cancel:     cancel,
+		done:       trackBackgroundJob(),
+	}
+	customGameDrafts[configId] = draft
+	go draft.cancelOnShutdown()
+	draft.startTurn()
+	updatedData := draft.updatedData(nil)
+	vo := draft.snapshot()
+	customGameDraftsMutex.Unlock()
+
+	socket.SocketIO.BroadcastToCustomConfigRoom(configId, socket.EventCustomConfigDraftUpdated, updatedData)
+	return &vo, nil
+}
+
+// PickCustomGameDraft picks player for captain controlled by given user, position is chosen by player's favor if empty
+func PickCustomGameDraft(configId, draftId, uid, puuid, position string) (*CustomGameDraftVO, error) {
+	customGameDraftsMutex.Lock()
+	draft, exists := customGameDrafts[configId]
+	if !exists || draft.Id != draftId || draft.Status != CustomGameDraftStatusDrafting || draft.TurnTeam == 0 {
+		customGameDraftsMutex.Unlock()
+		return nil, ErrCustomGameDraftNotFound
+	}
+	if draft.options.Captains[draft.TurnTeam-1].Uid != uid {
+		customGameDraftsMutex.Unlock()
+		return nil, ErrCustomGameDraftNotYourTurn
+	}
+	updatedData, err := draft.pick(puuid, position, false)
+	if err != nil {
+		customGameDraftsMutex.Unlock()
+		return nil, err
+	}
+	vo := draft.snapshot()
+	customGameDraftsMutex.Unlock()
+
+	draft.afterPick(updatedData)
+	return &vo, nil
+}
+
+// CancelCustomGameDraft cancels running draft, participants are not changed
+func CancelCustomGameDraft(configId, draftId string) error {
+	customGameDraftsMutex.Lock()
+	draft, exists := customGameDrafts[configId]
+	if !exists || draft.Id != draftId || draft.Status != CustomGameDraftStatusDrafting || draft.TurnTeam == 0 {
+		customGameDraftsMutex.Unlock()
+		return ErrCustomGameDraftNotFound
+	}
+	draft.end(CustomGameDraftStatusCancelled, nil)
+	doneData := draft.doneData()
+	customGameDraftsMutex.Unlock()
+
+	socket.SocketIO.BroadcastToCustomConfigRoom(configId, socket.EventCustomConfigDraftDone, doneData)
+	return nil
+}
+
+// GetCustomGameDraftVO returns running or last finished draft of configuration
+func GetCustomGameDraftVO(configId string) (*CustomGameDraftVO, bool) {
+	customGameDraftsMutex.Lock()
+	defer customGameDraftsMutex.Unlock()
+
+	draft, exists := customGameDrafts[configId]
+	if !exists {
+		return nil, false
+	}
+	vo := draft.snapshot()
+	return &vo, true
+}
+
+// cancelOnShutdown cancels running draft when server shuts down (returns when draft ends)
+func (d *customGameDraft) cancelOnShutdown() {
+	<-d.ctx.Done()
+
+	customGameDraftsMutex.Lock()
+	// ended already, or teams are being applied (apply ends draft)
+	if d.Status != CustomGameDraftStatusDrafting || d.TurnTeam == 0 {
+		customGameDraftsMutex.Unlock()
+		return
+	}
+	d.end(CustomGameDraftStatusCancelled, nil)
+	doneData := d.doneData()
+	customGameDraftsMutex.Unlock()
+
+	socket.SocketIO.BroadcastToCustomConfigRoom(d.ConfigId, socket.EventCustomConfigDraftDone, doneData)
+}
+
+// snapshot copies draft, so that it can be read without lock (should be called with lock)
+func (d *customGameDraft) snapshot() CustomGameDraftVO {
+	vo := d.CustomGameDraftVO
+	vo.Captains = append([]CustomGameDraftCaptainVO{}, d.Captains...)
+	vo.Picks = append([]CustomGameDraftPickVO{}, d.Picks...)
+	vo.Available = append([]string{}, d.Available...)
+	return vo
+}
+
+// startTurn sets team to pick & timer of current turn (should be called with lock)
+func (d *customGameDraft) startTurn() {
+	if d.Turn >= len(d.pickOrder) {
+		d.TurnTeam = 0
+		d.TurnEndsAt = nil
+		return
+	}
+	d.TurnTeam = d.pickOrder[d.Turn]
+	turnEndsAt := time.Now().Add(time.Duration(d.TurnSeconds) * time.Second)
+	d.TurnEndsAt = &turnEndsAt
+
+	turn := d.Turn
+	d.timer = time.AfterFunc(time.Duration(d.TurnSeconds)*time.Second, func() {
+		d.onTurnTimeout(turn)
+	})
+}
+
+func (d *customGameDraft) onTurnTimeout(turn int) {
+	customGameDraftsMutex.Lock()
+	// picked or cancelled right before timeout
+	if d.Status != CustomGameDraftStatusDrafting || d.Turn != turn {
+		customGameDraftsMutex.Unlock()
+		return
+	}
+	updatedData, err := d.pick("", "", true)
+	if err != nil {
+		log.Error(err)
+		d.end(CustomGameDraftStatusFailed, err)
+		doneData := d.doneData()
+		customGameDraftsMutex.Unlock()
+
+		socket.SocketIO.BroadcastToCustomConfigRoom(d.ConfigId, socket.EventCustomConfigDraftDone, doneData)
+		return
+	}
+	customGameDraftsMutex.Unlock()
+
+	d.afterPick(updatedData)
+}
+
+// afterPick broadcasts pick, and applies teams if all players are picked (should be called without lock)
+func (d *customGameDraft) afterPick(updatedData socket.CustomConfigDraftUpdatedData) {
+	socket.SocketIO.BroadcastToCustomConfigRoom(d.ConfigId, socket.EventCustomConfigDraftUpdated, updatedData)
+	if updatedData.TurnTeam == 0 {
+		d.apply()
+	}
+}
+
+// pick adds player (best available one if auto) to team of current turn, and moves to next turn (should be called with lock)
+func (d *customGameDraft) pick(puuid, position string, auto bool) (socket.CustomConfigDraftUpdatedData, error) {
+	team := d.TurnTeam
+	if auto {
+		puuid = d.bestAvailable()
+	}
+	availableIndex := -1
+	for i, availablePuuid := range d.Available {
+		if availablePuuid == puuid {
+			availableIndex = i
+			break
+		}
+	}
+	if availableIndex < 0 {
+		return socket.CustomConfigDraftUpdatedData{}, ErrCustomGameDraftInvalidPick
+	}
+
+	freePositions := d.freePositions(team)
+	if position == "" {
+		position = d.favoritePosition(puuid, freePositions)
+	}
+	if !freePositions[position] {
+		return socket.CustomConfigDraftUpdatedData{}, ErrCustomGameDraftInvalidPick
+	}
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.Available = append(d.Available[:availableIndex:availableIndex], d.Available[availableIndex+1:]...)
+	d.Picks = append(d.Picks, CustomGameDraftPickVO{
+		Team:     team,
+		Puuid:    puuid,
+		Position: position,
+		Auto:     auto,
+		PickedAt: time.Now(),
+	})
+	d.Turn++
+	d.startTurn()
+
+	return d.updatedData(&d.Picks[len(d.Picks)-1]), nil
+}
+
+// bestAvailable returns available player of highest rating point (should be called with lock)
+func (d *customGameDraft) bestAvailable() string {
+	best := ""
+	var bestRatingPoint int64 = -1
+	for _, puuid := range d.Available {
+		candidate, exists := d.candidates[puuid]
+		if !exists {
+			continue
+		}
+		if ratingPoint := candidate.GetRepresentativeRatingPoint(); ratingPoint > bestRatingPoint {
+			best = puuid
+			bestRatingPoint = ratingPoint
+		}
+	}
+	return best
+}
+
+// freePositions returns positions not taken in team yet (should be called with lock)
+func (d *customGameDraft) freePositions(team int) map[string]bool {
+	freePositions := make(map[string]bool)
+	for _, position := range GetSupportedPositions {
+		freePositions[position] = true
+	}
+	delete(freePositions, d.options.Captains[team-1].Position)
+	for _, pick := range d.Picks {
+		if pick.Team == team {
+			delete(freePositions, pick.Position)
+		}
+	}
+	return freePositions
+}
+
+// favoritePosition returns free position player favors most (should be called with lock)
+func (d *customGameDraft) favoritePosition(puuid string, freePositions map[string]bool) string {
+	favorite := ""
+	favoriteFavor := 0
+	for _, position := range GetSupportedPositions {
+		if !freePositions[position] {
+			continue
+		}
+		favor := getPositionFavor(d.candidates[puuid].PositionFavor, position)
+		if favorite == "" || favor > favoriteFavor {
+			favorite = position
+			favoriteFavor = favor
+		}
+	}
+	return favorite
+}
+
+// end finishes draft with given status, and evicts it after retention (should be called with lock)
+func (d *customGameDraft) end(status string, err error) {
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	if d.cancel != nil {
+		d.cancel()
+	}
+	if d.done != nil {
+		d.done()
+	}
+	time.AfterFunc(types.CustomGameDraftRetention, d.evict)
+	now := time.Now()
+	d.Status = status
+	d.EndedAt = &now
+	d.TurnTeam = 0
+	d.TurnEndsAt = nil
+	if err != nil {
+		errMsg := err.Error()
+		d.Error = &errMsg
+	}
+}
+
+// evict removes finished draft, unless another draft of configuration started since
+func (d *customGameDraft) evict() {
+	customGameDraftsMutex.Lock()
+	defer customGameDraftsMutex.Unlock()
+
+	if customGameDrafts[d.ConfigId] == d {
+		delete(customGameDrafts, d.ConfigId)
+	}
+}
+
+func (d *customGameDraft) updatedData(pick *CustomGameDraftPickVO) socket.CustomConfigDraftUpdatedData {
+	data := socket.CustomConfigDraftUpdatedData{
+		DraftId:    d.Id,
+		Turn:       d.Turn,
+		TurnTeam:   d.TurnTeam,
+		TurnEndsAt: d.TurnEndsAt,
+	}
+	if pick != nil {
+		puuid, position := pick.Puuid, pick.Position
+		data.PickedPuuid = &puuid
+		data.PickedPosition = &position
+		data.AutoPicked = pick.Auto
+	}
+	return data
+}
+
+func (d *customGameDraft) doneData() socket.CustomConfigDraftDoneData {
+	return socket.CustomConfigDraftDoneData{
+		DraftId: d.Id,
+		Status:  d.Status,
+		Error:   d.Error,
+	}
+}
+
+// apply writes drafted teams into participants of configuration, and broadcasts result
+func (d *customGameDraft) apply() {
+	// draft can't be cancelled or picked anymore, as all players are picked
+	customGameDraftsMutex.Lock()
+	config := CustomGameOptimizedConfigurationVO{
+		Team1: make([]CustomGameParticipantVO, 0),
+		Team2: make([]CustomGameParticipantVO, 0),
+	}
+	addParticipant := func(team int, puuid, position string) {
+		participant := CustomGameParticipantVO{Puuid: puuid, Position: position}
+		if team == 1 {
+			config.Team1 = append(config.Team1, participant)
+		} else {
+			config.Team2 = append(config.Team2, participant)
+		}
+	}
+	for _, captain := range d.options.Captains {
+		addParticipant(captain.Team, captain.Puuid, captain.Position)
+	}
+	for _, pick := range d.Picks {
+		addParticipant(pick.Team, pick.Puuid, pick.Position)
+	}
+	customGameDraftsMutex.Unlock()
+
+	version, err := d.commit(config)
+
+	customGameDraftsMutex.Lock()
+	if err != nil {
+		log.Error(err)
+		d.end(CustomGameDraftStatusFailed, err)
+	} else {
+		d.end(CustomGameDraftStatusCompleted, nil)
+	}
+	doneData := d.doneData()
+	customGameDraftsMutex.Unlock()
+
+	socket.SocketIO.BroadcastToCustomConfigRoom(d.ConfigId, socket.EventCustomConfigDraftDone, doneData)
+	if err == nil {
+		socket.SocketIO.BroadcastToCustomConfigRoom(d.ConfigId, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+			Version: version,
+		})
+	}
+}
+
+// commit replaces participants with drafted teams in a short transaction, and returns new version
+func (d *customGameDraft) commit(config CustomGameOptimizedConfigurationVO) (int, error) {
+	tx, err := db.Root.BeginTxx(d.ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	// edits are blocked while drafting, but configuration may still be changed by background jobs (e.g. optimization)
+	version, err := IncreaseCustomGameVersion(tx, d.ConfigId, &d.options.Version)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	before, err := GetCustomGameArrangementSnapshot(tx, d.ConfigId)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if err := applyCustomGameArrangement(tx, d.ConfigId, config, true); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if err := RecalculateCustomGameBalance(tx, d.ConfigId); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if err := RecordCustomGameRevision(tx, d.ConfigId, &d.options.ActorUid, types.CustomGameOperationDraft, *before); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	return version, nil
+}
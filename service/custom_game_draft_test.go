@@ -0,0 +1,169 @@
+package service
+
+import (
+	"errors"
+	socketio "github.com/googollee/go-socket.io"
+	"reflect"
+	"team.gg-server/controllers/socket"
+	"team.gg-server/types"
+	"testing"
+	"time"
+)
+
+// newTestCustomGameDraft registers running draft of 2 captains (top of team 1, mid of team 2) and 8 players,
+// players are equally rated & have no favor (auto pick takes first available player on first free position)
+func newTestCustomGameDraft(t *testing.T, configId string, turnSeconds int) *customGameDraft {
+	// picks are broadcast to room nobody joined
+	socket.SocketIO.Io = socketio.NewServer(nil)
+
+	captains := [2]CustomGameDraftCaptainVO{
+		{Team: 1, Puuid: "captain1", Uid: "user1", Position: types.PositionTop},
+		{Team: 2, Puuid: "captain2", Uid: "user2", Position: types.PositionMid},
+	}
+	pickOrder := getCustomGameDraftPickOrder(types.CustomGameDraftOrderSnake)
+	candidates := make(map[string]CustomGameTeamParticipantVO)
+	available := make([]string, 0, len(pickOrder))
+	for i := range pickOrder {
+		puuid := string(rune('a' + i))
+		available = append(available, puuid)
+		candidates[puuid] = CustomGameTeamParticipantVO{}
+	}
+	draft := &customGameDraft{
+		CustomGameDraftVO: CustomGameDraftVO{
+			Id:          configId + "-draft",
+			ConfigId:    configId,
+			Status:      CustomGameDraftStatusDrafting,
+			Order:       types.CustomGameDraftOrderSnake,
+			TurnSeconds: turnSeconds,
+			Captains:    captains[:],
+			Picks:       make([]CustomGameDraftPickVO, 0, len(pickOrder)),
+			Available:   available,
+			StartedAt:   time.Now(),
+		},
+		options:    CustomGameDraftOptions{Order: types.CustomGameDraftOrderSnake, TurnSeconds: turnSeconds, Captains: captains},
+		pickOrder:  pickOrder,
+		candidates: candidates,
+	}
+
+	customGameDraftsMutex.Lock()
+	customGameDrafts[configId] = draft
+	draft.startTurn()
+	customGameDraftsMutex.Unlock()
+
+	t.Cleanup(func() {
+		_ = CancelCustomGameDraft(configId, draft.Id)
+	})
+	return draft
+}
+
+func TestGetCustomGameDraftPickOrder(t *testing.T) {
+	tests := map[string][]int{
+		types.CustomGameDraftOrderSnake:       {1, 2, 2, 1, 1, 2, 2, 1},
+		types.CustomGameDraftOrderAlternating: {1, 2, 1, 2, 1, 2, 1, 2},
+	}
+	for order, expected := range tests {
+		if actual := getCustomGameDraftPickOrder(order); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected %v, got %v", order, expected, actual)
+		}
+	}
+}
+
+func TestStartCustomGameDraftValidation(t *testing.T) {
+	valid := CustomGameDraftOptions{
+		Order:       types.CustomGameDraftOrderSnake,
+		TurnSeconds: types.CustomGameDraftDefaultTurnSeconds,
+		Captains: [2]CustomGameDraftCaptainVO{
+			{Puuid: "captain1", Uid: "user1", Position: types.PositionTop},
+			{Puuid: "captain2", Uid: "user2", Position: types.PositionMid},
+		},
+	}
+	invalidOrder := valid
+	invalidOrder.Order = "RANDOM"
+	sameCaptain := valid
+	sameCaptain.Captains[1].Puuid = "captain1"
+	invalidPosition := valid
+	invalidPosition.Captains[1].Position = "BOTTOM"
+
+	// rejected before configuration is read
+	tests := []struct {
+		name     string
+		options  CustomGameDraftOptions
+		expected error
+	}{
+		{"invalid order", invalidOrder, ErrCustomGameDraftInvalidOrder},
+		{"same captain", sameCaptain, ErrCustomGameDraftInvalidCaptain},
+		{"invalid captain position", invalidPosition, ErrCustomGameDraftInvalidCaptain},
+	}
+	for _, test := range tests {
+		if _, err := StartCustomGameDraft(nil, "validation", test.options); !errors.Is(err, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, err)
+		}
+	}
+}
+
+func TestPickCustomGameDraft(t *testing.T) {
+	draft := newTestCustomGameDraft(t, "pick", types.CustomGameDraftMaxTurnSeconds)
+
+	if _, err := PickCustomGameDraft("pick", "other-draft", "user1", "a", ""); !errors.Is(err, ErrCustomGameDraftNotFound) {
+		t.Errorf("other draft: expected %v, got %v", ErrCustomGameDraftNotFound, err)
+	}
+	if _, err := PickCustomGameDraft("pick", draft.Id, "user2", "a", ""); !errors.Is(err, ErrCustomGameDraftNotYourTurn) {
+		t.Errorf("captain of other team: expected %v, got %v", ErrCustomGameDraftNotYourTurn, err)
+	}
+	if _, err := PickCustomGameDraft("pick", draft.Id, "user1", "captain2", ""); !errors.Is(err, ErrCustomGameDraftInvalidPick) {
+		t.Errorf("unavailable player: expected %v, got %v", ErrCustomGameDraftInvalidPick, err)
+	}
+	if _, err := PickCustomGameDraft("pick", draft.Id, "user1", "a", types.PositionTop); !errors.Is(err, ErrCustomGameDraftInvalidPick) {
+		t.Errorf("position of captain: expected %v, got %v", ErrCustomGameDraftInvalidPick, err)
+	}
+
+	// snake order: team 2 picks twice after first pick
+	for _, pick := range []struct {
+		uid, puuid, position string
+		turnTeam             int
+	}{
+		{"user1", "a", types.PositionJungle, 2},
+		{"user2", "b", types.PositionTop, 2},
+		{"user2", "c", "", 1},
+	} {
+		vo, err := PickCustomGameDraft("pick", draft.Id, pick.uid, pick.puuid, pick.position)
+		if err != nil {
+			t.Fatalf("pick %s: %v", pick.puuid, err)
+		}
+		if vo.TurnTeam != pick.turnTeam {
+			t.Errorf("pick %s: expected turn of team %d, got %d", pick.puuid, pick.turnTeam, vo.TurnTeam)
+		}
+	}
+
+	vo, _ := GetCustomGameDraftVO("pick")
+	// position of player without favor is first free position of team 2 (top & mid are taken)
+	if last := vo.Picks[len(vo.Picks)-1]; last.Team != 2 || last.Position != types.PositionJungle || last.Auto {
+		t.Errorf("expected manual pick of jungle for team 2, got %+v", last)
+	}
+	if len(vo.Available) != 5 {
+		t.Errorf("expected 5 available players, got %d", len(vo.Available))
+	}
+}
+
+func TestCustomGameDraftAutoPick(t *testing.T) {
+	newTestCustomGameDraft(t, "auto-pick", 1)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		vo, _ := GetCustomGameDraftVO("auto-pick")
+		if len(vo.Picks) > 0 {
+			pick := vo.Picks[0]
+			if !pick.Auto || pick.Team != 1 || pick.Puuid != "a" || pick.Position != types.PositionJungle {
+				t.Errorf("expected auto pick of first player on jungle for team 1, got %+v", pick)
+			}
+			if vo.TurnTeam != 2 || vo.TurnEndsAt == nil {
+				t.Errorf("expected timed turn of team 2, got team %d", vo.TurnTeam)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("turn timed out without auto pick")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/types"
+	"team.gg-server/util"
+)
+
+// fairness model scores arrangement of custom game (0~1 each, higher is better).
+// total fairness is weighted sum of line fairness, tier fairness and line satisfaction,
+// lowered by champion pool penalty if weighted (see customGameChampionPoolPenalty).
+// optimizer prunes search space with upper bound built from the model, so implementation should keep that
+// line fairness <= 1, tier fairness depends only on team split, and line satisfaction grows with sum of favor weights
+// (penalty only lowers fairness, so bound stays valid).
+
+// FairnessModel scores arrangements of custom game, selected per configuration
+type FairnessModel interface {
+	// Evaluate is called for every candidate arrangement while optimizing, so keep it allocation-free
+	Evaluate(slots []customGameFairnessSlot, weights CustomGameConfigurationWeightsVO) CustomGameConfigurationBalanceVO
+	// TierFairness scores team split by sum of rating points & count of participants of each team
+	TierFairness(team1TierScore, team2TierScore float64, team1Size, team2Size int) float64
+	// FavorWeight returns satisfaction of participant playing position of given favor (-1~2)
+	FavorWeight(favor int) float64
+	// LineSatisfaction normalizes sum of favor weights of participants (0~1)
+	LineSatisfaction(favorWeightSum float64) float64
+}
+
+var (
+	defaultCustomGameFairnessModel = &customGameDefaultFairnessModel{
+		favorWeights:    [4]float64{0.0, 1.0, 2.0, 4.0},
+		lineScale:       700,
+		tierScale:       0.35,
+		mergeBottomLane: true,
+	}
+	winProbabilityCustomGameFairnessModel = &customGameWinProbabilityFairnessModel{
+		customGameDefaultFairnessModel: *defaultCustomGameFairnessModel,
+		favorRatingOffsets:             [4]float64{-300, -150, 0, 50},
+		ratingScale:                    800,
+	}
+)
+
+// GetCustomGameFairnessModel returns fairness model by name (default model if unknown)
+func GetCustomGameFairnessModel(name string) FairnessModel {
+	switch name {
+	case types.CustomGameFairnessModelWinProbability:
+		return winProbabilityCustomGameFairnessModel
+	default:
+		return defaultCustomGameFairnessModel
+	}
+}
+
+func IsValidCustomGameFairnessModel(name string) bool {
+	return name == types.CustomGameFairnessModelDefault || name == types.CustomGameFairnessModelWinProbability
+}
+
+// SetCustomGameFairnessModel changes fairness model of configuration, and recalculates balance by it
+func SetCustomGameFairnessModel(tx db.Context, configId string, name string) error {
+	if !IsValidCustomGameFairnessModel(name) {
+		return fmt.Errorf("invalid fairness model: %s", name)
+	}
+	configDAO, exists, err := models.GetCustomGameDAO_byId(tx, configId)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrCustomGameConfigurationNotExists
+	}
+	configDAO.FairnessModel = name
+	if err := configDAO.Upsert(tx); err != nil {
+		return err
+	}
+	return RecalculateCustomGameBalance(tx, configId)
+}
+
+// customGameFairnessSlot is participant placed on (team, position), minimized for fairness calculation
+type customGameFairnessSlot struct {
+	Team          int
+	Position      string
+	RatingPoint   float64 // for tier fairness
+	PositionFavor CustomGameCandidatePositionFavorVO
+	// for line fairness (differs from rating point only if in-house rating of position is blended)
+	LineRatingPoints customGameLineRatingPoints
+	// penalties of champion pools (nil if not computed), participant of slot is found by index
+	ChampionPoolTable *customGameChampionPoolTable
+	ChampionPoolIndex int
+}
+
+type customGameLineRatingPoints struct {
+	Top     float64
+	Jungle  float64
+	Mid     float64
+	Adc     float64
+	Support float64
+}
+
+func newCustomGameFairnessSlot(participant CustomGameTeamParticipantVO, weights CustomGameConfigurationWeightsVO) customGameFairnessSlot {
+	return customGameFairnessSlot{
+		Team:          participant.Team,
+		Position:      participant.Position,
+		RatingPoint:   participant.GetBalanceRatingPoint(types.CustomGameRatingPositionAll, weights.InHouseRating),
+		PositionFavor: participant.PositionFavor,
+		LineRatingPoints: customGameLineRatingPoints{
+			Top:     participant.GetBalanceRatingPoint(types.PositionTop, weights.InHouseRating),
+			Jungle:  participant.GetBalanceRatingPoint(types.PositionJungle, weights.InHouseRating),
+			Mid:     participant.GetBalanceRatingPoint(types.PositionMid, weights.InHouseRating),
+			Adc:     participant.GetBalanceRatingPoint(types.PositionAdc, weights.InHouseRating),
+			Support: participant.GetBalanceRatingPoint(types.PositionSupport, weights.InHouseRating),
+		},
+	}
+}
+
+// lineRatingPoint returns rating point of slot on its position
+func (s *customGameFairnessSlot) lineRatingPoint() float64 {
+	switch s.Position {
+	case types.PositionTop:
+		return s.LineRatingPoints.Top
+	case types.PositionJungle:
+		return s.LineRatingPoints.Jungle
+	case types.PositionMid:
+		return s.LineRatingPoints.Mid
+	case types.PositionAdc:
+		return s.LineRatingPoints.Adc
+	case types.PositionSupport:
+		return s.LineRatingPoints.Support
+	default:
+		return 0
+	}
+}
+
+// championPoolKey returns key of slot in champion pool table (-1 if position is unknown)
+func (s *customGameFairnessSlot) championPoolKey() int {
+	positionIndex := getPositionIndex(s.Position)
+	if positionIndex < 0 {
+		return -1
+	}
+	return s.ChampionPoolIndex*len(GetSupportedPositions) + positionIndex
+}
+
+func getPositionInfluence(weights CustomGameConfigurationWeightsVO, position string) float64 {
+	switch position {
+	case types.PositionTop:
+		return weights.TopInfluence
+	case types.PositionJungle:
+		return weights.JungleInfluence
+	case types.PositionMid:
+		return weights.MidInfluence
+	case types.PositionAdc:
+		return weights.AdcInfluence
+	case types.PositionSupport:
+		return weights.SupportInfluence
+	default:
+		return 0
+	}
+}
+
+func calculateCustomGameConfigFairness(
+	teamParticipantMap map[string]CustomGameTeamParticipantVO,
+	weights CustomGameConfigurationWeightsVO,
+	model FairnessModel) (*CustomGameConfigurationBalanceVO, error) {
+	slots := make([]customGameFairnessSlot, 0, len(teamParticipantMap))
+	pools := make([]CustomGameChampionPoolVO, 0, len(teamParticipantMap))
+	for _, participant := range teamParticipantMap {
+		slots = append(slots, newCustomGameFairnessSlot(participant, weights))
+		pools = append(pools, participant.ChampionPool)
+	}
+	setCustomGameChampionPoolTable(slots, pools)
+	balance := model.Evaluate(slots, weights)
+	return &balance, nil
+}
+
+// customGameDefaultFairnessModel compares favor-weighted line scores of each position,
+// and total rating points of each team.
+type customGameDefaultFairnessModel struct {
+	favorWeights    [4]float64 // by favor -1, 0, 1, 2
+	lineScale       float64    // line score difference where line fairness becomes 0.5
+	tierScale       float64    // scaled tier score difference rate where tier fairness becomes 0.5
+	mergeBottomLane bool       // compare adc & support as a single bottom lane
+}
+
+func (m *customGameDefaultFairnessModel) FavorWeight(favor int) float64 {
+	if favor < -1 || favor > 2 {
+		return 0.0
+	}
+	return m.favorWeights[favor+1]
+}
+
+// regularize line satisfaction (0~20) * 2 -> (0~1) -> (0~1) (biased to 1)
+func (m *customGameDefaultFairnessModel) LineSatisfaction(favorWeightSum float64) float64 {
+	return math.Sqrt(favorWeightSum / (m.favorWeights[3] * 10))
+}
+
+// tier fairness depends only on which team each participant belongs to (not on positions)
+func (m *customGameDefaultFairnessModel) TierFairness(team1TierScore, team2TierScore float64, _, _ int) float64 {
+	var tierFairness float64 = 0
+	tierScoreDiff := math.Abs(team1TierScore - team2TierScore)
+	maxTierScore := math.Max(team1TierScore, team2TierScore)
+	if maxTierScore != 0 {
+		tierScoreDiffRate := tierScoreDiff / maxTierScore
+		if tierScoreDiffRate == 1 {
+			tierFairness = 0
+		} else {
+			scaledDiffRate := util.PolynomialToInfiniteScale(tierScoreDiffRate)
+			tierFairness = util.LogisticNormalize(scaledDiffRate, m.tierScale)
+		}
+	}
+	return tierFairness
+}
+
+func (m *customGameDefaultFairnessModel) Evaluate(slots []customGameFairnessSlot, weights CustomGameConfigurationWeightsVO) CustomGameConfigurationBalanceVO {
+	var team1LineScore float64 = 0
+	var team2LineScore float64 = 0
+
+	var team1TierScore float64 = 0
+	var team2TierScore float64 = 0
+	team1Size, team2Size := 0, 0
+
+	// line score of each position (by position index)
+	var team1PositionScores [5]float64
+	var team2PositionScores [5]float64
+
+	var lineSatisfaction float64 = 0
+
+	for i := range slots {
+		slot := &slots[i]
+		positionIndex := getPositionIndex(slot.Position)
+		var score float64 = 0
+		if positionIndex >= 0 {
+			favorWeight := m.FavorWeight(getPositionFavor(slot.PositionFavor, slot.Position))
+			lineSatisfaction += favorWeight
+			score = favorWeight * slot.lineRatingPoint() * getPositionInfluence(weights, slot.Position)
+			if slot.Team == 1 {
+				team1PositionScores[positionIndex] = score
+			} else {
+				team2PositionScores[positionIndex] = score
+			}
+		}
+
+		if slot.Team == 1 {
+			team1LineScore += score
+			team1TierScore += slot.RatingPoint
+			team1Size++
+		} else {
+			team2LineScore += score
+			team2TierScore += slot.RatingPoint
+			team2Size++
+		}
+	}
+
+	var lineScoreDiffSum float64 = 0
+	for p := 0; p < 3; p++ {
+		lineScoreDiffSum += math.Pow(team1PositionScores[p]-team2PositionScores[p], 2.0)
+	}
+	if m.mergeBottomLane {
+		bottomWeightSum := weights.AdcInfluence + weights.SupportInfluence
+		team1BottomScore := (team1PositionScores[3]*weights.AdcInfluence + team1PositionScores[4]*weights.SupportInfluence) / bottomWeightSum
+		team2BottomScore := (team2PositionScores[3]*weights.AdcInfluence + team2PositionScores[4]*weights.SupportInfluence) / bottomWeightSum
+		lineScoreDiffSum += math.Pow(team1BottomScore-team2BottomScore, 2.0)
+	} else {
+		for p := 3; p < 5; p++ {
+			lineScoreDiffSum += math.Pow(team1PositionScores[p]-team2PositionScores[p], 2.0)
+		}
+	}
+
+	// regularize (0~inf) -> (0~1)
+	var lineFairness float64 = 0
+	lineScoreDiffSum = math.Sqrt(lineScoreDiffSum)
+	if team1LineScore == 0 || team2LineScore == 0 {
+		lineFairness = 0
+	} else {
+		lineFairness = util.LogisticNormalize(lineScoreDiffSum, m.lineScale)
+	}
+
+	lineSatisfactionScore := m.LineSatisfaction(lineSatisfaction)
+	tierFairness := m.TierFairness(team1TierScore, team2TierScore, team1Size, team2Size)
+
+	totalFairness := lineFairness*weights.LineFairness + tierFairness*weights.TierFairness + lineSatisfactionScore*weights.LineSatisfaction
+	if weights.ChampionPool > 0 {
+		totalFairness = math.Max(totalFairness-customGameChampionPoolPenalty(slots)*weights.ChampionPool, 0)
+	}
+	return CustomGameConfigurationBalanceVO{
+		Fairness:         totalFairness,
+		LineFairness:     lineFairness,
+		TierFairness:     tierFairness,
+		LineSatisfaction: lineSatisfactionScore,
+	}
+}
+
+// customGameWinProbabilityFairnessModel predicts win probability of team 1 from rating differences of each lane
+// (player on unfavored position plays below own rating), and scores how close it is to even.
+// line satisfaction is same as default model.
+type customGameWinProbabilityFairnessModel struct {
+	customGameDefaultFairnessModel
+	favorRatingOffsets [4]float64 // rating point added to player on position of favor -1, 0, 1, 2
+	ratingScale        float64    // rating point difference making odds of win 10:1 (elo-like)
+}
+
+// winProbability returns probability of team 1 to win, by rating difference of teams
+func (m *customGameWinProbabilityFairnessModel) winProbability(ratingDiff float64) float64 {
+	return 1 / (1 + math.Pow(10, -ratingDiff/m.ratingScale))
+}
+
+// evenness maps win probability to 0~1 (1 if 50:50, 0 if one-sided)
+func (m *customGameWinProbabilityFairnessModel) evenness(winProbability float64) float64 {
+	return 1 - math.Abs(2*winProbability-1)
+}
+
+// TierFairness compares average rating points of teams
+func (m *customGameWinProbabilityFairnessModel) TierFairness(team1TierScore, team2TierScore float64, team1Size, team2Size int) float64 {
+	if math.Max(team1TierScore, team2TierScore) == 0 {
+		return 0
+	}
+	return m.evenness(m.winProbability(averageTierScore(team1TierScore, team1Size) - averageTierScore(team2TierScore, team2Size)))
+}
+
+// averageTierScore returns average rating point of team (0 if team is empty)
+func averageTierScore(tierScore float64, size int) float64 {
+	if size == 0 {
+		return 0
+	}
+	return tierScore / float64(size)
+}
+
+// positionRatingDiffs returns rating difference of team 1 over team 2 on each position (by position index, favor applied),
+// and whether both teams have participants
+func (m *customGameWinProbabilityFairnessModel) positionRatingDiffs(slots []customGameFairnessSlot) ([5]float64, bool) {
+	var ratingDiffs [5]float64
+	team1Exists, team2Exists := false, false
+	for i := range slots {
+		slot := &slots[i]
+		positionIndex := getPositionIndex(slot.Position)
+		if positionIndex < 0 {
+			continue
+		}
+		favor := getPositionFavor(slot.PositionFavor, slot.Position)
+		if favor < -1 || favor > 2 {
+			continue
+		}
+		rating := slot.lineRatingPoint() + m.favorRatingOffsets[favor+1]
+		if slot.Team == 1 {
+			ratingDiffs[positionIndex] += rating
+			team1Exists = true
+		} else {
+			ratingDiffs[positionIndex] -= rating
+			team2Exists = true
+		}
+	}
+	return ratingDiffs, team1Exists && team2Exists
+}
+
+// laneRatingDiff returns influence-weighted rating difference of team 1 over team 2 (favor applied)
+func (m *customGameWinProbabilityFairnessModel) laneRatingDiff(slots []customGameFairnessSlot, weights CustomGameConfigurationWeightsVO) (float64, bool) {
+	ratingDiffs, ok := m.positionRatingDiffs(slots)
+	var ratingDiff float64 = 0
+	for p, position := range GetSupportedPositions {
+		ratingDiff += ratingDiffs[p] * getPositionInfluence(weights, position)
+	}
+	return ratingDiff, ok
+}
+
+func (m *customGameWinProbabilityFairnessModel) Evaluate(slots []customGameFairnessSlot, weights CustomGameConfigurationWeightsVO) CustomGameConfigurationBalanceVO {
+	var team1TierScore float64 = 0
+	var team2TierScore float64 = 0
+	team1Size, team2Size := 0, 0
+	var lineSatisfaction float64 = 0
+	for i := range slots {
+		slot := &slots[i]
+		lineSatisfaction += m.FavorWeight(getPositionFavor(slot.PositionFavor, slot.Position))
+		if slot.Team == 1 {
+			team1TierScore += slot.RatingPoint
+			team1Size++
+		} else {
+			team2TierScore += slot.RatingPoint
+			team2Size++
+		}
+	}
+
+	var lineFairness float64 = 0
+	if ratingDiff, ok := m.laneRatingDiff(slots, weights); ok {
+		lineFairness = m.evenness(m.winProbability(ratingDiff))
+	}
+	lineSatisfactionScore := m.LineSatisfaction(lineSatisfaction)
+	tierFairness := m.TierFairness(team1TierScore, team2TierScore, team1Size, team2Size)
+
+	totalFairness := lineFairness*weights.LineFairness + tierFairness*weights.TierFairness + lineSatisfactionScore*weights.LineSatisfaction
+	if weights.ChampionPool > 0 {
+		totalFairness = math.Max(totalFairness-customGameChampionPoolPenalty(slots)*weights.ChampionPool, 0)
+	}
+	return CustomGameConfigurationBalanceVO{
+		Fairness:         totalFairness,
+		LineFairness:     lineFairness,
+		TierFairness:     tierFairness,
+		LineSatisfaction: lineSatisfactionScore,
+	}
+}
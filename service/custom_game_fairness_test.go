@@ -0,0 +1,249 @@
+package service
+
+import (
+	"math"
+	"team.gg-server/types"
+	"testing"
+)
+
+// scores of known arrangements are pinned, so that change of fairness models is always intended
+
+const customGameFairnessTestTolerance = 1e-9
+
+var customGameFairnessTestWeights = CustomGameConfigurationWeightsVO{
+	LineFairness:     types.WeightLineFairness,
+	TierFairness:     types.WeightTierFairness,
+	LineSatisfaction: types.WeightLineSatisfaction,
+	TopInfluence:     types.WeightTopInfluence,
+	JungleInfluence:  types.WeightJungleInfluence,
+	MidInfluence:     types.WeightMidInfluence,
+	AdcInfluence:     types.WeightAdcInfluence,
+	SupportInfluence: types.WeightSupportInfluence,
+}
+
+// newTestCustomGameTeamSlots places team members on top, jungle, mid, adc, support in order,
+// everyone favoring every position by given favor
+func newTestCustomGameTeamSlots(team int, ratingPoints [5]float64, favor int) []customGameFairnessSlot {
+	slots := make([]customGameFairnessSlot, 0, len(ratingPoints))
+	for p, position := range GetSupportedPositions {
+		ratingPoint := ratingPoints[p]
+		slots = append(slots, customGameFairnessSlot{
+			Team:        team,
+			Position:    position,
+			RatingPoint: ratingPoint,
+			PositionFavor: CustomGameCandidatePositionFavorVO{
+				Top:     favor,
+				Jungle:  favor,
+				Mid:     favor,
+				Adc:     favor,
+				Support: favor,
+			},
+			LineRatingPoints: customGameLineRatingPoints{
+				Top:     ratingPoint,
+				Jungle:  ratingPoint,
+				Mid:     ratingPoint,
+				Adc:     ratingPoint,
+				Support: ratingPoint,
+			},
+		})
+	}
+	return slots
+}
+
+func newTestCustomGameArrangement(team1 []customGameFairnessSlot, team2 []customGameFairnessSlot) []customGameFairnessSlot {
+	slots := make([]customGameFairnessSlot, 0, len(team1)+len(team2))
+	slots = append(slots, team1...)
+	return append(slots, team2...)
+}
+
+func TestFairnessModelEvaluate(t *testing.T) {
+	base := [5]float64{1600, 1500, 1700, 1400, 1300}
+	stronger := [5]float64{1800, 1700, 1900, 1600, 1500}
+
+	arrangements := map[string][]customGameFairnessSlot{
+		"mirrored teams": newTestCustomGameArrangement(
+			newTestCustomGameTeamSlots(1, base, 1),
+			newTestCustomGameTeamSlots(2, base, 1),
+		),
+		"stronger team 1": newTestCustomGameArrangement(
+			newTestCustomGameTeamSlots(1, stronger, 1),
+			newTestCustomGameTeamSlots(2, base, 1),
+		),
+		"team 2 on unfavored positions": newTestCustomGameArrangement(
+			newTestCustomGameTeamSlots(1, base, 1),
+			newTestCustomGameTeamSlots(2, base, -1),
+		),
+		"everyone neutral": newTestCustomGameArrangement(
+			newTestCustomGameTeamSlots(1, base, 0),
+			newTestCustomGameTeamSlots(2, base, 0),
+		),
+		"everyone on favorite, team 2 stronger": newTestCustomGameArrangement(
+			newTestCustomGameTeamSlots(1, base, 2),
+			newTestCustomGameTeamSlots(2, stronger, 2),
+		),
+		"adc & support swapped": newTestCustomGameArrangement(
+			newTestCustomGameTeamSlots(1, [5]float64{1600, 1500, 1700, 1700, 1200}, 1),
+			newTestCustomGameTeamSlots(2, [5]float64{1600, 1500, 1700, 1200, 1700}, 1),
+		),
+		"team 2 empty": newTestCustomGameTeamSlots(1, base, 1),
+	}
+
+	tests := []struct {
+		model       string
+		arrangement string
+		expected    CustomGameConfigurationBalanceVO
+	}{
+		{types.CustomGameFairnessModelDefault, "mirrored teams", CustomGameConfigurationBalanceVO{0.882842712474619, 1, 1, 0.707106781186548}},
+		{types.CustomGameFairnessModelDefault, "stronger team 1", CustomGameConfigurationBalanceVO{0.730332005148865, 0.808458380305131, 0.651851149018328, 0.707106781186548}},
+		{types.CustomGameFairnessModelDefault, "team 2 on unfavored positions", CustomGameConfigurationBalanceVO{0.44, 0, 1, 0.5}},
+		{types.CustomGameFairnessModelDefault, "everyone neutral", CustomGameConfigurationBalanceVO{0.8, 1, 1, 0.5}},
+		{types.CustomGameFairnessModelDefault, "everyone on favorite, team 2 stronger", CustomGameConfigurationBalanceVO{0.800703487611634, 0.678497810686765, 0.651851149018328, 1}},
+		{types.CustomGameFairnessModelDefault, "adc & support swapped", CustomGameConfigurationBalanceVO{0.86338325301516, 0.945945945945946, 1, 0.707106781186548}},
+		{types.CustomGameFairnessModelDefault, "team 2 empty", CustomGameConfigurationBalanceVO{0.2, 0, 0, 0.5}},
+
+		{types.CustomGameFairnessModelWinProbability, "mirrored teams", CustomGameConfigurationBalanceVO{0.882842712474619, 1, 1, 0.707106781186548}},
+		{types.CustomGameFairnessModelWinProbability, "stronger team 1", CustomGameConfigurationBalanceVO{0.714764712711157, 0.71987000039423, 0.71987000039423, 0.707106781186548}},
+		{types.CustomGameFairnessModelWinProbability, "team 2 on unfavored positions", CustomGameConfigurationBalanceVO{0.653562797500283, 0.593229993056343, 1, 0.5}},
+		{types.CustomGameFairnessModelWinProbability, "everyone neutral", CustomGameConfigurationBalanceVO{0.8, 1, 1, 0.5}},
+		{types.CustomGameFairnessModelWinProbability, "everyone on favorite, team 2 stronger", CustomGameConfigurationBalanceVO{0.831922000236538, 0.71987000039423, 0.71987000039423, 1}},
+		{types.CustomGameFairnessModelWinProbability, "adc & support swapped", CustomGameConfigurationBalanceVO{0.8724839398751, 0.971225631668003, 1, 0.707106781186548}},
+		{types.CustomGameFairnessModelWinProbability, "team 2 empty", CustomGameConfigurationBalanceVO{0.206316668742899, 0, 0.0263194530954109, 0.5}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.model+"/"+test.arrangement, func(t *testing.T) {
+			model := GetCustomGameFairnessModel(test.model)
+			actual := model.Evaluate(arrangements[test.arrangement], customGameFairnessTestWeights)
+			assertCustomGameBalance(t, test.expected, actual)
+		})
+	}
+}
+
+func TestFairnessModelTierFairness(t *testing.T) {
+	tests := []struct {
+		model          string
+		team1TierScore float64
+		team2TierScore float64
+		team1Size      int
+		team2Size      int
+		expected       float64
+	}{
+		{types.CustomGameFairnessModelDefault, 7500, 7500, 5, 5, 1},
+		{types.CustomGameFairnessModelDefault, 8500, 7500, 5, 5, 0.651851149018328},
+		{types.CustomGameFairnessModelDefault, 7500, 0, 5, 0, 0},
+		{types.CustomGameFairnessModelDefault, 0, 0, 0, 0, 0},
+		{types.CustomGameFairnessModelWinProbability, 7500, 7500, 5, 5, 1},
+		{types.CustomGameFairnessModelWinProbability, 8500, 7500, 5, 5, 0.71987000039423},
+		// team 1 isn't full, but its players are 200 points higher on average (same as above)
+		{types.CustomGameFairnessModelWinProbability, 6800, 7500, 4, 5, 0.71987000039423},
+		{types.CustomGameFairnessModelWinProbability, 0, 0, 0, 0, 0},
+	}
+
+	for _, test := range tests {
+		model := GetCustomGameFairnessModel(test.model)
+		actual := model.TierFairness(test.team1TierScore, test.team2TierScore, test.team1Size, test.team2Size)
+		if math.Abs(actual-test.expected) > customGameFairnessTestTolerance {
+			t.Errorf("%s (%v vs %v): expected %.15g, got %.15g", test.model, test.team1TierScore, test.team2TierScore, test.expected, actual)
+		}
+		// tier fairness is symmetric
+		mirrored := model.TierFairness(test.team2TierScore, test.team1TierScore, test.team2Size, test.team1Size)
+		if math.Abs(actual-mirrored) > customGameFairnessTestTolerance {
+			t.Errorf("%s (%v vs %v): not symmetric, %.15g vs %.15g", test.model, test.team1TierScore, test.team2TierScore, actual, mirrored)
+		}
+	}
+}
+
+func TestGetCustomGameFairnessModel(t *testing.T) {
+	if GetCustomGameFairnessModel("") != defaultCustomGameFairnessModel {
+		t.Error("empty model name should fall back to default model")
+	}
+	if GetCustomGameFairnessModel("UNKNOWN") != defaultCustomGameFairnessModel {
+		t.Error("unknown model name should fall back to default model")
+	}
+	if GetCustomGameFairnessModel(types.CustomGameFairnessModelWinProbability) != winProbabilityCustomGameFairnessModel {
+		t.Error("win probability model is not selected")
+	}
+}
+
+func assertCustomGameBalance(t *testing.T, expected, actual CustomGameConfigurationBalanceVO) {
+	t.Helper()
+	pairs := []struct {
+		name     string
+		expected float64
+		actual   float64
+	}{
+		{"fairness", expected.Fairness, actual.Fairness},
+		{"line fairness", expected.LineFairness, actual.LineFairness},
+		{"tier fairness", expected.TierFairness, actual.TierFairness},
+		{"line satisfaction", expected.LineSatisfaction, actual.LineSatisfaction},
+	}
+	for _, pair := range pairs {
+		if math.Abs(pair.expected-pair.actual) > customGameFairnessTestTolerance {
+			t.Errorf("%s: expected %.15g, got %.15g", pair.name, pair.expected, pair.actual)
+		}
+	}
+}
+
+// newTestCustomGameChampionPool gives same pool of given champions (full proficiency each) on every position
+func newTestCustomGameChampionPool(championIds ...int64) CustomGameChampionPoolVO {
+	positionPool := CustomGamePositionChampionPoolVO{
+		Depth:     float64(len(championIds)),
+		Champions: make([]CustomGamePoolChampionVO, 0, len(championIds)),
+	}
+	for _, championId := range championIds {
+		positionPool.Champions = append(positionPool.Champions, CustomGamePoolChampionVO{ChampionId: championId, Proficiency: 1})
+	}
+	return CustomGameChampionPoolVO{Known: true, Top: positionPool, Jungle: positionPool, Mid: positionPool, Adc: positionPool, Support: positionPool}
+}
+
+func TestCustomGameChampionPoolPenalty(t *testing.T) {
+	base := [5]float64{1600, 1500, 1700, 1400, 1300}
+	arrangement := func(pools ...CustomGameChampionPoolVO) []customGameFairnessSlot {
+		slots := newTestCustomGameArrangement(
+			newTestCustomGameTeamSlots(1, base, 1),
+			newTestCustomGameTeamSlots(2, base, 1),
+		)
+		slotPools := make([]CustomGameChampionPoolVO, len(slots))
+		for i := range slots {
+			slotPools[i] = pools[i%len(pools)]
+		}
+		setCustomGameChampionPoolTable(slots, slotPools)
+		return slots
+	}
+
+	tests := []struct {
+		name     string
+		slots    []customGameFairnessSlot
+		expected float64
+	}{
+		{"unknown pools", arrangement(CustomGameChampionPoolVO{}), 0},
+		{"deep distinct pools", arrangement(newTestCustomGameChampionPool(1, 2, 3), newTestCustomGameChampionPool(4, 5, 6)), 0},
+		{"no pool", arrangement(newTestCustomGameChampionPool()), 1},
+		// 1 thin position each (0.5), no conflict as pools are distinct
+		{"single champion pools", arrangement(
+			newTestCustomGameChampionPool(1), newTestCustomGameChampionPool(2), newTestCustomGameChampionPool(3),
+			newTestCustomGameChampionPool(4), newTestCustomGameChampionPool(5),
+		), 0.5},
+		// deep pools, but 2 teammates (top & mid of each team) depend on same 2 champions: 2 conflicts / 10 slots
+		{"shared narrow pools", arrangement(
+			newTestCustomGameChampionPool(1, 2), newTestCustomGameChampionPool(3, 4, 5), newTestCustomGameChampionPool(1, 2),
+			newTestCustomGameChampionPool(6, 7, 8), newTestCustomGameChampionPool(9, 10, 11),
+		), 0.2},
+	}
+
+	for _, test := range tests {
+		actual := customGameChampionPoolPenalty(test.slots)
+		if math.Abs(actual-test.expected) > customGameFairnessTestTolerance {
+			t.Errorf("%s: expected %.15g, got %.15g", test.name, test.expected, actual)
+		}
+	}
+
+	// penalty lowers fairness only if weighted
+	slots := arrangement(newTestCustomGameChampionPool())
+	unweighted := defaultCustomGameFairnessModel.Evaluate(slots, customGameFairnessTestWeights)
+	assertCustomGameBalance(t, CustomGameConfigurationBalanceVO{0.882842712474619, 1, 1, 0.707106781186548}, unweighted)
+	weights := customGameFairnessTestWeights
+	weights.ChampionPool = 0.5
+	weighted := defaultCustomGameFairnessModel.Evaluate(slots, weights)
+	assertCustomGameBalance(t, CustomGameConfigurationBalanceVO{0.382842712474619, 1, 1, 0.707106781186548}, weighted)
+}
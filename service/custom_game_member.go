@@ -0,0 +1,241 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/types"
+	"time"
+)
+
+// creator of configuration is always owner, other users get their role by accepting invites.
+// owners manage members and invites, editors edit configuration, viewers only view it.
+
+var (
+	ErrCustomGameInviteNotFound = errors.New("invite not found or expired")
+	ErrCustomGameInvalidRole    = errors.New("invalid role")
+	ErrCustomGameMemberNotFound = errors.New("member not found")
+	ErrCustomGameCreatorRole    = errors.New("role of creator can't be changed")
+)
+
+var customGameRoleLevels = map[string]int{
+	types.CustomGameRoleViewer: 1,
+	types.CustomGameRoleEditor: 2,
+	types.CustomGameRoleOwner:  3,
+}
+
+// IsValidCustomGameMemberRole checks role can be given to member
+func IsValidCustomGameMemberRole(role string) bool {
+	_, exists := customGameRoleLevels[role]
+	return exists
+}
+
+// IsCustomGameRoleAtLeast checks role has privileges of required role
+func IsCustomGameRoleAtLeast(role string, required string) bool {
+	return customGameRoleLevels[role] >= customGameRoleLevels[required]
+}
+
+// GetCustomGameRole returns role of user on configuration, false if user is not a member
+func GetCustomGameRole(db db.Context, configDAO models.CustomGameConfigurationDAO, uid string) (string, bool, error) {
+	if configDAO.CreatorUid == uid {
+		return types.CustomGameRoleOwner, true, nil
+	}
+	memberDAO, exists, err := models.GetCustomGameMemberDAO(db, configDAO.Id, uid)
+	if err != nil {
+		return "", false, err
+	}
+	if !exists {
+		return "", false, nil
+	}
+	return memberDAO.Role, true, nil
+}
+
+// CheckRoleForCustomGameConfig checks user has at least required role on configuration
+func CheckRoleForCustomGameConfig(db db.Context, configId string, uid string, required string) (bool, error) {
+	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(db, configId)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, nil
+	}
+	role, exists, err := GetCustomGameRole(db, *customGameConfigurationDAO, uid)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, nil
+	}
+	return IsCustomGameRoleAtLeast(role, required), nil
+}
+
+func GetCustomGameMemberVOs(db db.Context, configDAO models.CustomGameConfigurationDAO) ([]CustomGameMemberVO, error) {
+	creatorDAO, exists, err := models.GetUserDAO_byUid(db, configDAO.CreatorUid)
+	if err != nil {
+		return nil, err
+	}
+	memberVOs := make([]CustomGameMemberVO, 0)
+	if exists {
+		memberVOs = append(memberVOs, CustomGameMemberVO{
+			Uid:      creatorDAO.Uid,
+			UserId:   creatorDAO.UserId,
+			Role:     types.CustomGameRoleOwner,
+			JoinedAt: configDAO.CreatedAt,
+		})
+	}
+
+	memberDAOs, err := models.GetCustomGameMemberDAOs_byCustomGameConfigId(db, configDAO.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, memberDAO := range memberDAOs {
+		userDAO, exists, err := models.GetUserDAO_byUid(db, memberDAO.Uid)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		memberVOs = append(memberVOs, CustomGameMemberVO{
+			Uid:      memberDAO.Uid,
+			UserId:   userDAO.UserId,
+			Role:     memberDAO.Role,
+			JoinedAt: memberDAO.JoinedAt,
+		})
+	}
+	return memberVOs, nil
+}
+
+// CreateCustomGameInvite creates invite link token which gives role to anyone accepting it until expiry
+func CreateCustomGameInvite(db db.Context, configId string, role string, creatorUid string, expiry time.Duration) (*CustomGameInviteVO, error) {
+	if !IsValidCustomGameMemberRole(role) {
+		return nil, ErrCustomGameInvalidRole
+	}
+	now := time.Now()
+	// expired invites are useless, cleaned up lazily
+	if err := models.DeleteExpiredCustomGameInviteDAOs(db, now); err != nil {
+		return nil, err
+	}
+	inviteDAO := models.CustomGameInviteDAO{
+		Token:              uuid.New().String(),
+		CustomGameConfigId: configId,
+		Role:               role,
+		CreatorUid:         creatorUid,
+		CreatedAt:          now,
+		ExpiresAt:          now.Add(expiry),
+	}
+	if err := inviteDAO.Insert(db); err != nil {
+		return nil, err
+	}
+	inviteVO := CustomGameInviteMixer(inviteDAO)
+	return &inviteVO, nil
+}
+
+func GetCustomGameInviteVOs(db db.Context, configId string) ([]CustomGameInviteVO, error) {
+	inviteDAOs, err := models.GetCustomGameInviteDAOs_byCustomGameConfigId(db, configId, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	inviteVOs := make([]CustomGameInviteVO, 0)
+	for _, inviteDAO := range inviteDAOs {
+		inviteVOs = append(inviteVOs, CustomGameInviteMixer(inviteDAO))
+	}
+	return inviteVOs, nil
+}
+
+// AcceptCustomGameInvite makes user member of invited configuration, and returns id of configuration.
+// invite never lowers role of user already having higher role.
+func AcceptCustomGameInvite(db db.Context, token string, uid string) (string, *CustomGameMemberVO, error) {
+	now := time.Now()
+	inviteDAO, exists, err := models.GetCustomGameInviteDAO_byToken(db, token)
+	if err != nil {
+		return "", nil, err
+	}
+	if !exists || !inviteDAO.ExpiresAt.After(now) {
+		return "", nil, ErrCustomGameInviteNotFound
+	}
+	configDAO, exists, err := models.GetCustomGameDAO_byId(db, inviteDAO.CustomGameConfigId)
+	if err != nil {
+		return "", nil, err
+	}
+	if !exists {
+		return "", nil, ErrCustomGameInviteNotFound
+	}
+	userDAO, exists, err := models.GetUserDAO_byUid(db, uid)
+	if err != nil {
+		return "", nil, err
+	}
+	if !exists {
+		return "", nil, ErrCustomGameMemberNotFound
+	}
+
+	memberVO := CustomGameMemberVO{
+		Uid:      uid,
+		UserId:   userDAO.UserId,
+		Role:     inviteDAO.Role,
+		JoinedAt: now,
+	}
+	if configDAO.CreatorUid == uid {
+		memberVO.Role = types.CustomGameRoleOwner
+		memberVO.JoinedAt = configDAO.CreatedAt
+		return configDAO.Id, &memberVO, nil
+	}
+	memberDAO, exists, err := models.GetCustomGameMemberDAO(db, configDAO.Id, uid)
+	if err != nil {
+		return "", nil, err
+	}
+	if exists && IsCustomGameRoleAtLeast(memberDAO.Role, inviteDAO.Role) {
+		memberVO.Role = memberDAO.Role
+		memberVO.JoinedAt = memberDAO.JoinedAt
+		return configDAO.Id, &memberVO, nil
+	}
+	if exists {
+		memberVO.JoinedAt = memberDAO.JoinedAt
+	}
+
+	newMemberDAO := models.CustomGameMemberDAO{
+		CustomGameConfigId: configDAO.Id,
+		Uid:                uid,
+		Role:               memberVO.Role,
+		JoinedAt:           memberVO.JoinedAt,
+	}
+	if err := newMemberDAO.Upsert(db); err != nil {
+		return "", nil, err
+	}
+	return configDAO.Id, &memberVO, nil
+}
+
+// SetCustomGameMemberRole changes role of existing member
+func SetCustomGameMemberRole(db db.Context, configDAO models.CustomGameConfigurationDAO, uid string, role string) error {
+	if !IsValidCustomGameMemberRole(role) {
+		return ErrCustomGameInvalidRole
+	}
+	if configDAO.CreatorUid == uid {
+		return ErrCustomGameCreatorRole
+	}
+	memberDAO, exists, err := models.GetCustomGameMemberDAO(db, configDAO.Id, uid)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrCustomGameMemberNotFound
+	}
+	memberDAO.Role = role
+	return memberDAO.Upsert(db)
+}
+
+// RemoveCustomGameMember removes member from configuration (creator can't be removed)
+func RemoveCustomGameMember(db db.Context, configDAO models.CustomGameConfigurationDAO, uid string) error {
+	if configDAO.CreatorUid == uid {
+		return ErrCustomGameCreatorRole
+	}
+	memberDAO, exists, err := models.GetCustomGameMemberDAO(db, configDAO.Id, uid)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrCustomGameMemberNotFound
+	}
+	return memberDAO.Delete(db)
+}
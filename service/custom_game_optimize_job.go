@@ -0,0 +1,432 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	log "github.com/shyunku-libraries/go-logger"
+	"sync"
+	"team.gg-server/controllers/socket"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/types"
+	"time"
+)
+
+// optimization runs as server-side job (at most one per configuration),
+// progress & completion are broadcast to custom config room, and result is committed in a short transaction.
+
+const (
+	CustomGameOptimizeJobStatusRunning   = "running"
+	CustomGameOptimizeJobStatusSucceeded = "succeeded"
+	CustomGameOptimizeJobStatusFailed    = "failed"
+	CustomGameOptimizeJobStatusCancelled = "cancelled"
+)
+
+var (
+	ErrCustomGameOptimizeJobAlreadyRunning = errors.New("optimization is already running")
+	ErrCustomGameOptimizeJobNotFound       = errors.New("optimization job not found")
+)
+
+type CustomGameOptimizeOptions struct {
+	ResultCount  int
+	IncludeBench bool // select who plays among all candidates (not only arranged participants)
+	// split candidates into 2 lobbies, 2nd lobby is arranged in this configuration
+	LobbyConfigId *string
+	ActorUid      string // user who started optimization (recorded on revision)
+	// version of configuration optimized, result is not applied if configuration is changed while optimizing
+	Version *int
+	// version of lobby configuration when optimization started (same as Version)
+	LobbyVersion *int
+	// fairness model of configuration (see GetCustomGameFairnessModel)
+	FairnessModel string
+}
+
+type CustomGameOptimizeJobVO struct {
+	Id                 string                               `json:"id"`
+	ConfigId           string                               `json:"configId"`
+	LobbyConfigId      *string                              `json:"lobbyConfigId"`
+	Status             string                               `json:"status"`
+	StartedAt          time.Time                            `json:"startedAt"`
+	EndedAt            *time.Time                           `json:"endedAt"`
+	Error              *string                              `json:"error"`
+	Configurations     []CustomGameOptimizedConfigurationVO `json:"configurations"`
+	LobbyConfiguration *CustomGameOptimizedConfigurationVO  `json:"lobbyConfiguration"`
+	// constraints which can't be satisfied together (when failed for constraints)
+	ConflictingConstraints []CustomGameConstraintVO `json:"conflictingConstraints"`
+}
+
+type customGameOptimizeJob struct {
+	CustomGameOptimizeJobVO
+	options CustomGameOptimizeOptions
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// CustomGameOptimizeJobReservation holds configurations for job until it is started or released,
+// so that changes requested with optimization are committed only if job can run
+type CustomGameOptimizeJobReservation struct {
+	job *customGameOptimizeJob
+}
+
+var (
+	// key: config id (running or last finished job of the configuration, lobby config also points the job)
+	customGameOptimizeJobs      = make(map[string]*customGameOptimizeJob)
+	customGameOptimizeJobsMutex sync.Mutex
+)
+
+// ReserveCustomGameOptimizeJob reserves optimization of configuration (and lobby configuration if given)
+func ReserveCustomGameOptimizeJob(configId string, lobbyConfigId *string) (*CustomGameOptimizeJobReservation, error) {
+	customGameOptimizeJobsMutex.Lock()
+	defer customGameOptimizeJobsMutex.Unlock()
+
+	configIds := []string{configId}
+	if lobbyConfigId != nil {
+		configIds = append(configIds, *lobbyConfigId)
+	}
+	for _, id := range configIds {
+		if job, exists := customGameOptimizeJobs[id]; exists && job.Status == CustomGameOptimizeJobStatusRunning {
+			return nil, ErrCustomGameOptimizeJobAlreadyRunning
+		}
+	}
+
+	// job is cancelled on server shutdown too
+	ctx, cancel := context.WithCancel(ServerContext)
+	job := &customGameOptimizeJob{
+		CustomGameOptimizeJobVO: CustomGameOptimizeJobVO{
+			Id:            uuid.NewString(),
+			ConfigId:      configId,
+			LobbyConfigId: lobbyConfigId,
+			Status:        CustomGameOptimizeJobStatusRunning,
+			StartedAt:     time.Now(),
+		},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	for _, id := range configIds {
+		customGameOptimizeJobs[id] = job
+	}
+	return &CustomGameOptimizeJobReservation{job: job}, nil
+}
+
+// Start starts reserved optimization in background, options should have version of committed configuration
+func (r *CustomGameOptimizeJobReservation) Start(weights CustomGameConfigurationWeightsVO, options CustomGameOptimizeOptions) *CustomGameOptimizeJobVO {
+	customGameOptimizeJobsMutex.Lock()
+	defer customGameOptimizeJobsMutex.Unlock()
+
+	job := r.job
+	options.LobbyConfigId = job.LobbyConfigId
+	job.options = options
+	// shutdown waits for result being applied
+	done := trackBackgroundJob()
+	go func() {
+		defer done()
+		job.run(job.ctx, weights)
+	}()
+
+	vo := job.CustomGameOptimizeJobVO
+	return &vo
+}
+
+// Release gives up reserved optimization which is not started
+func (r *CustomGameOptimizeJobReservation) Release() {
+	r.job.cancel()
+	r.job.evict()
+}
+
+// CancelCustomGameOptimizeJob cancels running job, result of cancelled job is not applied
+func CancelCustomGameOptimizeJob(configId, jobId string) error {
+	customGameOptimizeJobsMutex.Lock()
+	defer customGameOptimizeJobsMutex.Unlock()
+
+	job, exists := customGameOptimizeJobs[configId]
+	if !exists || job.Id != jobId || job.Status != CustomGameOptimizeJobStatusRunning {
+		return ErrCustomGameOptimizeJobNotFound
+	}
+	job.cancel()
+	return nil
+}
+
+// GetCustomGameOptimizeJobVO returns running or last finished job of configuration
+func GetCustomGameOptimizeJobVO(configId string) (*CustomGameOptimizeJobVO, bool) {
+	customGameOptimizeJobsMutex.Lock()
+	defer customGameOptimizeJobsMutex.Unlock()
+
+	job, exists := customGameOptimizeJobs[configId]
+	if !exists {
+		return nil, false
+	}
+	vo := job.CustomGameOptimizeJobVO
+	return &vo, true
+}
+
+func (j *customGameOptimizeJob) run(ctx context.Context, weights CustomGameConfigurationWeightsVO) {
+	defer j.cancel()
+
+	configs, lobbyConfig, err := j.optimize(ctx, weights)
+	if err == nil {
+		// cancelled right after search
+		err = ctx.Err()
+	}
+	cancelled := errors.Is(err, context.Canceled)
+	var versions map[string]int
+	if err == nil {
+		versions, err = j.apply(ctx, configs[0], lobbyConfig)
+	}
+
+	customGameOptimizeJobsMutex.Lock()
+	now := time.Now()
+	j.EndedAt = &now
+	if err != nil {
+		if cancelled {
+			j.Status = CustomGameOptimizeJobStatusCancelled
+		} else {
+			log.Error(err)
+			errMsg := err.Error()
+			j.Status = CustomGameOptimizeJobStatusFailed
+			j.Error = &errMsg
+			var conflictErr *CustomGameConstraintConflictError
+			if errors.As(err, &conflictErr) {
+				j.ConflictingConstraints = conflictErr.Constraints
+			}
+		}
+	} else {
+		j.Status = CustomGameOptimizeJobStatusSucceeded
+		j.Configurations = configs
+		j.LobbyConfiguration = lobbyConfig
+	}
+	doneData := socket.CustomConfigOptimizeDoneData{
+		JobId:  j.Id,
+		Status: j.Status,
+		Error:  j.Error,
+	}
+	for _, constraint := range j.ConflictingConstraints {
+		doneData.ConflictingConstraintIds = append(doneData.ConflictingConstraintIds, constraint.Id)
+	}
+	customGameOptimizeJobsMutex.Unlock()
+	time.AfterFunc(types.CustomGameOptimizeJobRetention, j.evict)
+
+	socket.SocketIO.BroadcastToCustomConfigRoom(j.ConfigId, socket.EventCustomConfigOptimizeDone, doneData)
+	if err == nil {
+		for configId, version := range versions {
+			socket.SocketIO.BroadcastToCustomConfigRoom(configId, socket.EventCustomConfigUpdated, socket.CustomConfigUpdatedData{
+				Version: version,
+			})
+		}
+	}
+}
+
+// evict removes finished job, unless another job of configuration started since
+func (j *customGameOptimizeJob) evict() {
+	customGameOptimizeJobsMutex.Lock()
+	defer customGameOptimizeJobsMutex.Unlock()
+
+	for configId, job := range customGameOptimizeJobs {
+		if job == j {
+			delete(customGameOptimizeJobs, configId)
+		}
+	}
+}
+
+func (j *customGameOptimizeJob) optimize(ctx context.Context, weights CustomGameConfigurationWeightsVO) (
+	[]CustomGameOptimizedConfigurationVO, *CustomGameOptimizedConfigurationVO, error) {
+	model := GetCustomGameFairnessModel(j.options.FairnessModel)
+	colorLabelDAOs, err := models.GetCustomGameParticipantColorLabelDAOs_byCustomGameConfigId(db.Root, j.ConfigId)
+	if err != nil {
+		return nil, nil, err
+	}
+	colorMap := make(map[string]int)
+	for _, colorLabelDAO := range colorLabelDAOs {
+		if colorLabelDAO.ColorCode == 0 {
+			continue
+		}
+		colorMap[colorLabelDAO.Puuid] = colorLabelDAO.ColorCode
+	}
+	constraintVOs, err := GetCustomGameConstraintVOs(db.Root, j.ConfigId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	onProgress := func(current, total int64) {
+		socket.SocketIO.BroadcastToCustomConfigRoom(
+			j.ConfigId,
+			socket.EventCustomConfigOptimizeProcess,
+			socket.CustomConfigOptimizeProcessData{
+				JobId:    j.Id,
+				Type:     socket.TypeCustomConfigOptimizeProcessCalculating,
+				Progress: float64(current) / float64(total),
+				Current:  current,
+				Total:    total,
+			},
+		)
+	}
+
+	if !j.options.IncludeBench && j.options.LobbyConfigId == nil {
+		participantVOsMap, err := GetCurrentCustomGameTeamParticipantVOMap(db.Root, j.ConfigId)
+		if err != nil {
+			return nil, nil, err
+		}
+		configs, err := FindBalancedCustomGameConfigs(ctx, participantVOsMap, weights, model, colorMap, constraintVOs, j.options.ResultCount, onProgress)
+		return configs, nil, err
+	}
+
+	candidateVOsMap, err := GetCustomGameCandidatePoolVOMap(db.Root, j.ConfigId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if j.options.LobbyConfigId == nil {
+		configs, err := FindBalancedCustomGameConfigsWithBench(ctx, candidateVOsMap, weights, model, colorMap, constraintVOs, j.options.ResultCount, onProgress)
+		return configs, nil, err
+	}
+
+	lobbies, err := FindBalancedCustomGameLobbies(ctx, candidateVOsMap, weights, model, colorMap, constraintVOs, types.CustomGameOptimizeLobbySplitCount, onProgress)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []CustomGameOptimizedConfigurationVO{lobbies[0]}, &lobbies[1], nil
+}
+
+// apply commits optimized arrangement (and 2nd lobby) in a short transaction, and returns new versions by config id
+func (j *customGameOptimizeJob) apply(ctx context.Context, config CustomGameOptimizedConfigurationVO, lobbyConfig *CustomGameOptimizedConfigurationVO) (map[string]int, error) {
+	tx, err := db.Root.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]int)
+	version, err := IncreaseCustomGameVersion(tx, j.ConfigId, j.options.Version)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	versions[j.ConfigId] = version
+
+	before, err := GetCustomGameArrangementSnapshot(tx, j.ConfigId)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	// participants can be replaced only if players are selected among candidates
+	replace := j.options.IncludeBench || j.options.LobbyConfigId != nil
+	if err := applyCustomGameArrangement(tx, j.ConfigId, config, replace); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if err := RecalculateCustomGameBalance(tx, j.ConfigId); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if err := RecordCustomGameRevision(tx, j.ConfigId, &j.options.ActorUid, types.CustomGameOperationOptimize, *before); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if lobbyConfig != nil {
+		lobbyConfigId := *j.options.LobbyConfigId
+		lobbyVersion, err := IncreaseCustomGameVersion(tx, lobbyConfigId, j.options.LobbyVersion)
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+		versions[lobbyConfigId] = lobbyVersion
+
+		lobbyBefore, err := GetCustomGameArrangementSnapshot(tx, lobbyConfigId)
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+
+		// players of 2nd lobby become candidates of lobby configuration
+		for _, participant := range append(lobbyConfig.Team1, lobbyConfig.Team2...) {
+			candidateDAO, exists, err := models.GetCustomGameCandidateDAO_byPuuid(tx, j.ConfigId, participant.Puuid)
+			if err != nil {
+				_ = tx.Rollback()
+				return nil, err
+			}
+			if !exists {
+				_ = tx.Rollback()
+				return nil, fmt.Errorf("candidates have changed while optimizing")
+			}
+			candidateDAO.CustomGameConfigId = lobbyConfigId
+			if err := candidateDAO.Upsert(tx); err != nil {
+				_ = tx.Rollback()
+				return nil, err
+			}
+		}
+
+		if err := applyCustomGameArrangement(tx, lobbyConfigId, *lobbyConfig, true); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+		if err := RecalculateCustomGameBalance(tx, lobbyConfigId); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+		if err := RecordCustomGameRevision(tx, lobbyConfigId, &j.options.ActorUid, types.CustomGameOperationOptimize, *lobbyBefore); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	return versions, nil
+}
+
+// applyCustomGameArrangement rewrites participants of configuration.
+// if replace is false, arranged players should be the same as current participants.
+func applyCustomGameArrangement(tx db.Context, configId string, config CustomGameOptimizedConfigurationVO, replace bool) error {
+	teamPositions := make(map[string]CustomGameTeamPositionVO)
+	for _, participant := range config.Team1 {
+		teamPositions[participant.Puuid] = CustomGameTeamPositionVO{Team: 1, Position: participant.Position}
+	}
+	for _, participant := range config.Team2 {
+		teamPositions[participant.Puuid] = CustomGameTeamPositionVO{Team: 2, Position: participant.Position}
+	}
+
+	participantDAOs, err := models.GetCustomGameParticipantDAOs_byCustomGameConfigId(tx, configId)
+	if err != nil {
+		return err
+	}
+	if !replace {
+		if len(participantDAOs) != len(teamPositions) {
+			return fmt.Errorf("participants have changed while optimizing")
+		}
+		for _, participantDAO := range participantDAOs {
+			if _, exists := teamPositions[participantDAO.Puuid]; !exists {
+				return fmt.Errorf("participants have changed while optimizing")
+			}
+		}
+	} else {
+		for puuid := range teamPositions {
+			_, exists, err := models.GetCustomGameCandidateDAO_byPuuid(tx, configId, puuid)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return fmt.Errorf("candidates have changed while optimizing")
+			}
+		}
+	}
+
+	// delete all first (to avoid conflict of team & position)
+	if err := models.DeleteCustomGameParticipantDAOs_byId(tx, configId); err != nil {
+		return err
+	}
+	for puuid, teamPosition := range teamPositions {
+		participantDAO := models.CustomGameParticipantDAO{
+			CustomGameConfigId: configId,
+			Puuid:              puuid,
+			Team:               teamPosition.Team,
+			Position:           teamPosition.Position,
+		}
+		if err := participantDAO.Upsert(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,475 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	log "github.com/shyunku-libraries/go-logger"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"team.gg-server/types"
+	"time"
+)
+
+// custom game optimizer searches arrangements in 3 stages:
+// player selection (when there are more candidates than seats) -> team split -> position assignment of each team.
+// splits are visited in order of fairness upper bound (best first) by worker pool,
+// and branches whose upper bound can't beat current top-N are pruned.
+
+const (
+	customGameOptimizerReportInterval = 200 * time.Millisecond
+	// guards upper bound against floating point error of summation order
+	customGameOptimizerBoundEpsilon = 1e-9
+
+	customGameLobbySeatCount = 10
+)
+
+type customGameOptimizerSplit struct {
+	team1        []int // pool indices
+	team2        []int
+	tierFairness float64
+	upperBound   float64
+}
+
+type customGameOptimizerResult struct {
+	balance     CustomGameConfigurationBalanceVO
+	arrangement []CustomGameTeamPositionVO // by pool index (team 0: benched)
+}
+
+type customGameOptimizer struct {
+	pool        []CustomGameTeamParticipantVO
+	weights     CustomGameConfigurationWeightsVO
+	model       FairnessModel
+	resultCount int
+	onProgress  func(current, total int64)
+
+	puuids          []string
+	checker         *customGameConstraintChecker // by pool index
+	baseSlots       []customGameFairnessSlot
+	maxFavorWeights []float64
+
+	mutex     sync.Mutex
+	results   []customGameOptimizerResult // sorted by fairness desc
+	threshold uint64                      // (float64 bits) fairness to beat for entering top-N
+
+	processed      int64
+	total          int64
+	lastReportedAt time.Time
+}
+
+// FindBalancedCustomGameConfigs returns top-N balanced arrangements of participants (best first).
+// search stops with context error when ctx is done.
+func FindBalancedCustomGameConfigs(
+	ctx context.Context,
+	originalTeamParticipantMap map[string]CustomGameTeamParticipantVO,
+	weights CustomGameConfigurationWeightsVO,
+	model FairnessModel,
+	colorMap map[string]int,
+	constraints []CustomGameConstraintVO,
+	resultCount int,
+	onProgress func(current, total int64),
+) ([]CustomGameOptimizedConfigurationVO, error) {
+	pool := sortedCustomGamePool(originalTeamParticipantMap)
+	if len(pool) == 0 {
+		return nil, fmt.Errorf("no participants")
+	}
+	if len(pool) > customGameLobbySeatCount {
+		return nil, fmt.Errorf("too many participants (%d)", len(pool))
+	}
+
+	selection := make([]int, len(pool))
+	for i := range pool {
+		selection[i] = i
+	}
+	return findBalancedCustomGameConfigs(ctx, pool, [][]int{selection}, weights, model, colorMap, constraints, resultCount, onProgress)
+}
+
+// FindBalancedCustomGameConfigsWithBench selects who plays among candidates (see selectCustomGamePlayers),
+// and returns top-N balanced arrangements of selected players (best first).
+func FindBalancedCustomGameConfigsWithBench(
+	ctx context.Context,
+	candidateMap map[string]CustomGameTeamParticipantVO,
+	weights CustomGameConfigurationWeightsVO,
+	model FairnessModel,
+	colorMap map[string]int,
+	constraints []CustomGameConstraintVO,
+	resultCount int,
+	onProgress func(current, total int64),
+) ([]CustomGameOptimizedConfigurationVO, error) {
+	pool := sortedCustomGamePool(candidateMap)
+	if len(pool) == 0 {
+		return nil, fmt.Errorf("no candidates")
+	}
+
+	selections, err := selectCustomGamePlayers(pool, customGameLobbySeatCount, types.CustomGameOptimizeMaxSelectionCount)
+	if err != nil {
+		return nil, err
+	}
+	return findBalancedCustomGameConfigs(ctx, pool, selections, weights, model, colorMap, constraints, resultCount, onProgress)
+}
+
+func sortedCustomGamePool(participantMap map[string]CustomGameTeamParticipantVO) []CustomGameTeamParticipantVO {
+	pool := make([]CustomGameTeamParticipantVO, 0)
+	for _, participant := range participantMap {
+		pool = append(pool, participant)
+	}
+	// keep search order stable between calls
+	sort.SliceStable(pool, func(i, j int) bool {
+		return pool[i].Summary.Puuid < pool[j].Summary.Puuid
+	})
+	return pool
+}
+
+// findBalancedCustomGameConfigs searches arrangements of every selection (pool indices of players) at once
+func findBalancedCustomGameConfigs(
+	ctx context.Context,
+	pool []CustomGameTeamParticipantVO,
+	selections [][]int,
+	weights CustomGameConfigurationWeightsVO,
+	model FairnessModel,
+	colorMap map[string]int,
+	constraints []CustomGameConstraintVO,
+	resultCount int,
+	onProgress func(current, total int64),
+) ([]CustomGameOptimizedConfigurationVO, error) {
+	if resultCount <= 0 {
+		resultCount = 1
+	}
+
+	o := &customGameOptimizer{
+		pool:            pool,
+		weights:         weights,
+		model:           model,
+		resultCount:     resultCount,
+		onProgress:      onProgress,
+		puuids:          make([]string, len(pool)),
+		baseSlots:       make([]customGameFairnessSlot, len(pool)),
+		maxFavorWeights: make([]float64, len(pool)),
+		results:         make([]customGameOptimizerResult, 0, resultCount+1),
+		threshold:       math.Float64bits(math.Inf(-1)),
+	}
+	for i, participant := range pool {
+		o.puuids[i] = participant.Summary.Puuid
+	}
+	o.checker = newCustomGameConstraintChecker(o.puuids, constraints)
+	pools := make([]CustomGameChampionPoolVO, len(pool))
+	for i, participant := range pool {
+		o.baseSlots[i] = newCustomGameFairnessSlot(participant, weights)
+		pools[i] = participant.ChampionPool
+		for p, position := range GetSupportedPositions {
+			if !o.checker.isPositionAllowed(i, p) {
+				continue
+			}
+			o.maxFavorWeights[i] = math.Max(o.maxFavorWeights[i], o.model.FavorWeight(getPositionFavor(participant.PositionFavor, position)))
+		}
+	}
+
+	// pool penalties of every participant & position pair are looked up while searching
+	setCustomGameChampionPoolTable(o.baseSlots, pools)
+
+	splits := make([]customGameOptimizerSplit, 0)
+	for _, selection := range selections {
+		splits = append(splits, o.getSplits(selection, colorMap)...)
+	}
+	if len(splits) == 0 {
+		return nil, o.conflictError(selections[0], colorMap, constraints)
+	}
+	o.total = int64(len(splits))
+
+	// best first, so that threshold rises early and prunes more
+	sort.SliceStable(splits, func(i, j int) bool {
+		return splits[i].upperBound > splits[j].upperBound
+	})
+
+	splitChan := make(chan customGameOptimizerSplit)
+	var workers sync.WaitGroup
+	workerCount := runtime.NumCPU()
+	for i := 0; i < workerCount; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for split := range splitChan {
+				if ctx.Err() != nil {
+					// drain remaining splits
+					continue
+				}
+				o.searchSplit(ctx, split)
+				o.report(atomic.AddInt64(&o.processed, 1))
+			}
+		}()
+	}
+feed:
+	for _, split := range splits {
+		select {
+		case <-ctx.Done():
+			break feed
+		case splitChan <- split:
+		}
+	}
+	close(splitChan)
+	workers.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(o.results) == 0 {
+		return nil, o.conflictError(selections[0], colorMap, constraints)
+	}
+	log.Debugf("highest fairness: %.5f (%d selections, %d splits, %d workers)", o.results[0].balance.Fairness, len(selections), o.total, workerCount)
+
+	configs := make([]CustomGameOptimizedConfigurationVO, 0, len(o.results))
+	for _, result := range o.results {
+		configs = append(configs, o.toConfigurationVO(result))
+	}
+	return configs, nil
+}
+
+// getSplits enumerates team 1 member sets of selected players satisfying color labels & team constraints.
+// first selected player is always on team 1 (unless teams are locked), since mirrored split has the same fairness.
+func (o *customGameOptimizer) getSplits(selection []int, colorMap map[string]int) []customGameOptimizerSplit {
+	count := len(selection)
+	splits := make([]customGameOptimizerSplit, 0)
+	start, step := 1, 2
+	if o.checker.hasLockedTeam {
+		start, step = 0, 1
+	}
+	teamOf := make([]int, len(o.pool))
+	for mask := start; mask < 1<<count; mask += step {
+		team1 := make([]int, 0, 5)
+		team2 := make([]int, 0, 5)
+		for k, i := range selection {
+			if mask&(1<<k) != 0 {
+				team1 = append(team1, i)
+				teamOf[i] = 1
+			} else {
+				team2 = append(team2, i)
+				teamOf[i] = 2
+			}
+		}
+		if len(team1) > 5 || len(team2) > 5 {
+			continue
+		}
+
+		// participants with same color label should be on the same team
+		if !satisfiesCustomGameColorLabels(o.puuids, teamOf, colorMap) || !o.checker.satisfiesTeams(teamOf) {
+			continue
+		}
+
+		var team1TierScore, team2TierScore float64
+		for _, i := range team1 {
+			team1TierScore += o.baseSlots[i].RatingPoint
+		}
+		for _, i := range team2 {
+			team2TierScore += o.baseSlots[i].RatingPoint
+		}
+		tierFairness := o.model.TierFairness(team1TierScore, team2TierScore, len(team1), len(team2))
+
+		splits = append(splits, customGameOptimizerSplit{
+			team1:        team1,
+			team2:        team2,
+			tierFairness: tierFairness,
+			upperBound:   o.upperBound(tierFairness, o.sumMaxFavorWeights(team1)+o.sumMaxFavorWeights(team2)),
+		})
+	}
+	return splits
+}
+
+func (o *customGameOptimizer) sumMaxFavorWeights(members []int) float64 {
+	var sum float64 = 0
+	for _, i := range members {
+		sum += o.maxFavorWeights[i]
+	}
+	return sum
+}
+
+// upperBound assumes perfect line fairness, with given tier fairness and maximum reachable line satisfaction
+func (o *customGameOptimizer) upperBound(tierFairness float64, maxLineSatisfaction float64) float64 {
+	return o.weights.LineFairness +
+		tierFairness*o.weights.TierFairness +
+		o.model.LineSatisfaction(maxLineSatisfaction)*o.weights.LineSatisfaction +
+		customGameOptimizerBoundEpsilon
+}
+
+func (o *customGameOptimizer) getThreshold() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&o.threshold))
+}
+
+// searchSplit assigns positions of team 1, then team 2
+func (o *customGameOptimizer) searchSplit(ctx context.Context, split customGameOptimizerSplit) {
+	if split.upperBound <= o.getThreshold() {
+		return
+	}
+
+	// team 1 members first, then team 2 members
+	members := make([]int, 0, len(split.team1)+len(split.team2))
+	members = append(members, split.team1...)
+	members = append(members, split.team2...)
+	slots := make([]customGameFairnessSlot, len(members))
+	for k, i := range members {
+		slots[k] = o.baseSlots[i]
+	}
+	team1Slots := slots[:len(split.team1)]
+	team2Slots := slots[len(split.team1):]
+
+	team1Permutations := o.checker.getAllowedPermutations(split.team1)
+	team2Permutations := o.checker.getAllowedPermutations(split.team2)
+	team2MaxLineSatisfaction := o.sumMaxFavorWeights(split.team2)
+	for _, team1Positions := range team1Permutations {
+		if ctx.Err() != nil {
+			return
+		}
+
+		var team1LineSatisfaction float64 = 0
+		for k := range team1Slots {
+			team1Slots[k].Team = 1
+			team1Slots[k].Position = GetSupportedPositions[team1Positions[k]]
+			team1LineSatisfaction += o.model.FavorWeight(getPositionFavor(team1Slots[k].PositionFavor, team1Slots[k].Position))
+		}
+		if o.upperBound(split.tierFairness, team1LineSatisfaction+team2MaxLineSatisfaction) <= o.getThreshold() {
+			continue
+		}
+
+		for _, team2Positions := range team2Permutations {
+			for k := range team2Slots {
+				team2Slots[k].Team = 2
+				team2Slots[k].Position = GetSupportedPositions[team2Positions[k]]
+			}
+			balance := o.model.Evaluate(slots, o.weights)
+			if balance.Fairness > o.getThreshold() {
+				o.offer(balance, members, slots)
+			}
+		}
+	}
+}
+
+// conflictError reports conflicting constraints of selection (when no arrangement is found)
+func (o *customGameOptimizer) conflictError(selection []int, colorMap map[string]int, constraints []CustomGameConstraintVO) error {
+	puuids := make([]string, 0, len(selection))
+	for _, i := range selection {
+		puuids = append(puuids, o.puuids[i])
+	}
+	conflict := findCustomGameConstraintConflict(puuids, colorMap, constraints)
+	if conflict == nil {
+		return fmt.Errorf("failed to find balanced team participant combination")
+	}
+	return &CustomGameConstraintConflictError{Constraints: conflict}
+}
+
+func (o *customGameOptimizer) offer(balance CustomGameConfigurationBalanceVO, members []int, slots []customGameFairnessSlot) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if balance.Fairness <= o.getThreshold() {
+		return
+	}
+
+	arrangement := make([]CustomGameTeamPositionVO, len(o.pool))
+	for k, i := range members {
+		arrangement[i] = CustomGameTeamPositionVO{Team: slots[k].Team, Position: slots[k].Position}
+	}
+	index := sort.Search(len(o.results), func(i int) bool {
+		return o.results[i].balance.Fairness < balance.Fairness
+	})
+	o.results = append(o.results, customGameOptimizerResult{})
+	copy(o.results[index+1:], o.results[index:])
+	o.results[index] = customGameOptimizerResult{balance: balance, arrangement: arrangement}
+
+	if len(o.results) > o.resultCount {
+		o.results = o.results[:o.resultCount]
+	}
+	if len(o.results) == o.resultCount {
+		atomic.StoreUint64(&o.threshold, math.Float64bits(o.results[len(o.results)-1].balance.Fairness))
+	}
+}
+
+func (o *customGameOptimizer) report(processed int64) {
+	if o.onProgress == nil {
+		return
+	}
+
+	o.mutex.Lock()
+	now := time.Now()
+	if processed < o.total && now.Sub(o.lastReportedAt) < customGameOptimizerReportInterval {
+		o.mutex.Unlock()
+		return
+	}
+	o.lastReportedAt = now
+	o.mutex.Unlock()
+
+	o.onProgress(processed, o.total)
+}
+
+func (o *customGameOptimizer) toConfigurationVO(result customGameOptimizerResult) CustomGameOptimizedConfigurationVO {
+	config := CustomGameOptimizedConfigurationVO{
+		Balance: result.balance,
+		Team1:   make([]CustomGameParticipantVO, 0),
+		Team2:   make([]CustomGameParticipantVO, 0),
+		Bench:   make([]string, 0),
+	}
+	for i, teamPosition := range result.arrangement {
+		if teamPosition.Team == 0 {
+			config.Bench = append(config.Bench, o.pool[i].Summary.Puuid)
+		}
+	}
+	// ordered by position
+	for _, position := range GetSupportedPositions {
+		for i, teamPosition := range result.arrangement {
+			if teamPosition.Team == 0 || teamPosition.Position != position {
+				continue
+			}
+			participant := CustomGameParticipantVO{
+				Position: position,
+				Puuid:    o.pool[i].Summary.Puuid,
+			}
+			if teamPosition.Team == 1 {
+				config.Team1 = append(config.Team1, participant)
+			} else {
+				config.Team2 = append(config.Team2, participant)
+			}
+		}
+	}
+	return config
+}
+
+var (
+	positionPermutations     [][][]int
+	positionPermutationsOnce sync.Once
+)
+
+// getPositionPermutations returns every injective assignment of n team members to position indices
+func getPositionPermutations(n int) [][]int {
+	positionPermutationsOnce.Do(func() {
+		positionCount := len(GetSupportedPositions)
+		positionPermutations = make([][][]int, positionCount+1)
+		for count := 0; count <= positionCount; count++ {
+			permutations := make([][]int, 0)
+			used := make([]bool, positionCount)
+			current := make([]int, 0, count)
+			var permutate func()
+			permutate = func() {
+				if len(current) == count {
+					permutation := make([]int, count)
+					copy(permutation, current)
+					permutations = append(permutations, permutation)
+					return
+				}
+				for p := 0; p < positionCount; p++ {
+					if used[p] {
+						continue
+					}
+					used[p] = true
+					current = append(current, p)
+					permutate()
+					current = current[:len(current)-1]
+					used[p] = false
+				}
+			}
+			permutate()
+			positionPermutations[count] = permutations
+		}
+	})
+	return positionPermutations[n]
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"team.gg-server/types"
+	"testing"
+)
+
+// newTestCustomGamePool gives participants (puuid p0, p1, ...) of given rating points & position favors
+func newTestCustomGamePool(ratingPoints []int64, favors [][5]int) []CustomGameTeamParticipantVO {
+	pool := make([]CustomGameTeamParticipantVO, 0, len(ratingPoints))
+	for i, ratingPoint := range ratingPoints {
+		favor := favors[i%len(favors)]
+		pool = append(pool, CustomGameTeamParticipantVO{
+			CustomGameCandidateVO: CustomGameCandidateVO{
+				Summary:  SummonerSummaryVO{Puuid: fmt.Sprintf("p%d", i)},
+				SoloRank: &SummonerRankVO{RatingPoint: ratingPoint},
+				PositionFavor: CustomGameCandidatePositionFavorVO{
+					Top: favor[0], Jungle: favor[1], Mid: favor[2], Adc: favor[3], Support: favor[4],
+				},
+			},
+		})
+	}
+	return pool
+}
+
+// exhaustiveCustomGameFairnesses evaluates every split & position assignment of whole pool (reference of branch & bound),
+// and returns fairness of each arrangement (best first). mirrored splits are skipped unless teams are locked, as optimizer does.
+func exhaustiveCustomGameFairnesses(pool []CustomGameTeamParticipantVO, model FairnessModel, constraints []CustomGameConstraintVO) []float64 {
+	puuids := make([]string, len(pool))
+	baseSlots := make([]customGameFairnessSlot, len(pool))
+	pools := make([]CustomGameChampionPoolVO, len(pool))
+	for i, participant := range pool {
+		puuids[i] = participant.Summary.Puuid
+		baseSlots[i] = newCustomGameFairnessSlot(participant, customGameFairnessTestWeights)
+	}
+	setCustomGameChampionPoolTable(baseSlots, pools)
+	checker := newCustomGameConstraintChecker(puuids, constraints)
+
+	fairnesses := make([]float64, 0)
+	teamOf := make([]int, len(pool))
+	for mask := 0; mask < 1<<len(pool); mask++ {
+		if !checker.hasLockedTeam && mask&1 == 0 {
+			continue
+		}
+		members := make([]int, 0, len(pool))
+		team1Size := 0
+		for i := range pool {
+			if mask&(1<<i) != 0 {
+				teamOf[i] = 1
+				members = append(members, i)
+				team1Size++
+			}
+		}
+		for i := range pool {
+			if mask&(1<<i) == 0 {
+				teamOf[i] = 2
+				members = append(members, i)
+			}
+		}
+		if team1Size > 5 || len(pool)-team1Size > 5 || !checker.satisfiesTeams(teamOf) {
+			continue
+		}
+
+		slots := make([]customGameFairnessSlot, len(members))
+		for k, i := range members {
+			slots[k] = baseSlots[i]
+			slots[k].Team = teamOf[i]
+		}
+	nextAssignment:
+		for _, team1Positions := range getPositionPermutations(team1Size) {
+			for _, team2Positions := range getPositionPermutations(len(pool) - team1Size) {
+				positions := append(append([]int{}, team1Positions...), team2Positions...)
+				for k, i := range members {
+					if !checker.isPositionAllowed(i, positions[k]) {
+						continue nextAssignment
+					}
+					slots[k].Position = GetSupportedPositions[positions[k]]
+				}
+				fairnesses = append(fairnesses, model.Evaluate(slots, customGameFairnessTestWeights).Fairness)
+			}
+		}
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(fairnesses)))
+	return fairnesses
+}
+
+func TestFindBalancedCustomGameConfigs(t *testing.T) {
+	team1 := 1
+	tests := []struct {
+		name         string
+		ratingPoints []int64
+		favors       [][5]int
+		constraints  []CustomGameConstraintVO
+	}{
+		{
+			name:         "6 players",
+			ratingPoints: []int64{2100, 1900, 1700, 1600, 1400, 1200},
+			favors:       [][5]int{{2, 1, 0, -1, 0}, {0, 2, 1, 0, -1}, {-1, 0, 2, 1, 0}, {0, -1, 0, 2, 1}, {1, 0, -1, 0, 2}},
+		},
+		{
+			name:         "7 players with same favors",
+			ratingPoints: []int64{2400, 2000, 1800, 1800, 1500, 1300, 1000},
+			favors:       [][5]int{{1, 1, 1, 1, 1}},
+		},
+		{
+			name:         "locked team",
+			ratingPoints: []int64{2100, 1900, 1700, 1600, 1400, 1200},
+			favors:       [][5]int{{2, 1, 0, -1, 0}, {0, 2, 1, 0, -1}, {-1, 0, 2, 1, 0}},
+			constraints: []CustomGameConstraintVO{
+				{Id: "c1", Type: types.CustomGameConstraintLockedTeam, Puuid: "p1", Team: &team1},
+				{Id: "c2", Type: types.CustomGameConstraintLockedTeam, Puuid: "p2", Team: &team1},
+			},
+		},
+	}
+	models := []string{types.CustomGameFairnessModelDefault, types.CustomGameFairnessModelWinProbability}
+	const resultCount = 5
+
+	for _, test := range tests {
+		pool := newTestCustomGamePool(test.ratingPoints, test.favors)
+		selection := make([]int, len(pool))
+		for i := range pool {
+			selection[i] = i
+		}
+		for _, modelName := range models {
+			model := GetCustomGameFairnessModel(modelName)
+			expected := exhaustiveCustomGameFairnesses(pool, model, test.constraints)[:resultCount]
+
+			configs, err := findBalancedCustomGameConfigs(context.Background(), pool, [][]int{selection},
+				customGameFairnessTestWeights, model, nil, test.constraints, resultCount, nil)
+			if err != nil {
+				t.Fatalf("%s (%s): %v", test.name, modelName, err)
+			}
+			if len(configs) != resultCount {
+				t.Fatalf("%s (%s): expected %d results, got %d", test.name, modelName, resultCount, len(configs))
+			}
+			// pruned search keeps the same top-N as exhaustive search
+			for k, config := range configs {
+				if math.Abs(config.Balance.Fairness-expected[k]) > customGameFairnessTestTolerance {
+					t.Errorf("%s (%s): expected fairness %.15g at rank %d, got %.15g", test.name, modelName, expected[k], k+1, config.Balance.Fairness)
+				}
+			}
+			for _, constraint := range test.constraints {
+				for _, participant := range configs[0].Team2 {
+					if participant.Puuid == constraint.Puuid {
+						t.Errorf("%s (%s): %s is locked on team 1, but placed on team 2", test.name, modelName, constraint.Puuid)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCustomGameOptimizerSplits(t *testing.T) {
+	team2 := 2
+	pool := newTestCustomGamePool([]int64{2000, 1800, 1600, 1400}, [][5]int{{1, 1, 1, 1, 1}})
+	selection := []int{0, 1, 2, 3}
+	tests := []struct {
+		name        string
+		constraints []CustomGameConstraintVO
+		expected    int
+	}{
+		// 2^4 team 1 sets, mirrored ones skipped
+		{"mirrored splits skipped", nil, 8},
+		{"locked team keeps both sides", []CustomGameConstraintVO{
+			{Id: "c1", Type: types.CustomGameConstraintLockedTeam, Puuid: "p3", Team: &team2},
+		}, 8},
+		{"apart", []CustomGameConstraintVO{
+			{Id: "c1", Type: types.CustomGameConstraintApart, Puuid: "p0", TargetPuuid: &pool[1].Summary.Puuid},
+		}, 4},
+	}
+
+	for _, test := range tests {
+		o := &customGameOptimizer{
+			pool:            pool,
+			weights:         customGameFairnessTestWeights,
+			model:           GetCustomGameFairnessModel(types.CustomGameFairnessModelDefault),
+			puuids:          []string{"p0", "p1", "p2", "p3"},
+			baseSlots:       make([]customGameFairnessSlot, len(pool)),
+			maxFavorWeights: make([]float64, len(pool)),
+		}
+		o.checker = newCustomGameConstraintChecker(o.puuids, test.constraints)
+		for i, participant := range pool {
+			o.baseSlots[i] = newCustomGameFairnessSlot(participant, o.weights)
+		}
+
+		splits := o.getSplits(selection, nil)
+		if len(splits) != test.expected {
+			t.Errorf("%s: expected %d splits, got %d", test.name, test.expected, len(splits))
+		}
+		for _, split := range splits {
+			// upper bound never underestimates fairness of split (perfect line fairness & satisfaction)
+			if split.upperBound < split.tierFairness*o.weights.TierFairness {
+				t.Errorf("%s: upper bound %.15g is below tier fairness", test.name, split.upperBound)
+			}
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"team.gg-server/types"
+)
+
+// when there are more candidates than seats, who plays is decided by bench fairness rule:
+// must-play candidates always play, then who has been benched more consecutive rounds, then who played less.
+// seats left for candidates of the same priority are decided by optimizer (among sampled selections).
+
+const customGameMaxPoolSize = 64 // selections are keyed by bit mask
+
+func compareCustomGamePlayerPriority(a, b CustomGameCandidateVO) int {
+	if a.MustPlay != b.MustPlay {
+		if a.MustPlay {
+			return -1
+		}
+		return 1
+	}
+	if a.BenchStreak != b.BenchStreak {
+		return b.BenchStreak - a.BenchStreak
+	}
+	return a.PlayedCount - b.PlayedCount
+}
+
+// selectCustomGamePlayers returns selections (pool indices) of players to fill seats
+func selectCustomGamePlayers(pool []CustomGameTeamParticipantVO, seatCount int, maxSelectionCount int) ([][]int, error) {
+	if len(pool) > customGameMaxPoolSize {
+		return nil, fmt.Errorf("too many candidates (%d)", len(pool))
+	}
+
+	mustPlayCount := 0
+	for _, candidate := range pool {
+		if candidate.MustPlay {
+			mustPlayCount++
+		}
+	}
+	if mustPlayCount > seatCount {
+		return nil, fmt.Errorf("too many must-play candidates (%d)", mustPlayCount)
+	}
+
+	indices := make([]int, len(pool))
+	for i := range pool {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return compareCustomGamePlayerPriority(pool[indices[i]].CustomGameCandidateVO, pool[indices[j]].CustomGameCandidateVO) < 0
+	})
+
+	// take priority groups while they fit entirely, first group which doesn't fit shares the remaining seats
+	fixed := make([]int, 0, seatCount)
+	tied := make([]int, 0)
+	for start := 0; start < len(indices); {
+		end := start
+		for end < len(indices) && compareCustomGamePlayerPriority(pool[indices[start]].CustomGameCandidateVO, pool[indices[end]].CustomGameCandidateVO) == 0 {
+			end++
+		}
+		group := indices[start:end]
+		if len(fixed)+len(group) > seatCount {
+			tied = group
+			break
+		}
+		fixed = append(fixed, group...)
+		start = end
+	}
+
+	remaining := seatCount - len(fixed)
+	if remaining == 0 || len(tied) == 0 {
+		return [][]int{fixed}, nil
+	}
+
+	selections := make([][]int, 0)
+	for _, combination := range getCombinations(len(tied), remaining, maxSelectionCount) {
+		selection := make([]int, 0, seatCount)
+		selection = append(selection, fixed...)
+		for _, k := range combination {
+			selection = append(selection, tied[k])
+		}
+		selections = append(selections, selection)
+	}
+	return selections, nil
+}
+
+// getCombinations returns every k-combination of n, or random distinct samples of them if there are more than limit
+func getCombinations(n, k, limit int) [][]int {
+	combinations := make([][]int, 0)
+	if countCombinationsUpTo(n, k, limit) <= limit {
+		current := make([]int, 0, k)
+		var combinate func(start int)
+		combinate = func(start int) {
+			if len(current) == k {
+				combination := make([]int, k)
+				copy(combination, current)
+				combinations = append(combinations, combination)
+				return
+			}
+			for i := start; i <= n-(k-len(current)); i++ {
+				current = append(current, i)
+				combinate(i + 1)
+				current = current[:len(current)-1]
+			}
+		}
+		combinate(0)
+		return combinations
+	}
+
+	seen := make(map[uint64]bool)
+	for attempt := 0; len(combinations) < limit && attempt < limit*10; attempt++ {
+		combination := rand.Perm(n)[:k]
+		sort.Ints(combination)
+		var key uint64 = 0
+		for _, i := range combination {
+			key |= 1 << i
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		combinations = append(combinations, combination)
+	}
+	return combinations
+}
+
+// countCombinationsUpTo returns nCk, or limit+1 if it exceeds limit
+func countCombinationsUpTo(n, k, limit int) int {
+	count := 1
+	for i := 0; i < k; i++ {
+		count = count * (n - i) / (i + 1)
+		if count > limit {
+			return limit + 1
+		}
+	}
+	return count
+}
+
+// FindBalancedCustomGameLobbies selects players for 2 lobbies, and returns balanced arrangement of each lobby.
+// lobby splits are sampled (keeping color label groups & together constraints in the same lobby),
+// and the one with the best worse-lobby fairness is chosen.
+func FindBalancedCustomGameLobbies(
+	ctx context.Context,
+	candidateMap map[string]CustomGameTeamParticipantVO,
+	weights CustomGameConfigurationWeightsVO,
+	model FairnessModel,
+	colorMap map[string]int,
+	constraints []CustomGameConstraintVO,
+	lobbySplitCount int,
+	onProgress func(current, total int64),
+) ([2]CustomGameOptimizedConfigurationVO, error) {
+	var best [2]CustomGameOptimizedConfigurationVO
+
+	pool := sortedCustomGamePool(candidateMap)
+	selections, err := selectCustomGamePlayers(pool, 2*customGameLobbySeatCount, 1)
+	if err != nil {
+		return best, err
+	}
+	players := selections[0]
+	if len(players) < 2*customGameLobbySeatCount {
+		return best, fmt.Errorf("not enough candidates for 2 lobbies (%d)", len(players))
+	}
+
+	playing := make(map[int]bool)
+	for _, i := range players {
+		playing[i] = true
+	}
+	bench := make([]string, 0)
+	for i, candidate := range pool {
+		if !playing[i] {
+			bench = append(bench, candidate.Summary.Puuid)
+		}
+	}
+
+	lobbySplits := getCustomGameLobbySplits(pool, players, colorMap, constraints, lobbySplitCount)
+	if len(lobbySplits) == 0 {
+		return best, fmt.Errorf("no lobby split satisfies color labels")
+	}
+
+	total := int64(2 * len(lobbySplits))
+	var current int64 = 0
+	bestScore := math.Inf(-1)
+	var lastErr error
+nextSplit:
+	for _, lobbySplit := range lobbySplits {
+		var lobbies [2]CustomGameOptimizedConfigurationVO
+		for l, lobby := range lobbySplit {
+			configs, err := findBalancedCustomGameConfigs(ctx, pool, [][]int{lobby}, weights, model, colorMap, constraints, 1, nil)
+			current++
+			if onProgress != nil {
+				onProgress(current, total)
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return best, ctx.Err()
+				}
+				lastErr = err
+				continue nextSplit
+			}
+			lobbies[l] = configs[0]
+			lobbies[l].Bench = bench
+		}
+
+		score := math.Min(lobbies[0].Balance.Fairness, lobbies[1].Balance.Fairness)
+		if score > bestScore {
+			bestScore = score
+			best = lobbies
+		}
+	}
+
+	if math.IsInf(bestScore, -1) {
+		if lastErr != nil {
+			return best, lastErr
+		}
+		return best, fmt.Errorf("failed to find balanced lobbies")
+	}
+	return best, nil
+}
+
+// getCustomGameLobbySplits returns distinct splits of players into 2 lobbies of equal size.
+// first one is greedy split by rating, others are greedy splits in random order.
+func getCustomGameLobbySplits(pool []CustomGameTeamParticipantVO, players []int, colorMap map[string]int, constraints []CustomGameConstraintVO, count int) [][2][]int {
+	lobbySize := len(players) / 2
+
+	// players with same color label (or constrained to be together) are kept in the same lobby
+	parents := make(map[int]int)
+	var find func(i int) int
+	find = func(i int) int {
+		if parents[i] == i {
+			return i
+		}
+		parents[i] = find(parents[i])
+		return parents[i]
+	}
+	indices := make(map[string]int)
+	colorPlayers := make(map[int]int)
+	for _, i := range players {
+		parents[i] = i
+		indices[pool[i].Summary.Puuid] = i
+		colorCode := colorMap[pool[i].Summary.Puuid]
+		if colorCode == 0 {
+			continue
+		}
+		if j, exists := colorPlayers[colorCode]; exists {
+			parents[find(i)] = find(j)
+		} else {
+			colorPlayers[colorCode] = i
+		}
+	}
+	for _, constraint := range constraints {
+		if constraint.Type != types.CustomGameConstraintTogether {
+			continue
+		}
+		i, exists := indices[constraint.Puuid]
+		j, targetExists := indices[*constraint.TargetPuuid]
+		if exists && targetExists {
+			parents[find(i)] = find(j)
+		}
+	}
+	units := make([][]int, 0)
+	rootUnits := make(map[int]int)
+	for _, i := range players {
+		root := find(i)
+		if u, exists := rootUnits[root]; exists {
+			units[u] = append(units[u], i)
+			continue
+		}
+		rootUnits[root] = len(units)
+		units = append(units, []int{i})
+	}
+	unitRatingPoint := func(unit []int) float64 {
+		var sum float64 = 0
+		for _, i := range unit {
+			sum += float64(pool[i].GetRepresentativeRatingPoint())
+		}
+		return sum
+	}
+
+	lobbySplits := make([][2][]int, 0)
+	seen := make(map[uint64]bool)
+	for attempt := 0; len(lobbySplits) < count && attempt < count*10; attempt++ {
+		order := make([][]int, len(units))
+		copy(order, units)
+		if attempt == 0 {
+			sort.SliceStable(order, func(i, j int) bool {
+				return unitRatingPoint(order[i]) > unitRatingPoint(order[j])
+			})
+		} else {
+			rand.Shuffle(len(order), func(i, j int) {
+				order[i], order[j] = order[j], order[i]
+			})
+		}
+
+		// put each unit into lobby with lower rating sum (if it has room)
+		var lobbies [2][]int
+		var ratingPoints [2]float64
+		fits := true
+		for _, unit := range order {
+			target := 0
+			if ratingPoints[1] < ratingPoints[0] {
+				target = 1
+			}
+			if len(lobbies[target])+len(unit) > lobbySize {
+				target = 1 - target
+			}
+			if len(lobbies[target])+len(unit) > lobbySize {
+				fits = false
+				break
+			}
+			lobbies[target] = append(lobbies[target], unit...)
+			ratingPoints[target] += unitRatingPoint(unit)
+		}
+		if !fits || len(lobbies[0]) != lobbySize {
+			continue
+		}
+
+		// same split with swapped lobbies is the same
+		var key uint64 = 0
+		for _, i := range lobbies[0] {
+			key |= 1 << i
+		}
+		if key&(1<<players[0]) == 0 {
+			key = 0
+			for _, i := range lobbies[1] {
+				key |= 1 << i
+			}
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		lobbySplits = append(lobbySplits, lobbies)
+	}
+	return lobbySplits
+}
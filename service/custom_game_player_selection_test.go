@@ -0,0 +1,151 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+type testCustomGameCandidate struct {
+	mustPlay    bool
+	benchStreak int
+	playedCount int
+}
+
+func newTestCustomGameSelectionPool(candidates []testCustomGameCandidate) []CustomGameTeamParticipantVO {
+	pool := make([]CustomGameTeamParticipantVO, 0, len(candidates))
+	for i, candidate := range candidates {
+		pool = append(pool, CustomGameTeamParticipantVO{
+			CustomGameCandidateVO: CustomGameCandidateVO{
+				Summary:     SummonerSummaryVO{Puuid: fmt.Sprintf("p%d", i)},
+				MustPlay:    candidate.mustPlay,
+				BenchStreak: candidate.benchStreak,
+				PlayedCount: candidate.playedCount,
+			},
+		})
+	}
+	return pool
+}
+
+func TestSelectCustomGamePlayers(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []testCustomGameCandidate
+		seatCount  int
+		// players in every selection
+		fixed []int
+		// players who share the remaining seats
+		tied       []int
+		selections int
+	}{
+		{
+			name:       "everyone plays",
+			candidates: []testCustomGameCandidate{{}, {}, {}, {}},
+			seatCount:  4,
+			fixed:      []int{0, 1, 2, 3},
+			selections: 1,
+		},
+		{
+			name: "must-play before bench streak",
+			candidates: []testCustomGameCandidate{
+				{benchStreak: 3}, {mustPlay: true, playedCount: 9}, {benchStreak: 1}, {mustPlay: true}, {},
+			},
+			seatCount:  3,
+			fixed:      []int{3, 1, 0},
+			selections: 1,
+		},
+		{
+			name: "bench streak before played count",
+			candidates: []testCustomGameCandidate{
+				{playedCount: 0}, {benchStreak: 2, playedCount: 5}, {benchStreak: 1, playedCount: 5}, {playedCount: 1},
+			},
+			seatCount:  3,
+			fixed:      []int{1, 2, 0},
+			selections: 1,
+		},
+		{
+			name: "lower played count first",
+			candidates: []testCustomGameCandidate{
+				{playedCount: 4}, {playedCount: 2}, {playedCount: 3}, {playedCount: 1},
+			},
+			seatCount:  2,
+			fixed:      []int{3, 1},
+			selections: 1,
+		},
+		{
+			name: "tied group shares remaining seats",
+			candidates: []testCustomGameCandidate{
+				{mustPlay: true}, {benchStreak: 1}, {}, {}, {}, {}, {playedCount: 1},
+			},
+			seatCount:  4,
+			fixed:      []int{0, 1},
+			tied:       []int{2, 3, 4, 5},
+			selections: 6, // 4C2
+		},
+		{
+			name: "selections are sampled over limit",
+			candidates: []testCustomGameCandidate{
+				{}, {}, {}, {}, {}, {}, {}, {}, {}, {},
+			},
+			seatCount:  5,
+			tied:       []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+			selections: 20, // of 10C5
+		},
+	}
+	const maxSelectionCount = 20
+
+	for _, test := range tests {
+		pool := newTestCustomGameSelectionPool(test.candidates)
+		selections, err := selectCustomGamePlayers(pool, test.seatCount, maxSelectionCount)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if len(selections) != test.selections {
+			t.Errorf("%s: expected %d selections, got %d", test.name, test.selections, len(selections))
+		}
+
+		tied := make(map[int]bool)
+		for _, i := range test.tied {
+			tied[i] = true
+		}
+		seen := make(map[string]bool)
+		for _, selection := range selections {
+			if len(selection) != test.seatCount {
+				t.Errorf("%s: expected %d players, got %v", test.name, test.seatCount, selection)
+				continue
+			}
+			fixed := make(map[int]bool)
+			for _, i := range selection[:len(test.fixed)] {
+				fixed[i] = true
+			}
+			for _, i := range test.fixed {
+				if !fixed[i] {
+					t.Errorf("%s: expected %v to play first, got %v", test.name, test.fixed, selection)
+					break
+				}
+			}
+			for _, i := range selection[len(test.fixed):] {
+				if !tied[i] {
+					t.Errorf("%s: %d is not tied for remaining seats, got %v", test.name, i, selection)
+				}
+			}
+
+			sorted := append([]int{}, selection...)
+			sort.Ints(sorted)
+			key := fmt.Sprint(sorted)
+			if seen[key] {
+				t.Errorf("%s: duplicated selection %v", test.name, selection)
+			}
+			seen[key] = true
+		}
+	}
+}
+
+func TestSelectCustomGamePlayersTooManyMustPlay(t *testing.T) {
+	pool := newTestCustomGameSelectionPool([]testCustomGameCandidate{
+		{mustPlay: true}, {mustPlay: true}, {mustPlay: true}, {},
+	})
+	if _, err := selectCustomGamePlayers(pool, 2, 1); err == nil {
+		t.Error("expected error for more must-play candidates than seats")
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/models/mixed"
+	"team.gg-server/types"
+)
+
+// position favor is inferred from recent summoner's rift matches of candidate:
+// share of each position decides base favor, which is raised (or lowered) by 1
+// if player wins more (or less) and scores higher (or lower) on that position than usual.
+
+// GetCustomGamePositionFavorSuggestionVO suggests position favor of summoner from recent matches
+func GetCustomGamePositionFavorSuggestionVO(puuid string) (*CustomGamePositionFavorSuggestionVO, error) {
+	matchDAOs, err := mixed.GetPositionedMatchParticipantExtraMXDAOs(puuid, types.CustomGamePositionFavorMatchCount)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		matchCount          = 0
+		winCount            = 0
+		ggScoreSum          = 0.0
+		positionWins        = make(map[string]int)
+		positionGGScoreSums = make(map[string]float64)
+		positionCounts      = make(map[string]int)
+	)
+	for _, matchDAO := range matchDAOs {
+		// remakes tell nothing about position
+		if matchDAO.GameEndedInEarlySurrender {
+			continue
+		}
+		position, ok := teamPositionToPosition(matchDAO.TeamPosition)
+		if !ok {
+			continue
+		}
+		ggScore := matchDAO.GetScore()
+		matchCount++
+		ggScoreSum += ggScore
+		positionCounts[position]++
+		positionGGScoreSums[position] += ggScore
+		if matchDAO.Win {
+			winCount++
+			positionWins[position]++
+		}
+	}
+
+	suggestionVO := CustomGamePositionFavorSuggestionVO{
+		Puuid:      puuid,
+		MatchCount: matchCount,
+		Suggested:  matchCount >= types.CustomGamePositionFavorMinMatchCount,
+		Positions:  make([]CustomGamePositionStatVO, 0, len(GetSupportedPositions)),
+	}
+	if matchCount == 0 {
+		return &suggestionVO, nil
+	}
+
+	winRate := float64(winCount) / float64(matchCount)
+	avgGGScore := ggScoreSum / float64(matchCount)
+	mainPosition := ""
+	for _, position := range GetSupportedPositions {
+		statVO := CustomGamePositionStatVO{
+			Position:   position,
+			MatchCount: positionCounts[position],
+		}
+		if statVO.MatchCount > 0 {
+			statVO.WinRate = float64(positionWins[position]) / float64(statVO.MatchCount)
+			statVO.AvgGGScore = positionGGScoreSums[position] / float64(statVO.MatchCount)
+		}
+		suggestionVO.Positions = append(suggestionVO.Positions, statVO)
+		if mainPosition == "" || statVO.MatchCount > positionCounts[mainPosition] {
+			mainPosition = position
+		}
+	}
+	if !suggestionVO.Suggested {
+		return &suggestionVO, nil
+	}
+
+	for _, statVO := range suggestionVO.Positions {
+		favor := getPositionFavorByShare(float64(statVO.MatchCount) / float64(matchCount))
+		if statVO.MatchCount >= types.CustomGamePositionFavorMinStatCount {
+			if statVO.WinRate >= winRate+0.1 && statVO.AvgGGScore >= avgGGScore {
+				favor++
+			} else if statVO.WinRate <= winRate-0.1 && statVO.AvgGGScore < avgGGScore {
+				favor--
+			}
+		}
+		// most played position is never unfavored
+		if statVO.Position == mainPosition && favor < 1 {
+			favor = 1
+		}
+		setPositionFavor(&suggestionVO.PositionFavor, statVO.Position, clampPositionFavor(favor))
+	}
+	return &suggestionVO, nil
+}
+
+// GetCustomGamePositionFavorSuggestionVOs suggests position favors of candidates in configuration
+func GetCustomGamePositionFavorSuggestionVOs(db db.Context, configId string) ([]CustomGamePositionFavorSuggestionVO, error) {
+	candidateDAOs, err := models.GetCustomGameCandidateDAOs_byCustomGameConfigId(db, configId)
+	if err != nil {
+		return nil, err
+	}
+	suggestionVOs := make([]CustomGamePositionFavorSuggestionVO, 0, len(candidateDAOs))
+	for _, candidateDAO := range candidateDAOs {
+		suggestionVO, err := GetCustomGamePositionFavorSuggestionVO(candidateDAO.Puuid)
+		if err != nil {
+			return nil, err
+		}
+		suggestionVOs = append(suggestionVOs, *suggestionVO)
+	}
+	return suggestionVOs, nil
+}
+
+// ApplyCustomGamePositionFavorSuggestion fills position favor of candidate having no preference yet with suggestion,
+// returns whether candidate is changed
+func ApplyCustomGamePositionFavorSuggestion(candidateDAO *models.CustomGameCandidateDAO) (bool, error) {
+	if candidateDAO.FlavorTop != 0 || candidateDAO.FlavorJungle != 0 || candidateDAO.FlavorMid != 0 ||
+		candidateDAO.FlavorAdc != 0 || candidateDAO.FlavorSupport != 0 {
+		return false, nil
+	}
+	suggestionVO, err := GetCustomGamePositionFavorSuggestionVO(candidateDAO.Puuid)
+	if err != nil {
+		return false, err
+	}
+	if !suggestionVO.Suggested {
+		return false, nil
+	}
+	candidateDAO.FlavorTop = suggestionVO.PositionFavor.Top
+	candidateDAO.FlavorJungle = suggestionVO.PositionFavor.Jungle
+	candidateDAO.FlavorMid = suggestionVO.PositionFavor.Mid
+	candidateDAO.FlavorAdc = suggestionVO.PositionFavor.Adc
+	candidateDAO.FlavorSupport = suggestionVO.PositionFavor.Support
+	return true, nil
+}
+
+func getPositionFavorByShare(share float64) int {
+	switch {
+	case share >= 0.4:
+		return 2
+	case share >= 0.2:
+		return 1
+	case share >= 0.05:
+		return 0
+	default:
+		return -1
+	}
+}
+
+func clampPositionFavor(favor int) int {
+	if favor < -1 {
+		return -1
+	}
+	if favor > 2 {
+		return 2
+	}
+	return favor
+}
+
+func setPositionFavor(positionFavor *CustomGameCandidatePositionFavorVO, position string, favor int) {
+	switch position {
+	case types.PositionTop:
+		positionFavor.Top = favor
+	case types.PositionJungle:
+		positionFavor.Jungle = favor
+	case types.PositionMid:
+		positionFavor.Mid = favor
+	case types.PositionAdc:
+		positionFavor.Adc = favor
+	case types.PositionSupport:
+		positionFavor.Support = favor
+	}
+}
+
+// teamPositionToPosition converts team position of riot match data to position of custom game
+func teamPositionToPosition(teamPosition string) (string, bool) {
+	switch teamPosition {
+	case types.TeamPositionTop:
+		return types.PositionTop, true
+	case types.TeamPositionJungle:
+		return types.PositionJungle, true
+	case types.TeamPositionMid:
+		return types.PositionMid, true
+	case types.TeamPositionAdc:
+		return types.PositionAdc, true
+	case types.TeamPositionSupport:
+		return types.PositionSupport, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"math"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/types"
+	"time"
+)
+
+// in-house rating is Glicko-2 rating maintained per group (owner of configurations) for each player, overall and per position.
+// it starts from representative rating point of player, so it can be blended with (or replace) rating point on balancing.
+// each game is a rating period, and each player is rated against opposing team seen from own team's strength:
+// opponent rating = own rating + (opposing team average - own team average).
+
+const customGameGlicko2Scale = 173.7178
+
+var (
+	ErrCustomGameResultIncompleteTeams  = errors.New("both teams should be full")
+	ErrCustomGameResultAlreadyRecorded  = errors.New("result of match is already recorded")
+	ErrCustomGameResultInvalidWinner    = errors.New("winner should be team 1 or 2")
+	ErrCustomGameConfigurationNotExists = errors.New("custom game configuration not found")
+)
+
+type customGameRating struct {
+	rating     float64
+	deviation  float64
+	volatility float64
+}
+
+// customGameRatingGame is a game against opponent in rating period (score: 1 win, 0 loss)
+type customGameRatingGame struct {
+	opponentRating    float64
+	opponentDeviation float64
+	score             float64
+}
+
+// RecordCustomGameResult snapshots current arrangement of configuration as played game, and updates in-house ratings
+func RecordCustomGameResult(tx db.Context, configId string, winner int, riotMatchId *string, playedAt time.Time) (*CustomGameResultVO, error) {
+	if winner != 1 && winner != 2 {
+		return nil, ErrCustomGameResultInvalidWinner
+	}
+
+	configDAO, exists, err := models.GetCustomGameDAO_byId(tx, configId)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCustomGameConfigurationNotExists
+	}
+	groupUid := configDAO.CreatorUid
+
+	if riotMatchId != nil {
+		_, exists, err := models.GetCustomGameResultDAO_byRiotMatchId(tx, groupUid, *riotMatchId)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrCustomGameResultAlreadyRecorded
+		}
+	}
+
+	participantVOsMap, err := GetCurrentCustomGameTeamParticipantVOMap(tx, configId)
+	if err != nil {
+		return nil, err
+	}
+	teamSizes := make(map[int]int)
+	for _, participant := range participantVOsMap {
+		teamSizes[participant.Team]++
+	}
+	if teamSizes[1] != len(GetSupportedPositions) || teamSizes[2] != len(GetSupportedPositions) {
+		return nil, ErrCustomGameResultIncompleteTeams
+	}
+
+	// results played before the last one need replaying whole history
+	lastResultDAO, hasLastResult, err := models.GetLastCustomGameResultDAO_byGroupUid(tx, groupUid)
+	if err != nil {
+		return nil, err
+	}
+	replay := hasLastResult && playedAt.Before(lastResultDAO.PlayedAt)
+
+	resultDAO := models.CustomGameResultDAO{
+		Id:                 uuid.NewString(),
+		CustomGameConfigId: &configId,
+		GroupUid:           groupUid,
+		Winner:             winner,
+		RiotMatchId:        riotMatchId,
+		PlayedAt:           playedAt,
+		CreatedAt:          time.Now(),
+	}
+	if err := resultDAO.Insert(tx); err != nil {
+		// recorded concurrently
+		if db.IsDuplicateEntryError(err) {
+			return nil, ErrCustomGameResultAlreadyRecorded
+		}
+		return nil, err
+	}
+
+	participantDAOs := make([]models.CustomGameResultParticipantDAO, 0, len(participantVOsMap))
+	for puuid, participant := range participantVOsMap {
+		participantDAOs = append(participantDAOs, models.CustomGameResultParticipantDAO{
+			ResultId:    resultDAO.Id,
+			Puuid:       puuid,
+			Team:        participant.Team,
+			Position:    participant.Position,
+			RatingPoint: float64(participant.GetRepresentativeRatingPoint()),
+		})
+	}
+
+	if replay {
+		for _, participantDAO := range participantDAOs {
+			if err := participantDAO.Upsert(tx); err != nil {
+				return nil, err
+			}
+		}
+		if err := RecalculateCustomGameRatings(tx, groupUid); err != nil {
+			return nil, err
+		}
+		if participantDAOs, err = models.GetCustomGameResultParticipantDAOs_byResultId(tx, resultDAO.Id); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := applyCustomGameResultRatings(tx, resultDAO, participantDAOs); err != nil {
+			return nil, err
+		}
+	}
+
+	resultVO := customGameResultVO(resultDAO, participantDAOs)
+	return &resultVO, nil
+}
+
+// DeleteCustomGameResult deletes played game, and replays in-house ratings of group without it
+func DeleteCustomGameResult(tx db.Context, resultDAO models.CustomGameResultDAO) error {
+	if err := resultDAO.Delete(tx); err != nil {
+		return err
+	}
+	return RecalculateCustomGameRatings(tx, resultDAO.GroupUid)
+}
+
+// RecalculateCustomGameRatings rebuilds in-house ratings of group by replaying every result in played order
+func RecalculateCustomGameRatings(tx db.Context, groupUid string) error {
+	if err := models.DeleteCustomGameRatingDAOs_byGroupUid(tx, groupUid); err != nil {
+		return err
+	}
+	resultDAOs, err := models.GetCustomGameResultDAOs_byGroupUid(tx, groupUid)
+	if err != nil {
+		return err
+	}
+	for _, resultDAO := range resultDAOs {
+		participantDAOs, err := models.GetCustomGameResultParticipantDAOs_byResultId(tx, resultDAO.Id)
+		if err != nil {
+			return err
+		}
+		if err := applyCustomGameResultRatings(tx, resultDAO, participantDAOs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyCustomGameResultRatings updates ratings of participants (overall & played position), and saves participants with rating changes
+func applyCustomGameResultRatings(tx db.Context, resultDAO models.CustomGameResultDAO, participantDAOs []models.CustomGameResultParticipantDAO) error {
+	overallRatingDAOs := make([]*models.CustomGameRatingDAO, len(participantDAOs))
+	positionRatingDAOs := make([]*models.CustomGameRatingDAO, len(participantDAOs))
+	for k, participantDAO := range participantDAOs {
+		ratingDAOs, err := models.GetCustomGameRatingDAOs_byPuuid(tx, resultDAO.GroupUid, participantDAO.Puuid)
+		if err != nil {
+			return err
+		}
+		for i := range ratingDAOs {
+			switch ratingDAOs[i].Position {
+			case types.CustomGameRatingPositionAll:
+				overallRatingDAOs[k] = &ratingDAOs[i]
+			case participantDAO.Position:
+				positionRatingDAOs[k] = &ratingDAOs[i]
+			}
+		}
+
+		// new player starts from representative rating point, new position from overall rating
+		if overallRatingDAOs[k] == nil {
+			overallRatingDAOs[k] = newCustomGameRatingDAO(resultDAO.GroupUid, participantDAO.Puuid, types.CustomGameRatingPositionAll, participantDAO.RatingPoint)
+		}
+		if positionRatingDAOs[k] == nil {
+			positionRatingDAOs[k] = newCustomGameRatingDAO(resultDAO.GroupUid, participantDAO.Puuid, participantDAO.Position, overallRatingDAOs[k].Rating)
+		}
+	}
+
+	teams := make([]int, len(participantDAOs))
+	for k, participantDAO := range participantDAOs {
+		teams[k] = participantDAO.Team
+	}
+	newOverallRatings := updateCustomGameTeamRatings(overallRatingDAOs, teams, resultDAO.Winner)
+	newPositionRatings := updateCustomGameTeamRatings(positionRatingDAOs, teams, resultDAO.Winner)
+
+	playedAt := resultDAO.PlayedAt
+	for k := range participantDAOs {
+		participantDAOs[k].RatingBefore = overallRatingDAOs[k].Rating
+		participantDAOs[k].RatingAfter = newOverallRatings[k].rating
+		if err := participantDAOs[k].Upsert(tx); err != nil {
+			return err
+		}
+
+		won := participantDAOs[k].Team == resultDAO.Winner
+		for _, updated := range []struct {
+			ratingDAO *models.CustomGameRatingDAO
+			rating    customGameRating
+		}{
+			{overallRatingDAOs[k], newOverallRatings[k]},
+			{positionRatingDAOs[k], newPositionRatings[k]},
+		} {
+			updated.ratingDAO.Rating = updated.rating.rating
+			updated.ratingDAO.Deviation = updated.rating.deviation
+			updated.ratingDAO.Volatility = updated.rating.volatility
+			updated.ratingDAO.GameCount++
+			if won {
+				updated.ratingDAO.WinCount++
+			}
+			updated.ratingDAO.LastPlayedAt = &playedAt
+			if err := updated.ratingDAO.Upsert(tx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newCustomGameRatingDAO(groupUid, puuid, position string, rating float64) *models.CustomGameRatingDAO {
+	return &models.CustomGameRatingDAO{
+		GroupUid:   groupUid,
+		Puuid:      puuid,
+		Position:   position,
+		Rating:     rating,
+		Deviation:  types.CustomGameRatingInitialDeviation,
+		Volatility: types.CustomGameRatingInitialVolatility,
+	}
+}
+
+// updateCustomGameTeamRatings returns new ratings of players (by index) after a game between team 1 and 2
+func updateCustomGameTeamRatings(ratingDAOs []*models.CustomGameRatingDAO, teams []int, winner int) []customGameRating {
+	var ratingSums, deviationSquareSums [3]float64
+	var teamSizes [3]int
+	for k, ratingDAO := range ratingDAOs {
+		ratingSums[teams[k]] += ratingDAO.Rating
+		deviationSquareSums[teams[k]] += ratingDAO.Deviation * ratingDAO.Deviation
+		teamSizes[teams[k]]++
+	}
+
+	newRatings := make([]customGameRating, len(ratingDAOs))
+	for k, ratingDAO := range ratingDAOs {
+		team, opponentTeam := teams[k], 3-teams[k]
+		teamAverage := ratingSums[team] / float64(teamSizes[team])
+		opponentAverage := ratingSums[opponentTeam] / float64(teamSizes[opponentTeam])
+		opponentDeviation := math.Sqrt(deviationSquareSums[opponentTeam] / float64(teamSizes[opponentTeam]))
+
+		var score float64 = 0
+		if team == winner {
+			score = 1
+		}
+		newRatings[k] = updateCustomGameRating(
+			customGameRating{rating: ratingDAO.Rating, deviation: ratingDAO.Deviation, volatility: ratingDAO.Volatility},
+			[]customGameRatingGame{{
+				opponentRating:    ratingDAO.Rating + opponentAverage - teamAverage,
+				opponentDeviation: opponentDeviation,
+				score:             score,
+			}},
+		)
+	}
+	return newRatings
+}
+
+// updateCustomGameRating is a Glicko-2 update of rating period with given games (each in-house game is a period of its own)
+func updateCustomGameRating(r customGameRating, games []customGameRatingGame) customGameRating {
+	mu := r.rating / customGameGlicko2Scale
+	phi := r.deviation / customGameGlicko2Scale
+
+	vInverse, improvement := 0.0, 0.0
+	for _, game := range games {
+		opponentMu := game.opponentRating / customGameGlicko2Scale
+		opponentPhi := game.opponentDeviation / customGameGlicko2Scale
+		g := 1 / math.Sqrt(1+3*opponentPhi*opponentPhi/(math.Pi*math.Pi))
+		expected := 1 / (1 + math.Exp(-g*(mu-opponentMu)))
+		vInverse += g * g * expected * (1 - expected)
+		improvement += g * (game.score - expected)
+	}
+	v := 1 / vInverse
+	delta := v * improvement
+
+	sigma := updateCustomGameRatingVolatility(phi, r.volatility, v, delta)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*improvement
+
+	return customGameRating{
+		rating:     newMu * customGameGlicko2Scale,
+		deviation:  math.Min(newPhi*customGameGlicko2Scale, types.CustomGameRatingInitialDeviation),
+		volatility: sigma,
+	}
+}
+
+// updateCustomGameRatingVolatility finds new volatility by Illinois algorithm (step 5 of Glicko-2)
+func updateCustomGameRatingVolatility(phi, sigma, v, delta float64) float64 {
+	const epsilon = 1e-6
+	tau := types.CustomGameRatingTau
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	lower := a
+	var upper float64
+	if delta*delta > phi*phi+v {
+		upper = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		upper = a - k*tau
+	}
+
+	fLower, fUpper := f(lower), f(upper)
+	for math.Abs(upper-lower) > epsilon {
+		c := lower + (lower-upper)*fLower/(fUpper-fLower)
+		fC := f(c)
+		if fC*fUpper < 0 {
+			lower, fLower = upper, fUpper
+		} else {
+			fLower /= 2
+		}
+		upper, fUpper = c, fC
+	}
+	return math.Exp(lower / 2)
+}
+
+func GetCustomGameResultVOs(db db.Context, configId string) ([]CustomGameResultVO, error) {
+	resultDAOs, err := models.GetCustomGameResultDAOs_byCustomGameConfigId(db, configId)
+	if err != nil {
+		return nil, err
+	}
+	resultVOs := make([]CustomGameResultVO, 0)
+	for _, resultDAO := range resultDAOs {
+		participantDAOs, err := models.GetCustomGameResultParticipantDAOs_byResultId(db, resultDAO.Id)
+		if err != nil {
+			return nil, err
+		}
+		resultVO := customGameResultVO(resultDAO, participantDAOs)
+		if err := attachCustomGameResultStats(db, &resultVO); err != nil {
+			return nil, err
+		}
+		resultVOs = append(resultVOs, resultVO)
+	}
+	return resultVOs, nil
+}
+
+func GetCustomGameRatingVOs(db db.Context, groupUid string) ([]CustomGameRatingVO, error) {
+	ratingDAOs, err := models.GetCustomGameRatingDAOs_byGroupUid(db, groupUid)
+	if err != nil {
+		return nil, err
+	}
+	ratingVOs := make([]CustomGameRatingVO, 0)
+	for _, ratingDAO := range ratingDAOs {
+		ratingVOs = append(ratingVOs, CustomGameRatingMixer(ratingDAO))
+	}
+	return ratingVOs, nil
+}
+
+func customGameResultVO(resultDAO models.CustomGameResultDAO, participantDAOs []models.CustomGameResultParticipantDAO) CustomGameResultVO {
+	resultVO := CustomGameResultVO{
+		Id:                 resultDAO.Id,
+		CustomGameConfigId: resultDAO.CustomGameConfigId,
+		Winner:             resultDAO.Winner,
+		RiotMatchId:        resultDAO.RiotMatchId,
+		PlayedAt:           resultDAO.PlayedAt,
+		Team1:              make([]CustomGameResultParticipantVO, 0),
+		Team2:              make([]CustomGameResultParticipantVO, 0),
+	}
+	// ordered by position
+	for _, position := range GetSupportedPositions {
+		for _, participantDAO := range participantDAOs {
+			if participantDAO.Position != position {
+				continue
+			}
+			if participantDAO.Team == 1 {
+				resultVO.Team1 = append(resultVO.Team1, CustomGameResultParticipantMixer(participantDAO))
+			} else {
+				resultVO.Team2 = append(resultVO.Team2, CustomGameResultParticipantMixer(participantDAO))
+			}
+		}
+	}
+	return resultVO
+}
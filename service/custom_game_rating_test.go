@@ -0,0 +1,79 @@
+package service
+
+import (
+	"math"
+	"testing"
+)
+
+// reference values are from worked example of Glicko-2 paper (Glickman) and its straightforward implementation
+
+func TestUpdateCustomGameRating(t *testing.T) {
+	tests := []struct {
+		name           string
+		rating         customGameRating
+		games          []customGameRatingGame
+		wantRating     float64
+		wantDeviation  float64
+		wantVolatility float64
+	}{
+		{
+			name:   "glickman example",
+			rating: customGameRating{rating: 1500, deviation: 200, volatility: 0.06},
+			games: []customGameRatingGame{
+				{opponentRating: 1400, opponentDeviation: 30, score: 1},
+				{opponentRating: 1550, opponentDeviation: 100, score: 0},
+				{opponentRating: 1700, opponentDeviation: 300, score: 0},
+			},
+			wantRating:     1464.06,
+			wantDeviation:  151.52,
+			wantVolatility: 0.05999,
+		},
+		{
+			name:           "win against equal",
+			rating:         customGameRating{rating: 1500, deviation: 200, volatility: 0.06},
+			games:          []customGameRatingGame{{opponentRating: 1500, opponentDeviation: 200, score: 1}},
+			wantRating:     1578.80,
+			wantDeviation:  180.08,
+			wantVolatility: 0.06000,
+		},
+		{
+			name:           "loss against equal",
+			rating:         customGameRating{rating: 1500, deviation: 200, volatility: 0.06},
+			games:          []customGameRatingGame{{opponentRating: 1500, opponentDeviation: 200, score: 0}},
+			wantRating:     1421.20,
+			wantDeviation:  180.08,
+			wantVolatility: 0.06000,
+		},
+		{
+			name:           "upset win raises volatility",
+			rating:         customGameRating{rating: 1500, deviation: 150, volatility: 0.06},
+			games:          []customGameRatingGame{{opponentRating: 2200, opponentDeviation: 30, score: 1}},
+			wantRating:     1625.63,
+			wantDeviation:  149.40,
+			wantVolatility: 0.06001,
+		},
+		{
+			name:           "upset loss of certain player",
+			rating:         customGameRating{rating: 1500, deviation: 50, volatility: 0.06},
+			games:          []customGameRatingGame{{opponentRating: 1300, opponentDeviation: 50, score: 0}},
+			wantRating:     1488.94,
+			wantDeviation:  50.68,
+			wantVolatility: 0.06000,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := updateCustomGameRating(test.rating, test.games)
+			if math.Abs(got.rating-test.wantRating) > 0.05 {
+				t.Errorf("rating = %.4f, want %.2f", got.rating, test.wantRating)
+			}
+			if math.Abs(got.deviation-test.wantDeviation) > 0.05 {
+				t.Errorf("deviation = %.4f, want %.2f", got.deviation, test.wantDeviation)
+			}
+			if math.Abs(got.volatility-test.wantVolatility) > 1e-5 {
+				t.Errorf("volatility = %.6f, want %.5f", got.volatility, test.wantVolatility)
+			}
+		})
+	}
+}
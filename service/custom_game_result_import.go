@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/third_party/riot/api"
+	"team.gg-server/types"
+	"time"
+)
+
+// result import links custom game played on riot server (custom lobby or tournament code) back to configuration.
+// every participant plays the same match, so recent matches of a single participant are searched
+// for the one where the same 10 players played on the arranged teams.
+
+var ErrCustomGameResultMatchNotFound = errors.New("no custom game matches the arrangement in time window")
+
+// ImportCustomGameResult finds custom game played by current arrangement in time window, and records its result.
+// version of configuration is increased with the result (if it's still expected version), and returned.
+func ImportCustomGameResult(ctx context.Context, configId string, expectedVersion *int, startTime, endTime time.Time) (*CustomGameResultVO, int, error) {
+	participantVOsMap, err := GetCurrentCustomGameTeamParticipantVOMap(db.Root, configId)
+	if err != nil {
+		return nil, 0, err
+	}
+	var searcher *CustomGameTeamParticipantVO
+	teamSizes := make(map[int]int)
+	for _, participant := range participantVOsMap {
+		teamSizes[participant.Team]++
+		// any participant works, picked deterministically
+		if searcher == nil || participant.Summary.Puuid < searcher.Summary.Puuid {
+			p := participant
+			searcher = &p
+		}
+	}
+	if teamSizes[1] != len(GetSupportedPositions) || teamSizes[2] != len(GetSupportedPositions) {
+		return nil, 0, ErrCustomGameResultIncompleteTeams
+	}
+
+	platform, puuid := searcher.Summary.Platform, searcher.Summary.Puuid
+	matchIds, err := api.GetMatchIdsInterval(ctx, platform, puuid, &api.MatchIdsReqOption{
+		Count:     types.CustomGameResultImportMatchCount,
+		StartTime: &startTime,
+		EndTime:   &endTime,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := RenewSummonerMatchesIfNecessary(ctx, db.Root, platform, puuid, *matchIds); err != nil {
+		return nil, 0, err
+	}
+
+	// match ids are ordered by recent first
+	for _, matchId := range *matchIds {
+		matchDAO, exists, err := models.GetMatchDAO(db.Root, matchId)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !exists || (matchDAO.GameType != types.GameTypeCustom && matchDAO.TournamentCode == "") {
+			continue
+		}
+		matchParticipantDAOs, err := models.GetMatchParticipantDAOs(db.Root, matchId)
+		if err != nil {
+			return nil, 0, err
+		}
+		winner, matched := matchCustomGameArrangement(participantVOsMap, matchParticipantDAOs)
+		if !matched {
+			continue
+		}
+
+		tx, err := db.Root.BeginTxx(ctx, nil)
+		if err != nil {
+			return nil, 0, err
+		}
+		version, err := IncreaseCustomGameVersion(tx, configId, expectedVersion)
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, 0, err
+		}
+		playedAt := time.UnixMilli(matchDAO.GameStartTimestamp)
+		resultVO, err := RecordCustomGameResult(tx, configId, winner, &matchDAO.MatchId, playedAt)
+		if err != nil {
+			_ = tx.Rollback()
+			if errors.Is(err, ErrCustomGameResultAlreadyRecorded) {
+				// same arrangement may be played several times
+				continue
+			}
+			return nil, 0, err
+		}
+		if err := tx.Commit(); err != nil {
+			_ = tx.Rollback()
+			return nil, 0, err
+		}
+
+		if err := attachCustomGameResultStats(db.Root, resultVO); err != nil {
+			return nil, 0, err
+		}
+		return resultVO, version, nil
+	}
+	return nil, 0, ErrCustomGameResultMatchNotFound
+}
+
+// matchCustomGameArrangement checks match is played by participants on arranged teams (either side), and returns winner team
+func matchCustomGameArrangement(participantVOsMap map[string]CustomGameTeamParticipantVO, matchParticipantDAOs []models.MatchParticipantDAO) (int, bool) {
+	if len(matchParticipantDAOs) != len(participantVOsMap) {
+		return 0, false
+	}
+
+	// riot team id of each arranged team
+	riotTeamIds := make(map[int]int)
+	winner := 0
+	for _, matchParticipantDAO := range matchParticipantDAOs {
+		participant, exists := participantVOsMap[matchParticipantDAO.Puuid]
+		if !exists {
+			return 0, false
+		}
+		if riotTeamId, exists := riotTeamIds[participant.Team]; exists && riotTeamId != matchParticipantDAO.TeamId {
+			return 0, false
+		}
+		riotTeamIds[participant.Team] = matchParticipantDAO.TeamId
+		if matchParticipantDAO.Win {
+			winner = participant.Team
+		}
+	}
+	if riotTeamIds[1] == riotTeamIds[2] || winner == 0 {
+		return 0, false
+	}
+	return winner, true
+}
+
+// attachCustomGameResultStats fills stats of participants from linked riot match
+func attachCustomGameResultStats(db db.Context, resultVO *CustomGameResultVO) error {
+	if resultVO.RiotMatchId == nil {
+		return nil
+	}
+	matchParticipantDAOs, err := models.GetMatchParticipantDAOs(db, *resultVO.RiotMatchId)
+	if err != nil {
+		return err
+	}
+	statsMap := make(map[string]CustomGameResultParticipantStatsVO)
+	for _, matchParticipantDAO := range matchParticipantDAOs {
+		statsMap[matchParticipantDAO.Puuid] = CustomGameResultParticipantStatsMixer(matchParticipantDAO)
+	}
+	for _, team := range [][]CustomGameResultParticipantVO{resultVO.Team1, resultVO.Team2} {
+		for k := range team {
+			if stats, exists := statsMap[team[k].Puuid]; exists {
+				team[k].Stats = &stats
+			}
+		}
+	}
+	return nil
+}
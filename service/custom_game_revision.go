@@ -0,0 +1,265 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"reflect"
+	"sort"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/types"
+	"time"
+)
+
+// revisions track arrangement, weights & fairness model of configuration (mutations which don't change them append nothing).
+// undo applies before-state of last applied revision and marks it undone, redo applies after-state of first undone one.
+// new revision discards undone revisions, as usual redo history.
+
+var (
+	ErrCustomGameRevisionNothingToUndo = errors.New("nothing to undo")
+	ErrCustomGameRevisionNothingToRedo = errors.New("nothing to redo")
+	ErrCustomGameRevisionNotFound      = errors.New("revision not found")
+)
+
+// GetCustomGameArrangementSnapshot returns current arrangement, weights & fairness model of configuration
+func GetCustomGameArrangementSnapshot(db db.Context, configId string) (*CustomGameArrangementSnapshotVO, error) {
+	configDAO, exists, err := models.GetCustomGameDAO_byId(db, configId)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCustomGameConfigurationNotExists
+	}
+	participantDAOs, err := models.GetCustomGameParticipantDAOs_byCustomGameConfigId(db, configId)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := CustomGameArrangementSnapshotVO{
+		Team1:         make([]CustomGameParticipantVO, 0),
+		Team2:         make([]CustomGameParticipantVO, 0),
+		Weights:       CustomGameConfigurationWeightsMixer(*configDAO),
+		FairnessModel: configDAO.FairnessModel,
+	}
+	for _, participantDAO := range participantDAOs {
+		if participantDAO.Team == 1 {
+			snapshot.Team1 = append(snapshot.Team1, CustomGameConfigurationParticipantMixer(*participantDAO))
+		} else {
+			snapshot.Team2 = append(snapshot.Team2, CustomGameConfigurationParticipantMixer(*participantDAO))
+		}
+	}
+	for _, team := range [][]CustomGameParticipantVO{snapshot.Team1, snapshot.Team2} {
+		sort.Slice(team, func(i, j int) bool {
+			return getPositionIndex(team[i].Position) < getPositionIndex(team[j].Position)
+		})
+	}
+	return &snapshot, nil
+}
+
+// RecordCustomGameRevision appends revision from before-state to current state, if changed
+func RecordCustomGameRevision(db db.Context, configId string, actorUid *string, operation string, before CustomGameArrangementSnapshotVO) error {
+	after, err := GetCustomGameArrangementSnapshot(db, configId)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(before, *after) {
+		return nil
+	}
+
+	beforeState, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+	afterState, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	if err := models.DeleteUndoneCustomGameRevisionDAOs(db, configId); err != nil {
+		return err
+	}
+	maxSeq, err := models.GetMaxCustomGameRevisionSeq(db, configId)
+	if err != nil {
+		return err
+	}
+	revisionDAO := models.CustomGameRevisionDAO{
+		Id:                 uuid.New().String(),
+		CustomGameConfigId: configId,
+		Seq:                maxSeq + 1,
+		ActorUid:           actorUid,
+		Operation:          operation,
+		BeforeState:        string(beforeState),
+		AfterState:         string(afterState),
+		Undone:             false,
+		CreatedAt:          time.Now(),
+	}
+	if err := revisionDAO.Upsert(db); err != nil {
+		return err
+	}
+	return models.DeleteOldCustomGameRevisionDAOs(db, configId, revisionDAO.Seq-types.CustomGameRevisionMaxCount+1)
+}
+
+func GetCustomGameRevisionVOs(db db.Context, configId string) ([]CustomGameRevisionVO, error) {
+	revisionDAOs, err := models.GetCustomGameRevisionDAOs_byCustomGameConfigId(db, configId)
+	if err != nil {
+		return nil, err
+	}
+	revisionVOs := make([]CustomGameRevisionVO, 0)
+	for _, revisionDAO := range revisionDAOs {
+		revisionVO, err := customGameRevisionVO(revisionDAO)
+		if err != nil {
+			return nil, err
+		}
+		revisionVOs = append(revisionVOs, *revisionVO)
+	}
+	return revisionVOs, nil
+}
+
+// UndoCustomGameRevision reverts last applied revision
+func UndoCustomGameRevision(tx db.Context, configId string) (*CustomGameRevisionVO, error) {
+	revisionDAO, exists, err := models.GetLastAppliedCustomGameRevisionDAO(tx, configId)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCustomGameRevisionNothingToUndo
+	}
+	revisionVO, err := customGameRevisionVO(*revisionDAO)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyCustomGameArrangementSnapshot(tx, configId, revisionVO.Before); err != nil {
+		return nil, err
+	}
+	revisionDAO.Undone = true
+	if err := revisionDAO.Upsert(tx); err != nil {
+		return nil, err
+	}
+	revisionVO.Undone = true
+	return revisionVO, nil
+}
+
+// RedoCustomGameRevision re-applies first undone revision
+func RedoCustomGameRevision(tx db.Context, configId string) (*CustomGameRevisionVO, error) {
+	revisionDAO, exists, err := models.GetFirstUndoneCustomGameRevisionDAO(tx, configId)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCustomGameRevisionNothingToRedo
+	}
+	revisionVO, err := customGameRevisionVO(*revisionDAO)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyCustomGameArrangementSnapshot(tx, configId, revisionVO.After); err != nil {
+		return nil, err
+	}
+	revisionDAO.Undone = false
+	if err := revisionDAO.Upsert(tx); err != nil {
+		return nil, err
+	}
+	revisionVO.Undone = false
+	return revisionVO, nil
+}
+
+// RestoreCustomGameRevision brings back state right after given revision, as a new revision
+func RestoreCustomGameRevision(tx db.Context, configId string, revisionId string, actorUid *string) (*CustomGameRevisionVO, error) {
+	revisionDAO, exists, err := models.GetCustomGameRevisionDAO_byId(tx, configId, revisionId)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCustomGameRevisionNotFound
+	}
+	revisionVO, err := customGameRevisionVO(*revisionDAO)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := GetCustomGameArrangementSnapshot(tx, configId)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyCustomGameArrangementSnapshot(tx, configId, revisionVO.After); err != nil {
+		return nil, err
+	}
+	if err := RecordCustomGameRevision(tx, configId, actorUid, types.CustomGameOperationRestore, *before); err != nil {
+		return nil, err
+	}
+	return revisionVO, nil
+}
+
+// applyCustomGameArrangementSnapshot rewrites arrangement, weights & fairness model (players who are no longer candidates are left out)
+func applyCustomGameArrangementSnapshot(tx db.Context, configId string, snapshot CustomGameArrangementSnapshotVO) error {
+	configDAO, exists, err := models.GetCustomGameDAO_byId(tx, configId)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrCustomGameConfigurationNotExists
+	}
+	configDAO.LineFairnessWeight = snapshot.Weights.LineFairness
+	configDAO.TierFairnessWeight = snapshot.Weights.TierFairness
+	configDAO.LineSatisfactionWeight = snapshot.Weights.LineSatisfaction
+	configDAO.TopInfluenceWeight = snapshot.Weights.TopInfluence
+	configDAO.JungleInfluenceWeight = snapshot.Weights.JungleInfluence
+	configDAO.MidInfluenceWeight = snapshot.Weights.MidInfluence
+	configDAO.AdcInfluenceWeight = snapshot.Weights.AdcInfluence
+	configDAO.SupportInfluenceWeight = snapshot.Weights.SupportInfluence
+	configDAO.InHouseRatingWeight = snapshot.Weights.InHouseRating
+	configDAO.ChampionPoolWeight = snapshot.Weights.ChampionPool
+	// snapshots recorded before fairness model was tracked keep current model
+	if snapshot.FairnessModel != "" {
+		configDAO.FairnessModel = snapshot.FairnessModel
+	}
+	if err := configDAO.Upsert(tx); err != nil {
+		return err
+	}
+
+	candidateDAOs, err := models.GetCustomGameCandidateDAOs_byCustomGameConfigId(tx, configId)
+	if err != nil {
+		return err
+	}
+	isCandidate := make(map[string]bool)
+	for _, candidateDAO := range candidateDAOs {
+		isCandidate[candidateDAO.Puuid] = true
+	}
+	arrangement := CustomGameOptimizedConfigurationVO{
+		Team1: make([]CustomGameParticipantVO, 0),
+		Team2: make([]CustomGameParticipantVO, 0),
+	}
+	for _, participant := range snapshot.Team1 {
+		if isCandidate[participant.Puuid] {
+			arrangement.Team1 = append(arrangement.Team1, participant)
+		}
+	}
+	for _, participant := range snapshot.Team2 {
+		if isCandidate[participant.Puuid] {
+			arrangement.Team2 = append(arrangement.Team2, participant)
+		}
+	}
+	if err := applyCustomGameArrangement(tx, configId, arrangement, true); err != nil {
+		return err
+	}
+	return RecalculateCustomGameBalance(tx, configId)
+}
+
+func customGameRevisionVO(d models.CustomGameRevisionDAO) (*CustomGameRevisionVO, error) {
+	revisionVO := CustomGameRevisionVO{
+		Id:        d.Id,
+		Seq:       d.Seq,
+		ActorUid:  d.ActorUid,
+		Operation: d.Operation,
+		Undone:    d.Undone,
+		CreatedAt: d.CreatedAt,
+	}
+	if err := json.Unmarshal([]byte(d.BeforeState), &revisionVO.Before); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(d.AfterState), &revisionVO.After); err != nil {
+		return nil, err
+	}
+	return &revisionVO, nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"sort"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"time"
+)
+
+// share tokens expose read-only view of teams & balance to anyone having the link, even if configuration is private.
+// revoking token (deleting it) stops the link from working.
+
+var ErrCustomGameShareTokenNotFound = errors.New("share token not found or expired")
+
+// CheckViewPermissionForCustomGameConfig checks user can view configuration by id (public, or member of private one)
+func CheckViewPermissionForCustomGameConfig(db db.Context, configId string, uid string) (bool, error) {
+	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(db, configId)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, nil
+	}
+	if !customGameConfigurationDAO.IsPrivate {
+		return true, nil
+	}
+	if uid == "" {
+		return false, nil
+	}
+	_, isMember, err := GetCustomGameRole(db, *customGameConfigurationDAO, uid)
+	if err != nil {
+		return false, err
+	}
+	return isMember, nil
+}
+
+// CreateCustomGameShareToken creates share token, which never expires if expiry is nil
+func CreateCustomGameShareToken(db db.Context, configId string, creatorUid string, expiry *time.Duration) (*CustomGameShareTokenVO, error) {
+	now := time.Now()
+	shareTokenDAO := models.CustomGameShareTokenDAO{
+		Token:              uuid.New().String(),
+		CustomGameConfigId: configId,
+		CreatorUid:         creatorUid,
+		CreatedAt:          now,
+	}
+	if expiry != nil {
+		expiresAt := now.Add(*expiry)
+		shareTokenDAO.ExpiresAt = &expiresAt
+	}
+	if err := shareTokenDAO.Insert(db); err != nil {
+		return nil, err
+	}
+	shareTokenVO := CustomGameShareTokenMixer(shareTokenDAO)
+	return &shareTokenVO, nil
+}
+
+func GetCustomGameShareTokenVOs(db db.Context, configId string) ([]CustomGameShareTokenVO, error) {
+	shareTokenDAOs, err := models.GetCustomGameShareTokenDAOs_byCustomGameConfigId(db, configId)
+	if err != nil {
+		return nil, err
+	}
+	shareTokenVOs := make([]CustomGameShareTokenVO, 0)
+	for _, shareTokenDAO := range shareTokenDAOs {
+		shareTokenVOs = append(shareTokenVOs, CustomGameShareTokenMixer(shareTokenDAO))
+	}
+	return shareTokenVOs, nil
+}
+
+// GetCustomGameSharedViewVO returns read-only view of current arrangement by share token
+func GetCustomGameSharedViewVO(db db.Context, token string) (*CustomGameSharedViewVO, error) {
+	shareTokenDAO, exists, err := models.GetCustomGameShareTokenDAO_byToken(db, token)
+	if err != nil {
+		return nil, err
+	}
+	if !exists || shareTokenDAO.IsExpired(time.Now()) {
+		return nil, ErrCustomGameShareTokenNotFound
+	}
+	customGameConfigurationDAO, exists, err := models.GetCustomGameDAO_byId(db, shareTokenDAO.CustomGameConfigId)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCustomGameShareTokenNotFound
+	}
+
+	participantVOsMap, err := GetCurrentCustomGameTeamParticipantVOMap(db, customGameConfigurationDAO.Id)
+	if err != nil {
+		return nil, err
+	}
+	sharedViewVO := CustomGameSharedViewVO{
+		Name:          customGameConfigurationDAO.Name,
+		LastUpdatedAt: customGameConfigurationDAO.LastUpdatedAt,
+		Balance:       CustomGameConfigurationFairnessMixer(*customGameConfigurationDAO),
+		Team1:         make([]CustomGameSharedParticipantVO, 0),
+		Team2:         make([]CustomGameSharedParticipantVO, 0),
+	}
+	for _, participant := range participantVOsMap {
+		sharedParticipantVO := CustomGameSharedParticipantVO{
+			Position: participant.Position,
+			Summary:  participant.Summary,
+			Rank:     participant.GetRepresentativeRank(),
+		}
+		if participant.Team == 1 {
+			sharedViewVO.Team1 = append(sharedViewVO.Team1, sharedParticipantVO)
+		} else {
+			sharedViewVO.Team2 = append(sharedViewVO.Team2, sharedParticipantVO)
+		}
+	}
+	for _, team := range [][]CustomGameSharedParticipantVO{sharedViewVO.Team1, sharedViewVO.Team2} {
+		sort.Slice(team, func(i, j int) bool {
+			return getPositionIndex(team[i].Position) < getPositionIndex(team[j].Position)
+		})
+	}
+	return &sharedViewVO, nil
+}
+
+// SetCustomGameConfigPrivacy makes configuration private (members only) or public
+func SetCustomGameConfigPrivacy(db db.Context, configDAO models.CustomGameConfigurationDAO, isPrivate bool) error {
+	configDAO.IsPrivate = isPrivate
+	configDAO.LastUpdatedAt = time.Now()
+	return configDAO.Upsert(db)
+}
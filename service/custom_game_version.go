@@ -0,0 +1,32 @@
+package service
+
+import (
+	"errors"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+)
+
+// every change of configuration increases its version. mutating requests carry version they're based on,
+// so that concurrent edits of organizers based on stale state are rejected instead of silently overwriting.
+
+var ErrCustomGameVersionMismatch = errors.New("custom game configuration has been changed by others")
+
+// IncreaseCustomGameVersion increases version of configuration and returns new version.
+// fails with ErrCustomGameVersionMismatch if current version is not expected one (not checked if nil).
+func IncreaseCustomGameVersion(db db.Context, configId string, expectedVersion *int) (int, error) {
+	increased, err := models.IncreaseCustomGameVersion(db, configId, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	configDAO, exists, err := models.GetCustomGameDAO_byId(db, configId)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, ErrCustomGameConfigurationNotExists
+	}
+	if !increased {
+		return 0, ErrCustomGameVersionMismatch
+	}
+	return configDAO.Version, nil
+}
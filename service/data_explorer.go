@@ -2,37 +2,110 @@ package service
 
 import (
 	"context"
+	"errors"
 	log "github.com/shyunku-libraries/go-logger"
+	"math"
+	"os"
+	"strconv"
+	"sync"
 	"team.gg-server/libs/db"
 	"team.gg-server/models"
+	"team.gg-server/third_party/riot"
 	"team.gg-server/third_party/riot/api"
 	"team.gg-server/types"
 	"time"
 )
 
+// DataExplorer crawls summoners in crawl frontier (persistent, priority ordered) with multiple workers.
+// all workers share riot rate limiter, and crawling requests are sent with background priority.
 type DataExplorer struct {
+	mutex            sync.Mutex
 	lastExploredTime *time.Time
 	exploreCaches    int
 	cacheHit         int
+
+	workerCount        int
+	ladder             *ladderExplorer
+	lastStaleCheckedAt *time.Time
+
+	crawlWorker *Worker
+	seedWorker  *Worker
 }
 
 func NewDataExplorer() *DataExplorer {
-	return &DataExplorer{
+	de := &DataExplorer{
 		lastExploredTime: nil,
 		exploreCaches:    0,
+		workerCount:      types.DataExplorerWorkerCount,
+		ladder:           newLadderExplorer(),
+		crawlWorker:      NewWorker("data_explorer"),
+		seedWorker:       NewWorker("crawl_frontier_seeder"),
+	}
+	if workerCount, err := strconv.Atoi(os.Getenv("DATA_EXPLORER_WORKERS")); err == nil && workerCount > 0 {
+		de.workerCount = workerCount
+	}
+	return de
+}
+
+// Loop runs crawling workers & seeder until context is done, and waits for them to stop
+func (de *DataExplorer) Loop(ctx context.Context, waitGroup *sync.WaitGroup) {
+	defer waitGroup.Done()
+
+	var workers sync.WaitGroup
+	for i := 0; i < de.workerCount; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			de.workerLoop(ctx)
+		}()
 	}
+	de.seedLoop(ctx)
+
+	workers.Wait()
+	log.Info("DataExplorer: stopped.")
 }
 
-func (de *DataExplorer) Loop() {
+func (de *DataExplorer) workerLoop(ctx context.Context) {
 	loopInterval := GetDataExplorerLoopPeriod()
-	for {
-		if de.Explore() {
-			time.Sleep(loopInterval)
+	for ctx.Err() == nil {
+		meaningful := false
+		_ = de.crawlWorker.Run(func() (int, error) {
+			var err error
+			meaningful, err = de.Explore(ctx)
+			if meaningful && err == nil {
+				return 1, nil
+			}
+			return 0, err
+		})
+
+		waitPeriod := loopInterval
+		if !meaningful {
+			// nothing to crawl
+			waitPeriod = types.DataExplorerSeedPeriod
+		}
+		if !de.crawlWorker.Wait(ctx, waitPeriod) {
+			return
+		}
+	}
+}
+
+func (de *DataExplorer) seedLoop(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := de.seedWorker.Run(func() (int, error) {
+			return 0, de.seed(ctx)
+		}); err != nil {
+			log.Error(err)
+		}
+		if !de.seedWorker.Wait(ctx, types.DataExplorerSeedPeriod) {
+			return
 		}
 	}
 }
 
 func (de *DataExplorer) finalizeExploration(meaningful, success bool) {
+	de.mutex.Lock()
+	defer de.mutex.Unlock()
+
 	now := time.Now()
 	de.lastExploredTime = &now
 	if meaningful {
@@ -46,30 +119,30 @@ func (de *DataExplorer) finalizeExploration(meaningful, success bool) {
 	}
 }
 
-func (de *DataExplorer) Explore() bool {
-	var err error
-	var meaningful bool
-	// update something new
-	meaningful, err = de.fetchNewSummoner()
-
+// Explore crawls a summoner from frontier, returns false if there's nothing to crawl
+func (de *DataExplorer) Explore(ctx context.Context) (bool, error) {
+	meaningful, err := de.crawlNextFrontier(ctx)
 	de.finalizeExploration(meaningful, err == nil)
-	return meaningful
+	return meaningful, err
 }
 
 func (de *DataExplorer) GetExploreCaches() int {
+	de.mutex.Lock()
+	defer de.mutex.Unlock()
 	return de.exploreCaches
 }
 
-func (de *DataExplorer) fetchNewSummoner() (bool, error) {
-	ctx := context.Background()
+func (de *DataExplorer) crawlNextFrontier(ctx context.Context) (bool, error) {
+	// crawling requests should not slow down user-facing lookups
+	ctx = riot.WithPriority(ctx, riot.PriorityBackground)
+
+	// claim next summoner (short transaction, so that other workers can claim others)
 	tx, err := db.Root.BeginTxx(ctx, nil)
 	if err != nil {
 		log.Error(err)
 		return false, err
 	}
-
-	// get random summoner match
-	participant, exists, err := models.GetRandomMatchParticipantDAO(tx)
+	frontier, exists, err := models.ClaimNextCrawlFrontierDAO(tx, time.Now(), types.DataExplorerCrawlLease)
 	if err != nil {
 		log.Error(err)
 		_ = tx.Rollback()
@@ -77,59 +150,217 @@ func (de *DataExplorer) fetchNewSummoner() (bool, error) {
 	}
 	if !exists {
 		_ = tx.Rollback()
-		return true, nil
+		return false, nil
 	}
-
-	// search for original summoner
-	_, exists, err = models.GetSummonerDAO_byPuuid(tx, participant.Puuid)
-	if err != nil {
+	if err := tx.Commit(); err != nil {
 		log.Error(err)
 		_ = tx.Rollback()
 		return false, err
 	}
-	if exists {
-		_ = tx.Rollback()
-		return true, nil
+
+	crawlErr := de.crawlSummoner(ctx, frontier.Platform, frontier.Puuid)
+
+	// result is recorded out of crawling transaction (to be kept even if crawling is rolled back)
+	now := time.Now()
+	if crawlErr != nil && ctx.Err() != nil {
+		// stopped by shutdown, not a failure of summoner (crawl again after restart)
+		if err := models.ReleaseCrawlFrontier(db.Root, frontier.Puuid); err != nil {
+			log.Error(err)
+		}
+		return true, crawlErr
+	}
+	if crawlErr != nil {
+		log.Error(crawlErr)
+		if err := models.MarkCrawlFrontierFailed(db.Root, frontier.Puuid, crawlErr.Error(), now.Add(crawlRetryDelay(frontier.Attempts))); err != nil {
+			log.Error(err)
+		}
+		return true, crawlErr
+	}
+	if err := models.MarkCrawlFrontierSucceeded(db.Root, frontier.Puuid, now.Add(types.DataExplorerStalePeriod)); err != nil {
+		log.Error(err)
+		return true, err
 	}
 
-	// get summoner info
-	summonerDAO, _, err := RenewSummonerInfoByPuuid(tx, participant.Puuid)
+	// failure of backfill is not a failure of summoner
+	if err := de.backfillMatchTimelines(ctx); err != nil {
+		log.Warn(err)
+	}
+	return true, nil
+}
+
+// backfillMatchTimelines fetches timelines of matches saved without it by user-facing requests
+func (de *DataExplorer) backfillMatchTimelines(ctx context.Context) error {
+	tx, err := db.Root.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	backlogDAOs, err := models.ClaimMatchTimelineBacklogDAOs(tx, types.DataExplorerTimelineBackfillCount)
 	if err != nil {
-		log.Error(err)
 		_ = tx.Rollback()
-		return true, err
+		return err
+	}
+	for _, backlogDAO := range backlogDAOs {
+		matchId := backlogDAO.MatchId
+		platform, ok := riot.GetPlatformByMatchId(matchId)
+		if ok {
+			timeline, err := api.GetMatchTimelineByMatchId(ctx, platform, matchId)
+			if err != nil && !errors.Is(err, riot.ErrNotFound) {
+				// transient, fetched again on next backfill
+				_ = tx.Rollback()
+				return err
+			}
+			if timeline != nil {
+				if err := saveMatchTimelineToLocalDB(tx, *timeline); err != nil {
+					_ = tx.Rollback()
+					return err
+				}
+			}
+		}
+		// timeline is saved, or riot has none
+		if err := models.DeleteMatchTimelineBacklog(tx, matchId); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
 	}
 
-	// get summoner rank
-	if err := RenewSummonerLeague(tx, summonerDAO.Id, summonerDAO.Puuid); err != nil {
-		log.Error(err)
+	if err := tx.Commit(); err != nil {
 		_ = tx.Rollback()
-		return true, err
+		return err
 	}
+	return nil
+}
 
-	// get summoner recent matches
-	if err := RenewSummonerMatches(tx, summonerDAO.Puuid, &api.MatchIdsReqOption{
-		QueueId: types.QueueTypeAll,
-		Count:   types.DataExplorerLoadMatchesCount,
-	}); err != nil {
-		log.Error(err)
+// crawlRetryDelay returns exponential backoff delay by consecutive failed attempts
+func crawlRetryDelay(attempts int) time.Duration {
+	delay := float64(types.DataExplorerRetryBaseDelay) * math.Pow(2, float64(attempts-1))
+	if delay > float64(types.DataExplorerRetryMaxDelay) {
+		return types.DataExplorerRetryMaxDelay
+	}
+	return time.Duration(delay)
+}
+
+func (de *DataExplorer) crawlSummoner(ctx context.Context, platform, puuid string) error {
+	tx, err := db.Root.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := de.exploreSummoner(ctx, tx, platform, puuid); err != nil {
 		_ = tx.Rollback()
-		return true, err
+		return err
 	}
 
-	// get summoner mastery
-	if err := RenewSummonerMastery(tx, summonerDAO.Id, summonerDAO.Puuid); err != nil {
-		log.Error(err)
+	// participants of crawled matches are next candidates
+	if err := models.EnqueueCrawlFrontiers_byMatchParticipants(tx, puuid, platform,
+		types.CrawlPriorityMatchParticipant, types.CrawlReasonMatchParticipant, time.Now()); err != nil {
 		_ = tx.Rollback()
-		return true, err
+		return err
 	}
 
 	if err := tx.Commit(); err != nil {
-		log.Error(err)
 		_ = tx.Rollback()
-		return true, err
+		return err
 	}
+	return nil
+}
 
-	//log.Debugf("DataExplorer: fetched new summoner %s#%s", summonerDAO.GameName, summonerDAO.TagLine)
-	return true, nil
+// seed fills frontier when it's running out, and enqueues stale summoners periodically
+func (de *DataExplorer) seed(ctx context.Context) error {
+	ctx = riot.WithPriority(ctx, riot.PriorityBackground)
+	now := time.Now()
+
+	if de.lastStaleCheckedAt == nil || now.Sub(*de.lastStaleCheckedAt) >= types.DataExplorerStaleCheckPeriod {
+		de.lastStaleCheckedAt = &now
+		if err := models.EnqueueCrawlFrontiers_byStaleSummoners(db.Root, now.Add(-types.DataExplorerStalePeriod),
+			types.CrawlPriorityStale, types.CrawlReasonStale, now, types.DataExplorerStaleEnqueueCount); err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+
+	dueCount, err := models.GetDueCrawlFrontierCount(db.Root, now)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if dueCount >= types.DataExplorerFrontierLowWatermark {
+		return nil
+	}
+
+	if de.ladder.shouldExplore() {
+		return de.ladder.seedFrontier(ctx)
+	}
+	return de.seedRandomSummoner()
+}
+
+// seedRandomSummoner enqueues random match participant not collected yet
+func (de *DataExplorer) seedRandomSummoner() error {
+	participant, exists, err := models.GetRandomMatchParticipantDAO(db.Root)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	_, exists, err = models.GetSummonerDAO_byPuuid(db.Root, participant.Puuid)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	// explore within the platform where the match was played
+	platform, ok := riot.GetPlatformByMatchId(participant.MatchId)
+	if !ok {
+		platform = riot.DefaultPlatform
+	}
+
+	now := time.Now()
+	frontier := &models.CrawlFrontierDAO{
+		Puuid:       participant.Puuid,
+		Platform:    platform,
+		Priority:    types.CrawlPriorityRandom,
+		Reason:      types.CrawlReasonRandom,
+		CreatedAt:   now,
+		NextCrawlAt: now,
+	}
+	if err := frontier.Enqueue(db.Root); err != nil {
+		log.Error(err)
+		return err
+	}
+	return nil
+}
+
+// exploreSummoner collects (or refreshes if stale) summoner info, rank, recent matches and mastery
+func (de *DataExplorer) exploreSummoner(ctx context.Context, tx db.Context, platform, puuid string) error {
+	// get summoner info
+	summonerDAO, err := RenewSummonerInfoByPuuid(ctx, tx, platform, puuid)
+	if err != nil {
+		return err
+	}
+
+	// get summoner rank
+	if err := RenewSummonerLeague(ctx, tx, summonerDAO.Platform, summonerDAO.Id, summonerDAO.Puuid); err != nil {
+		return err
+	}
+
+	// get summoner recent matches
+	if err := RenewSummonerMatches(ctx, tx, summonerDAO.Platform, summonerDAO.Puuid, &api.MatchIdsReqOption{
+		QueueId: types.QueueTypeAll,
+		Count:   types.DataExplorerLoadMatchesCount,
+	}); err != nil {
+		return err
+	}
+
+	// get summoner mastery
+	if err := RenewSummonerMastery(ctx, tx, summonerDAO.Platform, summonerDAO.Id, summonerDAO.Puuid); err != nil {
+		return err
+	}
+
+	return nil
 }
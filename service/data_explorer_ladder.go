@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	log "github.com/shyunku-libraries/go-logger"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"team.gg-server/libs/db"
+	"team.gg-server/models"
+	"team.gg-server/models/mixed"
+	"team.gg-server/third_party/riot"
+	"team.gg-server/third_party/riot/api"
+	"team.gg-server/types"
+	"time"
+)
+
+// ladder exploration seeds summoners from ranked ladders (league-v4, league-exp-v4),
+// so collected samples follow target tier distribution rather than tiers of our users.
+
+var defaultLadderTierDistribution = map[string]float64{
+	TierChallenger:  0.04,
+	TierGrandmaster: 0.06,
+	TierMaster:      0.10,
+	TierDiamond:     0.14,
+	TierEmerald:     0.14,
+	TierPlatinum:    0.14,
+	TierGold:        0.13,
+	TierSilver:      0.10,
+	TierBronze:      0.08,
+	TierIron:        0.07,
+}
+
+type ladderCursor struct {
+	divisionIndex int
+	page          int
+	// ladder of tier has no more unknown summoners until this time
+	exhaustedUntil *time.Time
+}
+
+type ladderExplorer struct {
+	platform     string
+	ratio        float64            // ratio of ladder seeding among frontier seedings
+	distribution map[string]float64 // target sample distribution by tier (sum = 1)
+
+	cursors map[string]*ladderCursor
+
+	tierCounts          map[string]int
+	tierCountsUpdatedAt *time.Time
+}
+
+func newLadderExplorer() *ladderExplorer {
+	le := &ladderExplorer{
+		platform:     riot.DefaultPlatform,
+		ratio:        types.DataExplorerLadderRatio,
+		distribution: defaultLadderTierDistribution,
+		cursors:      make(map[string]*ladderCursor),
+		tierCounts:   make(map[string]int),
+	}
+
+	if platform := os.Getenv("DATA_EXPLORER_LADDER_PLATFORM"); platform != "" {
+		if riot.IsSupportedPlatform(platform) {
+			le.platform = riot.NormalizePlatform(platform)
+		} else {
+			log.Warnf("unsupported ladder platform: %s, using %s", platform, le.platform)
+		}
+	}
+	if ratio, err := strconv.ParseFloat(os.Getenv("DATA_EXPLORER_LADDER_RATIO"), 64); err == nil && ratio >= 0 && ratio <= 1 {
+		le.ratio = ratio
+	}
+	if raw := os.Getenv("DATA_EXPLORER_TIER_DISTRIBUTION"); raw != "" {
+		distribution, err := parseTierDistribution(raw)
+		if err != nil {
+			log.Warnf("invalid tier distribution (%s): %v", raw, err)
+		} else {
+			le.distribution = distribution
+		}
+	}
+
+	for tier := range le.distribution {
+		le.cursors[tier] = &ladderCursor{divisionIndex: 0, page: 0}
+	}
+	return le
+}
+
+// parseTierDistribution parses "CHALLENGER:1,GRANDMASTER:1,MASTER:2,..." into normalized weights
+func parseTierDistribution(raw string) (map[string]float64, error) {
+	distribution := make(map[string]float64)
+	total := 0.0
+	for _, token := range strings.Split(raw, ",") {
+		pair := strings.Split(strings.TrimSpace(token), ":")
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("invalid token: %s", token)
+		}
+		tier := strings.ToUpper(strings.TrimSpace(pair[0]))
+		if _, exists := TierRankMap[Tier(tier)]; !exists || tier == TierUnranked {
+			return nil, fmt.Errorf("invalid tier: %s", tier)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(pair[1]), 64)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight: %s", pair[1])
+		}
+		distribution[tier] = weight
+		total += weight
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("sum of weights should be positive")
+	}
+	for tier, weight := range distribution {
+		distribution[tier] = weight / total
+	}
+	return distribution, nil
+}
+
+func (le *ladderExplorer) shouldExplore() bool {
+	return rand.Float64() < le.ratio
+}
+
+func (le *ladderExplorer) refreshTierCounts() error {
+	now := time.Now()
+	if le.tierCountsUpdatedAt != nil && now.Sub(*le.tierCountsUpdatedAt) < types.DataExplorerTierCountRefreshPeriod {
+		return nil
+	}
+
+	tierCountMXDAOs, err := mixed.GetLeagueTierCountMXDAOs(db.Root, types.RankTypeSolo)
+	if err != nil {
+		return err
+	}
+	tierCounts := make(map[string]int)
+	for _, tierCount := range tierCountMXDAOs {
+		tierCounts[tierCount.Tier] = tierCount.Count
+	}
+	le.tierCounts = tierCounts
+	le.tierCountsUpdatedAt = &now
+	return nil
+}
+
+// nextTargetTier returns tier which is the most lacking compared to target distribution
+func (le *ladderExplorer) nextTargetTier() (string, bool, error) {
+	if err := le.refreshTierCounts(); err != nil {
+		return "", false, err
+	}
+
+	total := 0
+	for tier := range le.distribution {
+		total += le.tierCounts[tier]
+	}
+
+	tiers := make([]string, 0, len(le.distribution))
+	for tier, weight := range le.distribution {
+		cursor := le.cursors[tier]
+		if weight <= 0 || (cursor.exhaustedUntil != nil && time.Now().Before(*cursor.exhaustedUntil)) {
+			continue
+		}
+		tiers = append(tiers, tier)
+	}
+	if len(tiers) == 0 {
+		return "", false, nil
+	}
+
+	deficit := func(tier string) float64 {
+		if total == 0 {
+			return le.distribution[tier]
+		}
+		return le.distribution[tier] - float64(le.tierCounts[tier])/float64(total)
+	}
+	sort.SliceStable(tiers, func(i, j int) bool {
+		if deficit(tiers[i]) == deficit(tiers[j]) {
+			return tiers[i] < tiers[j]
+		}
+		return deficit(tiers[i]) > deficit(tiers[j])
+	})
+	return tiers[0], true, nil
+}
+
+// seedFrontier fetches next page of ladder of the most lacking tier,
+// and enqueues summoners not collected yet into crawl frontier
+func (le *ladderExplorer) seedFrontier(ctx context.Context) error {
+	tier, ok, err := le.nextTargetTier()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	cursor := le.cursors[tier]
+	entries := make([]api.LeagueItemDto, 0)
+	exhausted := false
+
+	switch tier {
+	case TierChallenger, TierGrandmaster, TierMaster:
+		// apex tiers are returned at once
+		var league *api.LeagueListDto
+		if tier == TierChallenger {
+			league, err = api.GetChallengerLeague(ctx, le.platform, types.RankTypeSolo)
+		} else if tier == TierGrandmaster {
+			league, err = api.GetGrandmasterLeague(ctx, le.platform, types.RankTypeSolo)
+		} else {
+			league, err = api.GetMasterLeague(ctx, le.platform, types.RankTypeSolo)
+		}
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		entries = league.Entries
+		exhausted = true
+	default:
+		cursor.page++
+		league, err := api.GetLeagueExpEntries(ctx, le.platform, types.RankTypeSolo, tier, string(Ranks[cursor.divisionIndex]), cursor.page)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		entries = *league
+		if len(entries) == 0 {
+			// go to next division
+			cursor.divisionIndex++
+			cursor.page = 0
+			if cursor.divisionIndex >= len(Ranks) {
+				cursor.divisionIndex = 0
+				exhausted = true
+			}
+		}
+	}
+
+	seeded := 0
+	for _, entry := range entries {
+		// stop at safe point on shutdown
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		puuid := entry.Puuid
+		if puuid == "" {
+			// old ladder entries don't have puuid
+			summoner, err := api.GetSummonerBySummonerId(ctx, le.platform, entry.SummonerId)
+			if err != nil {
+				log.Warn(err)
+				continue
+			}
+			puuid = summoner.Puuid
+		}
+
+		_, exists, err := models.GetSummonerDAO_byPuuid(db.Root, puuid)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		now := time.Now()
+		frontier := &models.CrawlFrontierDAO{
+			Puuid:       puuid,
+			Platform:    le.platform,
+			Priority:    types.CrawlPriorityLadder,
+			Reason:      types.CrawlReasonLadder,
+			CreatedAt:   now,
+			NextCrawlAt: now,
+		}
+		if err := frontier.Enqueue(db.Root); err != nil {
+			log.Error(err)
+			return err
+		}
+		seeded++
+	}
+
+	if exhausted {
+		// ladder walked through, wait for ladder changes
+		exhaustedUntil := time.Now().Add(types.DataExplorerLadderExhaustedCooldown)
+		cursor.exhaustedUntil = &exhaustedUntil
+	}
+	log.Debugf("DataExplorer: seeded %d summoners from %s ladder", seeded, tier)
+	return nil
+}
@@ -1,6 +1,7 @@
 package statistics
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	uuid2 "github.com/google/uuid"
@@ -9,6 +10,7 @@ import (
 	"path"
 	"sort"
 	"strconv"
+	"sync"
 	"team.gg-server/core"
 	"team.gg-server/libs/db"
 	"team.gg-server/models/mixed"
@@ -109,6 +111,45 @@ type ChampionCounterStatistics struct {
 	IsValid bool `json:"isValid"`
 }
 
+type ChampionBuildOrderStatistics struct {
+	ItemIds []int `json:"itemIds"` // 완성 아이템 구매 순서
+
+	Count int `json:"count"`
+	Win   int `json:"win"`
+
+	WinRate  float64 `json:"winRate"`
+	PickRate float64 `json:"pickRate"`
+}
+
+type ChampionSkillOrderStatistics struct {
+	FirstSkills []int `json:"firstSkills"` // 1~3 레벨 스킬 (1: Q, 2: W, 3: E)
+	MaxOrder    []int `json:"maxOrder"`    // 스킬 마스터 순서
+
+	Count int `json:"count"`
+	Win   int `json:"win"`
+
+	WinRate  float64 `json:"winRate"`
+	PickRate float64 `json:"pickRate"`
+}
+
+type ChampionGoldCurveFrame struct {
+	Minute       int     `json:"minute"`
+	AvgTotalGold float64 `json:"avgTotalGold"`
+	AvgXp        float64 `json:"avgXp"`
+	AvgCs        float64 `json:"avgCs"`
+	Count        int     `json:"count"`
+}
+
+type ChampionLaneDiffStatistics struct {
+	AvgGoldDiffAt10 *float64 `json:"avgGoldDiffAt10"`
+	AvgGoldDiffAt15 *float64 `json:"avgGoldDiffAt15"`
+	AvgXpDiffAt10   *float64 `json:"avgXpDiffAt10"`
+	AvgXpDiffAt15   *float64 `json:"avgXpDiffAt15"`
+	AvgCsDiffAt10   *float64 `json:"avgCsDiffAt10"`
+	AvgCsDiffAt15   *float64 `json:"avgCsDiffAt15"`
+	Count           int      `json:"count"`
+}
+
 type ChampionDetailStatisticsMetaTree struct {
 	MajorMetaPicks []ChampionDetailStatisticsMeta    `json:"majorMetaPicks"`
 	MinorMetaPicks []ChampionDetailStatisticsMeta    `json:"minorMetaPick"`
@@ -116,6 +157,13 @@ type ChampionDetailStatisticsMetaTree struct {
 	PickCount      int                               `json:"pickCount"`
 	WinCount       int                               `json:"winCount"`
 	CounterMap     map[int]ChampionCounterStatistics `json:"counterMap"`
+	BuildOrders    []ChampionBuildOrderStatistics    `json:"buildOrders"` // from match timeline
+	GoldCurve      []ChampionGoldCurveFrame          `json:"goldCurve"`   // from match timeline
+
+	MostPickedSkillOrders     []ChampionSkillOrderStatistics `json:"mostPickedSkillOrders"`     // from match timeline
+	HighestWinRateSkillOrders []ChampionSkillOrderStatistics `json:"highestWinRateSkillOrders"` // from match timeline
+
+	LaneDiff *ChampionLaneDiffStatistics `json:"laneDiff"` // vs lane opponent, from match timeline
 }
 
 type ChampionDetailStatisticsPositionMetaTree struct {
@@ -166,13 +214,15 @@ type ChampionDetailStatistics struct {
 }
 
 type ChampionDetailStatisticsRepository struct {
-	Cache *ChampionDetailStatistics
+	Cache  *ChampionDetailStatistics
+	worker *service.Worker
 }
 
 func NewChampionDetailStatisticsRepository() *ChampionDetailStatisticsRepository {
 	cdsr := &ChampionDetailStatisticsRepository{
 		Cache: nil,
 	}
+	cdsr.worker = service.NewWorker(cdsr.key())
 	_, _ = cdsr.Load()
 	return cdsr
 }
@@ -188,16 +238,32 @@ func (cdsr *ChampionDetailStatisticsRepository) Period() time.Duration {
 	return 24 * time.Hour
 }
 
-func (cdsr *ChampionDetailStatisticsRepository) Loop() {
+// Loop recollects champion detail statistics (builds, skill orders, counters, ...) of recent game versions once a day.
+// heavy queries of collection in progress are cancelled when context is done.
+func (cdsr *ChampionDetailStatisticsRepository) Loop(ctx context.Context, waitGroup *sync.WaitGroup) {
+	defer waitGroup.Done()
 	for {
-		if _, err := cdsr.Collect(); err != nil {
+		if err := cdsr.worker.Run(func() (int, error) {
+			return cdsr.collectJob(ctx)
+		}); err != nil {
 			log.Error(err)
 		}
-		time.Sleep(cdsr.Period())
+		if !cdsr.worker.Wait(ctx, cdsr.Period()) {
+			log.Infof("%s loop stopped.", cdsr.key())
+			return
+		}
+	}
+}
+
+func (cdsr *ChampionDetailStatisticsRepository) collectJob(ctx context.Context) (int, error) {
+	result, err := cdsr.Collect(ctx)
+	if err != nil {
+		return 0, err
 	}
+	return len(result.Data), nil
 }
 
-func (cdsr *ChampionDetailStatisticsRepository) Collect() (*ChampionDetailStatistics, error) {
+func (cdsr *ChampionDetailStatisticsRepository) Collect(ctx context.Context) (*ChampionDetailStatistics, error) {
 	log.Debugf("collecting %s...", cdsr.key())
 	timer := util.NewTimerWithName("ChampionDetailStatisticsRepository")
 	timer.Start()
@@ -213,7 +279,7 @@ func (cdsr *ChampionDetailStatisticsRepository) Collect() (*ChampionDetailStatis
 
 	// collect data
 	championDetailStatisticsMXDAOmap := make(map[int]statistics_models.ChampionDetailStatisticMXDAO)
-	championDetailStatisticMXDAOs, err := statistics_models.GetChampionDetailStatisticMXDAOs(StatisticsDB, recentMatchGameVersions)
+	championDetailStatisticMXDAOs, err := statistics_models.GetChampionDetailStatisticMXDAOs(ctx, StatisticsDB, recentMatchGameVersions)
 	if err != nil {
 		log.Error(err)
 		return nil, err
@@ -226,7 +292,7 @@ func (cdsr *ChampionDetailStatisticsRepository) Collect() (*ChampionDetailStatis
 
 	// collect champion pick count by team position
 	championPositionStatisticsMXDAOmap := make(map[int]map[string]ChampionPositionStatistics)
-	championPositionStatisticsMXDAOs, err := statistics_models.GetChampionPositionStatisticsMXDAOs(StatisticsDB, recentMatchGameVersions)
+	championPositionStatisticsMXDAOs, err := statistics_models.GetChampionPositionStatisticsMXDAOs(ctx, StatisticsDB, recentMatchGameVersions)
 	if err != nil {
 		log.Error(err)
 		return nil, err
@@ -251,7 +317,7 @@ func (cdsr *ChampionDetailStatisticsRepository) Collect() (*ChampionDetailStatis
 		}
 	}
 
-	if err := statistics_models.CreateTemporaryTables(StatisticsDB, recentMatchGameVersions); err != nil {
+	if err := statistics_models.CreateTemporaryTables(ctx, StatisticsDB, recentMatchGameVersions); err != nil {
 		log.Error(err)
 		return nil, err
 	}
@@ -264,7 +330,7 @@ func (cdsr *ChampionDetailStatisticsRepository) Collect() (*ChampionDetailStatis
 
 	// collect meta
 	championDetailStatisticsMetaMap := make(map[int][]statistics_models.ChampionDetailStatisticsMetaMXDAO)
-	championDetailStatisticsMetaMXDAOs, err := statistics_models.GetChampionDetailStatisticsMetaMXDAOs(StatisticsDB)
+	championDetailStatisticsMetaMXDAOs, err := statistics_models.GetChampionDetailStatisticsMetaMXDAOs(ctx, StatisticsDB)
 	if err != nil {
 		log.Error(err)
 		return nil, err
@@ -281,7 +347,7 @@ func (cdsr *ChampionDetailStatisticsRepository) Collect() (*ChampionDetailStatis
 
 	// collect counter data
 	championCounterStatisticsMap := make(map[int][]statistics_models.ChampionCounterStatisticsMXDAO) // key: championId
-	championCounterStatisticsMXDAOs, err := statistics_models.GetChampionCounterStatisticsMXDAOs(StatisticsDB)
+	championCounterStatisticsMXDAOs, err := statistics_models.GetChampionCounterStatisticsMXDAOs(ctx, StatisticsDB)
 	if err != nil {
 		log.Error(err)
 		return nil, err
@@ -296,6 +362,68 @@ func (cdsr *ChampionDetailStatisticsRepository) Collect() (*ChampionDetailStatis
 	log.Debugf("championCounterStatisticsMXDAOs fetch complete: %d, size: %s",
 		len(championCounterStatisticsMXDAOs), util.MemorySizeOfArray(championCounterStatisticsMXDAOs))
 
+	// collect build orders & gold curves (from match timeline)
+	// key: championId -> teamPosition
+	championBuildOrderMap := make(map[int]map[string][]statistics_models.ChampionBuildOrderStatisticsMXDAO)
+	championBuildOrderMXDAOs, err := statistics_models.GetChampionBuildOrderStatisticsMXDAOs(ctx, StatisticsDB, recentMatchGameVersions)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	for _, buildOrder := range championBuildOrderMXDAOs {
+		if _, exists := championBuildOrderMap[buildOrder.ChampionId]; !exists {
+			championBuildOrderMap[buildOrder.ChampionId] = make(map[string][]statistics_models.ChampionBuildOrderStatisticsMXDAO)
+		}
+		championBuildOrderMap[buildOrder.ChampionId][buildOrder.TeamPosition] = append(championBuildOrderMap[buildOrder.ChampionId][buildOrder.TeamPosition], buildOrder)
+	}
+	log.Debugf("championBuildOrderMXDAOs fetch complete: %d, size: %s",
+		len(championBuildOrderMXDAOs), util.MemorySizeOfArray(championBuildOrderMXDAOs))
+
+	championGoldCurveMap := make(map[int]map[string][]statistics_models.ChampionGoldCurveStatisticsMXDAO)
+	championGoldCurveMXDAOs, err := statistics_models.GetChampionGoldCurveStatisticsMXDAOs(ctx, StatisticsDB, recentMatchGameVersions, goldCurveMaxMinute)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	for _, frame := range championGoldCurveMXDAOs {
+		if _, exists := championGoldCurveMap[frame.ChampionId]; !exists {
+			championGoldCurveMap[frame.ChampionId] = make(map[string][]statistics_models.ChampionGoldCurveStatisticsMXDAO)
+		}
+		championGoldCurveMap[frame.ChampionId][frame.TeamPosition] = append(championGoldCurveMap[frame.ChampionId][frame.TeamPosition], frame)
+	}
+	log.Debugf("championGoldCurveMXDAOs fetch complete: %d, size: %s",
+		len(championGoldCurveMXDAOs), util.MemorySizeOfArray(championGoldCurveMXDAOs))
+
+	championSkillSequenceMap := make(map[int]map[string][]statistics_models.ChampionSkillSequenceStatisticsMXDAO)
+	championSkillSequenceMXDAOs, err := statistics_models.GetChampionSkillSequenceStatisticsMXDAOs(ctx, StatisticsDB, recentMatchGameVersions)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	for _, skillSequence := range championSkillSequenceMXDAOs {
+		if _, exists := championSkillSequenceMap[skillSequence.ChampionId]; !exists {
+			championSkillSequenceMap[skillSequence.ChampionId] = make(map[string][]statistics_models.ChampionSkillSequenceStatisticsMXDAO)
+		}
+		championSkillSequenceMap[skillSequence.ChampionId][skillSequence.TeamPosition] = append(championSkillSequenceMap[skillSequence.ChampionId][skillSequence.TeamPosition], skillSequence)
+	}
+	log.Debugf("championSkillSequenceMXDAOs fetch complete: %d, size: %s",
+		len(championSkillSequenceMXDAOs), util.MemorySizeOfArray(championSkillSequenceMXDAOs))
+
+	championLaneDiffMap := make(map[int]map[string]statistics_models.ChampionLaneDiffStatisticsMXDAO)
+	championLaneDiffMXDAOs, err := statistics_models.GetChampionLaneDiffStatisticsMXDAOs(ctx, StatisticsDB, recentMatchGameVersions)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	for _, laneDiff := range championLaneDiffMXDAOs {
+		if _, exists := championLaneDiffMap[laneDiff.ChampionId]; !exists {
+			championLaneDiffMap[laneDiff.ChampionId] = make(map[string]statistics_models.ChampionLaneDiffStatisticsMXDAO)
+		}
+		championLaneDiffMap[laneDiff.ChampionId][laneDiff.TeamPosition] = laneDiff
+	}
+	log.Debugf("championLaneDiffMXDAOs fetch complete: %d, size: %s",
+		len(championLaneDiffMXDAOs), util.MemorySizeOfArray(championLaneDiffMXDAOs))
+
 	stats := make(map[int]ChampionDetailStatisticsItem)
 	for key, champion := range service.Champions {
 		championId, err := strconv.Atoi(key)
@@ -327,6 +455,36 @@ func (cdsr *ChampionDetailStatisticsRepository) Collect() (*ChampionDetailStatis
 			log.Error(err)
 			return nil, err
 		}
+		for teamPosition, buildOrders := range championBuildOrderMap[championId] {
+			if positionMetaTree := metaTree.byPosition(teamPosition); positionMetaTree != nil {
+				positionMetaTree.BuildOrders = getBuildOrderStatistics(buildOrders)
+			}
+		}
+		for teamPosition, frames := range championGoldCurveMap[championId] {
+			if positionMetaTree := metaTree.byPosition(teamPosition); positionMetaTree != nil {
+				positionMetaTree.GoldCurve = getGoldCurveFrames(frames)
+			}
+		}
+		for teamPosition, skillSequences := range championSkillSequenceMap[championId] {
+			if positionMetaTree := metaTree.byPosition(teamPosition); positionMetaTree != nil {
+				mostPicked, highestWinRate := getSkillOrderStatistics(skillSequences)
+				positionMetaTree.MostPickedSkillOrders = mostPicked
+				positionMetaTree.HighestWinRateSkillOrders = highestWinRate
+			}
+		}
+		for teamPosition, laneDiff := range championLaneDiffMap[championId] {
+			if positionMetaTree := metaTree.byPosition(teamPosition); positionMetaTree != nil {
+				positionMetaTree.LaneDiff = &ChampionLaneDiffStatistics{
+					AvgGoldDiffAt10: laneDiff.AvgGoldDiffAt10,
+					AvgGoldDiffAt15: laneDiff.AvgGoldDiffAt15,
+					AvgXpDiffAt10:   laneDiff.AvgXpDiffAt10,
+					AvgXpDiffAt15:   laneDiff.AvgXpDiffAt15,
+					AvgCsDiffAt10:   laneDiff.AvgCsDiffAt10,
+					AvgCsDiffAt15:   laneDiff.AvgCsDiffAt15,
+					Count:           laneDiff.Total,
+				}
+			}
+		}
 		//// get champion detail meta statistics
 		//metaTree, err := cdsr.collectEachChampionMetas(championId)
 		//if err != nil {
@@ -825,6 +983,11 @@ func (cdsr *ChampionDetailStatisticsRepository) collectEachChampionMetas(
 			PickCount:      pickCount,
 			WinCount:       winCount,
 			CounterMap:     counterMap,
+			BuildOrders:    make([]ChampionBuildOrderStatistics, 0),
+			GoldCurve:      make([]ChampionGoldCurveFrame, 0),
+
+			MostPickedSkillOrders:     make([]ChampionSkillOrderStatistics, 0),
+			HighestWinRateSkillOrders: make([]ChampionSkillOrderStatistics, 0),
 		}
 		for _, metaPick := range majorMetaPicks {
 			meta, err := metaPick.toRealMeta()
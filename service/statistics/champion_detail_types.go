@@ -4,6 +4,7 @@ import (
 	"fmt"
 	uuid2 "github.com/google/uuid"
 	"team.gg-server/service"
+	"team.gg-server/types"
 )
 
 type MetaPick struct {
@@ -129,3 +130,20 @@ func (mg *MetaGroup) getTotalPickCount() int {
 	}
 	return totalPickCount
 }
+
+func (t *ChampionDetailStatisticsPositionMetaTree) byPosition(teamPosition string) *ChampionDetailStatisticsMetaTree {
+	switch teamPosition {
+	case types.TeamPositionTop:
+		return t.Top
+	case types.TeamPositionJungle:
+		return t.Jungle
+	case types.TeamPositionMid:
+		return t.Mid
+	case types.TeamPositionAdc:
+		return t.Adc
+	case types.TeamPositionSupport:
+		return t.Support
+	default:
+		return nil
+	}
+}
@@ -5,6 +5,7 @@ import (
 	log "github.com/shyunku-libraries/go-logger"
 	"sort"
 	"strconv"
+	"team.gg-server/models/mixed/statistics_models"
 	"team.gg-server/service"
 	"team.gg-server/types"
 )
@@ -281,3 +282,209 @@ func getSlotsFromStyle(primaryStyleId, subStyleId int) ([]PerkSlot, []PerkSlot,
 
 	return mainSlots, subSlots, statSlots, nil
 }
+
+const (
+	buildOrderMaxCount = 5
+	goldCurveMaxMinute = 30
+)
+
+// getBuildOrderStatistics returns most picked build orders (desc)
+func getBuildOrderStatistics(buildOrderMXDAOs []statistics_models.ChampionBuildOrderStatisticsMXDAO) []ChampionBuildOrderStatistics {
+	totalCount := 0
+	for _, buildOrder := range buildOrderMXDAOs {
+		totalCount += buildOrder.Total
+	}
+
+	sort.SliceStable(buildOrderMXDAOs, func(i, j int) bool {
+		if buildOrderMXDAOs[i].Total == buildOrderMXDAOs[j].Total {
+			return buildOrderMXDAOs[i].Win > buildOrderMXDAOs[j].Win
+		}
+		return buildOrderMXDAOs[i].Total > buildOrderMXDAOs[j].Total
+	})
+
+	buildOrders := make([]ChampionBuildOrderStatistics, 0)
+	for _, buildOrder := range buildOrderMXDAOs {
+		if len(buildOrders) >= buildOrderMaxCount {
+			break
+		}
+		if buildOrder.Total == 0 {
+			continue
+		}
+		buildOrders = append(buildOrders, ChampionBuildOrderStatistics{
+			ItemIds:  []int{buildOrder.Item0Id, buildOrder.Item1Id, buildOrder.Item2Id},
+			Count:    buildOrder.Total,
+			Win:      buildOrder.Win,
+			WinRate:  float64(buildOrder.Win) / float64(buildOrder.Total),
+			PickRate: float64(buildOrder.Total) / float64(totalCount),
+		})
+	}
+	return buildOrders
+}
+
+// getGoldCurveFrames returns gold curve sorted by minute
+func getGoldCurveFrames(goldCurveMXDAOs []statistics_models.ChampionGoldCurveStatisticsMXDAO) []ChampionGoldCurveFrame {
+	frames := make([]ChampionGoldCurveFrame, 0, len(goldCurveMXDAOs))
+	for _, frame := range goldCurveMXDAOs {
+		frames = append(frames, ChampionGoldCurveFrame{
+			Minute:       frame.Minute,
+			AvgTotalGold: frame.AvgTotalGold,
+			AvgXp:        frame.AvgXp,
+			AvgCs:        frame.AvgCs,
+			Count:        frame.Total,
+		})
+	}
+	sort.SliceStable(frames, func(i, j int) bool {
+		return frames[i].Minute < frames[j].Minute
+	})
+	return frames
+}
+
+const (
+	skillMaxLevel               = 5
+	skillOrderMaxCount          = 3
+	skillOrderMinPickRate       = 0.05 // 최고 승률 스킬 순서 후보 최소 픽률
+	skillOrderFirstSkillsLength = 3
+)
+
+type skillOrderKey struct {
+	firstSkills [skillOrderFirstSkillsLength]int
+	maxOrder    [3]int
+}
+
+// parseSkillSequence returns skills of first 3 levels & max order of basic skills (Q/W/E).
+// basic skill which reaches max level earlier comes first,
+// and skills not maxed yet are ordered by their points (desc) & first level up.
+func parseSkillSequence(sequence string) (skillOrderKey, bool) {
+	key := skillOrderKey{}
+	slots := make([]int, 0, len(sequence))
+	for _, ch := range sequence {
+		slot := int(ch - '0')
+		if slot < types.SkillSlotQ || slot > types.SkillSlotR {
+			return key, false
+		}
+		slots = append(slots, slot)
+	}
+	if len(slots) < skillOrderFirstSkillsLength {
+		return key, false
+	}
+	copy(key.firstSkills[:], slots[:skillOrderFirstSkillsLength])
+
+	points := make(map[int]int)
+	firstLevelUpAt := make(map[int]int)
+	maxedAt := make(map[int]int)
+	for i, slot := range slots {
+		if slot == types.SkillSlotR {
+			continue
+		}
+		points[slot]++
+		if _, exists := firstLevelUpAt[slot]; !exists {
+			firstLevelUpAt[slot] = i
+		}
+		if points[slot] == skillMaxLevel {
+			maxedAt[slot] = i
+		}
+	}
+	// not enough levels to decide max order
+	if len(maxedAt) == 0 {
+		return key, false
+	}
+
+	maxOrder := []int{types.SkillSlotQ, types.SkillSlotW, types.SkillSlotE}
+	sort.SliceStable(maxOrder, func(i, j int) bool {
+		a, b := maxOrder[i], maxOrder[j]
+		aMaxedAt, aMaxed := maxedAt[a]
+		bMaxedAt, bMaxed := maxedAt[b]
+		if aMaxed && bMaxed {
+			return aMaxedAt < bMaxedAt
+		}
+		if aMaxed != bMaxed {
+			return aMaxed
+		}
+		if points[a] != points[b] {
+			return points[a] > points[b]
+		}
+		aFirstAt, aExists := firstLevelUpAt[a]
+		if !aExists {
+			aFirstAt = len(slots)
+		}
+		bFirstAt, bExists := firstLevelUpAt[b]
+		if !bExists {
+			bFirstAt = len(slots)
+		}
+		return aFirstAt < bFirstAt
+	})
+	copy(key.maxOrder[:], maxOrder)
+
+	return key, true
+}
+
+// getSkillOrderStatistics returns most picked skill orders & highest win rate skill orders
+func getSkillOrderStatistics(skillSequenceMXDAOs []statistics_models.ChampionSkillSequenceStatisticsMXDAO) ([]ChampionSkillOrderStatistics, []ChampionSkillOrderStatistics) {
+	totalCount := 0
+	skillOrderMap := make(map[skillOrderKey]*ChampionSkillOrderStatistics)
+	for _, skillSequence := range skillSequenceMXDAOs {
+		key, ok := parseSkillSequence(skillSequence.SkillSequence)
+		if !ok {
+			continue
+		}
+		skillOrder, exists := skillOrderMap[key]
+		if !exists {
+			skillOrder = &ChampionSkillOrderStatistics{
+				FirstSkills: append([]int{}, key.firstSkills[:]...),
+				MaxOrder:    append([]int{}, key.maxOrder[:]...),
+			}
+			skillOrderMap[key] = skillOrder
+		}
+		skillOrder.Count += skillSequence.Total
+		skillOrder.Win += skillSequence.Win
+		totalCount += skillSequence.Total
+	}
+
+	skillOrders := make([]ChampionSkillOrderStatistics, 0, len(skillOrderMap))
+	for _, skillOrder := range skillOrderMap {
+		if skillOrder.Count == 0 {
+			continue
+		}
+		skillOrder.WinRate = float64(skillOrder.Win) / float64(skillOrder.Count)
+		skillOrder.PickRate = float64(skillOrder.Count) / float64(totalCount)
+		skillOrders = append(skillOrders, *skillOrder)
+	}
+
+	// most picked
+	sort.SliceStable(skillOrders, func(i, j int) bool {
+		if skillOrders[i].Count == skillOrders[j].Count {
+			return skillOrders[i].Win > skillOrders[j].Win
+		}
+		return skillOrders[i].Count > skillOrders[j].Count
+	})
+	mostPicked := make([]ChampionSkillOrderStatistics, 0)
+	for _, skillOrder := range skillOrders {
+		if len(mostPicked) >= skillOrderMaxCount {
+			break
+		}
+		mostPicked = append(mostPicked, skillOrder)
+	}
+
+	// highest win rate (among skill orders picked enough)
+	candidates := make([]ChampionSkillOrderStatistics, 0)
+	for _, skillOrder := range skillOrders {
+		if skillOrder.PickRate >= skillOrderMinPickRate {
+			candidates = append(candidates, skillOrder)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].WinRate == candidates[j].WinRate {
+			return candidates[i].Count > candidates[j].Count
+		}
+		return candidates[i].WinRate > candidates[j].WinRate
+	})
+	highestWinRate := make([]ChampionSkillOrderStatistics, 0)
+	for _, skillOrder := range candidates {
+		if len(highestWinRate) >= skillOrderMaxCount {
+			break
+		}
+		highestWinRate = append(highestWinRate, skillOrder)
+	}
+
+	return mostPicked, highestWinRate
+}
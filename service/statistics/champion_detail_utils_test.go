@@ -1,2 +1,72 @@
 package statistics
 
+import "testing"
+
+func TestParseSkillSequence(t *testing.T) {
+	tests := []struct {
+		name        string
+		sequence    string
+		ok          bool
+		firstSkills [3]int
+		maxOrder    [3]int
+	}{
+		{name: "empty timeline", sequence: "", ok: false},
+		{name: "too short", sequence: "12", ok: false},
+		{name: "invalid slot", sequence: "1231151112", ok: false},
+		{name: "not a digit", sequence: "123Q", ok: false},
+		{name: "nothing maxed", sequence: "1231141", ok: false},
+		{
+			name:        "all maxed",
+			sequence:    "123114112242233433",
+			ok:          true,
+			firstSkills: [3]int{1, 2, 3},
+			maxOrder:    [3]int{1, 2, 3},
+		},
+		{
+			name:        "ultimate is not maxed",
+			sequence:    "1234444411111",
+			ok:          true,
+			firstSkills: [3]int{1, 2, 3},
+			maxOrder:    [3]int{1, 2, 3},
+		},
+		{
+			name:        "not maxed ordered by points",
+			sequence:    "312141113",
+			ok:          true,
+			firstSkills: [3]int{3, 1, 2},
+			maxOrder:    [3]int{1, 3, 2},
+		},
+		{
+			name:        "not maxed tie ordered by first level up",
+			sequence:    "21314111",
+			ok:          true,
+			firstSkills: [3]int{2, 1, 3},
+			maxOrder:    [3]int{1, 2, 3},
+		},
+		{
+			name:        "never leveled skill comes last",
+			sequence:    "2224222",
+			ok:          true,
+			firstSkills: [3]int{2, 2, 2},
+			maxOrder:    [3]int{2, 1, 3},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			key, ok := parseSkillSequence(test.sequence)
+			if ok != test.ok {
+				t.Fatalf("ok = %v, want %v", ok, test.ok)
+			}
+			if !ok {
+				return
+			}
+			if key.firstSkills != test.firstSkills {
+				t.Errorf("first skills = %v, want %v", key.firstSkills, test.firstSkills)
+			}
+			if key.maxOrder != test.maxOrder {
+				t.Errorf("max order = %v, want %v", key.maxOrder, test.maxOrder)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package statistics
+
+import (
+	"context"
+	"encoding/json"
+	log "github.com/shyunku-libraries/go-logger"
+	"os"
+	"path"
+	"sync"
+	"team.gg-server/core"
+	"team.gg-server/models/mixed/statistics_models"
+	"team.gg-server/service"
+	"team.gg-server/types"
+	"team.gg-server/util"
+	"time"
+)
+
+/* ----------------------- Champion position share statistics_models ----------------------- */
+
+type ChampionPositionShareStatistics struct {
+	UpdatedAt time.Time                                      `json:"updatedAt"`
+	Counts    []statistics_models.ChampionPositionCountMXDAO `json:"counts"`
+}
+
+type ChampionPositionShareStatisticsRepository struct {
+	Cache  *ChampionPositionShareStatistics
+	worker *service.Worker
+}
+
+func NewChampionPositionShareStatisticsRepository() *ChampionPositionShareStatisticsRepository {
+	cpsr := &ChampionPositionShareStatisticsRepository{
+		Cache: nil,
+	}
+	cpsr.worker = service.NewWorker(cpsr.key())
+	_, _ = cpsr.Load()
+	return cpsr
+}
+
+func (cpsr *ChampionPositionShareStatisticsRepository) key() string {
+	return "champion_position_share_statistics"
+}
+
+func (cpsr *ChampionPositionShareStatisticsRepository) Period() time.Duration {
+	if core.DebugMode {
+		return 1 * time.Hour
+	}
+	return types.CustomGameChampionPoolShareCacheHours * time.Hour
+}
+
+// Loop recounts picks of every champion by position (used to estimate custom game champion pools)
+// every few hours, until context is done (queries in progress are cancelled).
+func (cpsr *ChampionPositionShareStatisticsRepository) Loop(ctx context.Context, waitGroup *sync.WaitGroup) {
+	defer waitGroup.Done()
+	for {
+		if err := cpsr.worker.Run(func() (int, error) {
+			return cpsr.collectJob(ctx)
+		}); err != nil {
+			log.Error(err)
+		}
+		if !cpsr.worker.Wait(ctx, cpsr.Period()) {
+			log.Infof("%s loop stopped.", cpsr.key())
+			return
+		}
+	}
+}
+
+func (cpsr *ChampionPositionShareStatisticsRepository) collectJob(ctx context.Context) (int, error) {
+	result, err := cpsr.Collect(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(result.Counts), nil
+}
+
+func (cpsr *ChampionPositionShareStatisticsRepository) Collect(ctx context.Context) (*ChampionPositionShareStatistics, error) {
+	log.Debugf("collecting %s...", cpsr.key())
+	timer := util.NewTimerWithName("ChampionPositionShareStatisticsRepository")
+	timer.Start()
+
+	// collect data
+	countMXDAOs, err := statistics_models.GetChampionPositionCountMXDAOs(ctx, StatisticsDB)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	cpsr.Cache = &ChampionPositionShareStatistics{
+		UpdatedAt: time.Now(),
+		Counts:    countMXDAOs,
+	}
+	service.SetChampionPositionShares(cpsr.Cache.Counts)
+
+	log.Debugf("%s data collected successfully in %s", cpsr.key(), timer.GetDurationString())
+	if err := cpsr.Save(); err != nil {
+		log.Warn(err)
+	}
+
+	return cpsr.Cache, nil
+}
+
+func (cpsr *ChampionPositionShareStatisticsRepository) Save() error {
+	if cpsr.Cache == nil {
+		log.Error("data is nil")
+		return nil
+	}
+
+	// save data
+	jsonData, err := json.Marshal(cpsr.Cache)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	// create directory if not exists
+	if err = os.MkdirAll(path.Join(util.GetProjectRootDirectory(), StatisticsDataPath), 0755); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	filePath := keyPath(cpsr.key())
+	err = os.WriteFile(filePath, jsonData, 0644)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	log.Debugf("%s data saved to %s successfully", cpsr.key(), filePath)
+	return nil
+}
+
+func (cpsr *ChampionPositionShareStatisticsRepository) Load() (*ChampionPositionShareStatistics, error) {
+	if cpsr.Cache != nil {
+		return cpsr.Cache, nil
+	}
+
+	// if there is no data, collect and save
+	filePath := keyPath(cpsr.key())
+	_, err := os.Stat(filePath)
+	if err != nil {
+		log.Error(err)
+		return nil, nil
+	}
+
+	// read file
+	jsonData, err := os.ReadFile(filePath)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	// unmarshal data
+	err = json.Unmarshal(jsonData, &cpsr.Cache)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	// publish saved shares until next collection
+	service.SetChampionPositionShares(cpsr.Cache.Counts)
+	return cpsr.Cache, nil
+}
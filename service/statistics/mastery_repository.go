@@ -1,12 +1,14 @@
 package statistics
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	log "github.com/shyunku-libraries/go-logger"
 	"os"
 	"path"
 	"strconv"
+	"sync"
 	"team.gg-server/core"
 	"team.gg-server/models/mixed/statistics_models"
 	"team.gg-server/service"
@@ -45,13 +47,15 @@ type MasteryStatistics struct {
 }
 
 type MasteryStatisticsRepository struct {
-	Cache *MasteryStatistics
+	Cache  *MasteryStatistics
+	worker *service.Worker
 }
 
 func NewMasteryStatisticsRepository() *MasteryStatisticsRepository {
 	msr := &MasteryStatisticsRepository{
 		Cache: nil,
 	}
+	msr.worker = service.NewWorker(msr.key())
 	_, _ = msr.Load()
 	return msr
 }
@@ -67,28 +71,44 @@ func (msr *MasteryStatisticsRepository) Period() time.Duration {
 	return 12 * time.Hour
 }
 
-func (msr *MasteryStatisticsRepository) Loop() {
+// Loop recollects mastery statistics of every champion and its top rankers every 12 hours,
+// until context is done (queries in progress are cancelled).
+func (msr *MasteryStatisticsRepository) Loop(ctx context.Context, waitGroup *sync.WaitGroup) {
+	defer waitGroup.Done()
 	for {
-		if _, err := msr.Collect(); err != nil {
+		if err := msr.worker.Run(func() (int, error) {
+			return msr.collectJob(ctx)
+		}); err != nil {
 			log.Error(err)
 		}
-		time.Sleep(msr.Period())
+		if !msr.worker.Wait(ctx, msr.Period()) {
+			log.Infof("%s loop stopped.", msr.key())
+			return
+		}
+	}
+}
+
+func (msr *MasteryStatisticsRepository) collectJob(ctx context.Context) (int, error) {
+	result, err := msr.Collect(ctx)
+	if err != nil {
+		return 0, err
 	}
+	return len(result.MasteryGroups), nil
 }
 
-func (msr *MasteryStatisticsRepository) Collect() (*MasteryStatistics, error) {
+func (msr *MasteryStatisticsRepository) Collect(ctx context.Context) (*MasteryStatistics, error) {
 	log.Debugf("collecting %s...", msr.key())
 	timer := util.NewTimerWithName("MasteryStatisticsRepository")
 	timer.Start()
 
 	// collect data
-	masteryMXDAOs, err := statistics_models.GetMasteryStatisticsMXDAOs(StatisticsDB)
+	masteryMXDAOs, err := statistics_models.GetMasteryStatisticsMXDAOs(ctx, StatisticsDB)
 	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
 
-	masteryTopRankersMXDAO, err := statistics_models.GetMasteryStatisticsTopRankersMXDAOs(StatisticsDB, 30)
+	masteryTopRankersMXDAO, err := statistics_models.GetMasteryStatisticsTopRankersMXDAOs(ctx, StatisticsDB, 30)
 	if err != nil {
 		log.Error(err)
 		return nil, err
@@ -1,7 +1,9 @@
 package statistics
 
 import (
+	"context"
 	"path"
+	"sync"
 	"team.gg-server/libs/db"
 	"team.gg-server/util"
 	"time"
@@ -10,17 +12,18 @@ import (
 const StatisticsDataPath = "datafiles/statistics"
 
 var (
-	StatisticsDB                 *db.Database                        = nil
-	ChampionDetailStatisticsRepo *ChampionDetailStatisticsRepository = nil
-	TierStatisticsRepo           *TierStatisticsRepository           = nil
-	MasteryStatisticsRepo        *MasteryStatisticsRepository        = nil
+	StatisticsDB                        *db.Database                               = nil
+	ChampionDetailStatisticsRepo        *ChampionDetailStatisticsRepository        = nil
+	TierStatisticsRepo                  *TierStatisticsRepository                  = nil
+	MasteryStatisticsRepo               *MasteryStatisticsRepository               = nil
+	ChampionPositionShareStatisticsRepo *ChampionPositionShareStatisticsRepository = nil
 )
 
 type Statistics[T any] interface {
 	key() string
 	Period() time.Duration
-	Loop()
-	Collect() (*T, error)
+	Loop(ctx context.Context, waitGroup *sync.WaitGroup)
+	Collect(ctx context.Context) (*T, error)
 	Save() error
 	Load() (*T, error)
 }
@@ -29,6 +32,7 @@ func InitializeStatisticRepos() {
 	ChampionDetailStatisticsRepo = NewChampionDetailStatisticsRepository()
 	TierStatisticsRepo = NewTierStatisticsRepository()
 	MasteryStatisticsRepo = NewMasteryStatisticsRepository()
+	ChampionPositionShareStatisticsRepo = NewChampionPositionShareStatisticsRepository()
 }
 
 func keyPath(key string) string {
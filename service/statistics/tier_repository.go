@@ -1,10 +1,12 @@
 package statistics
 
 import (
+	"context"
 	"encoding/json"
 	log "github.com/shyunku-libraries/go-logger"
 	"os"
 	"path"
+	"sync"
 	"team.gg-server/core"
 	"team.gg-server/models/mixed/statistics_models"
 	"team.gg-server/service"
@@ -50,13 +52,15 @@ type TierStatistics struct {
 }
 
 type TierStatisticsRepository struct {
-	Cache *TierStatistics
+	Cache  *TierStatistics
+	worker *service.Worker
 }
 
 func NewTierStatisticsRepository() *TierStatisticsRepository {
 	tsr := &TierStatisticsRepository{
 		Cache: nil,
 	}
+	tsr.worker = service.NewWorker(tsr.key())
 	_, _ = tsr.Load()
 	return tsr
 }
@@ -72,28 +76,44 @@ func (tsr *TierStatisticsRepository) Period() time.Duration {
 	return 12 * time.Hour
 }
 
-func (tsr *TierStatisticsRepository) Loop() {
+// Loop recollects summoner count & top rankers of each tier every 12 hours,
+// until context is done (queries in progress are cancelled).
+func (tsr *TierStatisticsRepository) Loop(ctx context.Context, waitGroup *sync.WaitGroup) {
+	defer waitGroup.Done()
 	for {
-		if _, err := tsr.Collect(); err != nil {
+		if err := tsr.worker.Run(func() (int, error) {
+			return tsr.collectJob(ctx)
+		}); err != nil {
 			log.Error(err)
 		}
-		time.Sleep(tsr.Period())
+		if !tsr.worker.Wait(ctx, tsr.Period()) {
+			log.Infof("%s loop stopped.", tsr.key())
+			return
+		}
+	}
+}
+
+func (tsr *TierStatisticsRepository) collectJob(ctx context.Context) (int, error) {
+	result, err := tsr.Collect(ctx)
+	if err != nil {
+		return 0, err
 	}
+	return len(result.QueueGroups), nil
 }
 
-func (tsr *TierStatisticsRepository) Collect() (*TierStatistics, error) {
+func (tsr *TierStatisticsRepository) Collect(ctx context.Context) (*TierStatistics, error) {
 	log.Debugf("collecting %s...", tsr.key())
 	timer := util.NewTimerWithName("TierStatisticsRepository")
 	timer.Start()
 
 	// collect data
-	tierCountMXDAOs, err := statistics_models.GetTierStatisticsTierCountMXDAOs(StatisticsDB)
+	tierCountMXDAOs, err := statistics_models.GetTierStatisticsTierCountMXDAOs(ctx, StatisticsDB)
 	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
 
-	topRankersMXDAOs, err := statistics_models.GetTierStatisticsTopRankersMXDAOs(StatisticsDB, 30)
+	topRankersMXDAOs, err := statistics_models.GetTierStatisticsTopRankersMXDAOs(ctx, StatisticsDB, 30)
 	if err != nil {
 		log.Error(err)
 		return nil, err
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	log "github.com/shyunku-libraries/go-logger"
 	"os"
 	"os/signal"
@@ -10,6 +11,32 @@ import (
 
 type Finalizer func() error
 
+var (
+	// ServerContext is cancelled on shutdown, background jobs started by requests (optimization, draft, ...) run on it
+	ServerContext = context.Background()
+	// shutdown waits for background jobs registered to it (nil until server starts)
+	serverWaitGroup *sync.WaitGroup
+)
+
+// SetServerContext sets context & wait group of server, which background jobs started by requests use
+func SetServerContext(ctx context.Context, waitGroup *sync.WaitGroup) {
+	ServerContext = ctx
+	serverWaitGroup = waitGroup
+}
+
+// trackBackgroundJob registers job to wait group of server, shutdown waits until returned function is called
+func trackBackgroundJob() func() {
+	waitGroup := serverWaitGroup
+	if waitGroup == nil {
+		return func() {}
+	}
+	waitGroup.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(waitGroup.Done)
+	}
+}
+
 func PrepareFinalize(cancel func(), wg *sync.WaitGroup, finalizers []Finalizer) {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
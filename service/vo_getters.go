@@ -76,6 +76,12 @@ func GetSummonerExtraVO(puuid string, soloRank *SummonerRankVO) (*SummonerExtraV
 		ggScoreAvg = ggScoreSum / float64(validGGScores)
 	}
 
+	laneDiffMXDAO, err := mixed.GetSummonerLaneDiffMXDAO(db.Root, puuid, 30)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
 	// TODO :: add some extra fun things (statistics_models: tags)
 
 	var predictedRankVO *SummonerRankVO
@@ -103,6 +109,7 @@ func GetSummonerExtraVO(puuid string, soloRank *SummonerRankVO) (*SummonerExtraV
 		RecentAvgGGScore: ggScoreAvg,
 		PredictedMMR:     predictedMMR,
 		PredictedRank:    predictedRankVO,
+		LaneDiff:         SummonerLaneDiffMixer(*laneDiffMXDAO),
 	}, nil
 }
 
@@ -299,7 +306,7 @@ func getSummonerMatchSummaryVOs(puuid string, matchDAOs []models.MatchDAO) ([]Ma
 }
 
 func GetCustomGameConfigurationVOs(uid string) ([]CustomGameConfigurationSummaryVO, error) {
-	customGameConfigurationDAOs, err := models.GetCustomGameDAOs_byCreatorUid(db.Root, uid)
+	customGameConfigurationDAOs, err := models.GetCustomGameDAOs_byMemberUid(db.Root, uid)
 	if err != nil {
 		log.Error(err)
 		return nil, err
@@ -307,13 +314,52 @@ func GetCustomGameConfigurationVOs(uid string) ([]CustomGameConfigurationSummary
 
 	customGameConfigurationVOs := make([]CustomGameConfigurationSummaryVO, 0)
 	for _, customGameConfigurationDAO := range customGameConfigurationDAOs {
-		customGameConfigurationVOs = append(customGameConfigurationVOs, CustomGameConfigurationSummaryMixer(customGameConfigurationDAO))
+		role, _, err := GetCustomGameRole(db.Root, customGameConfigurationDAO, uid)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		customGameConfigurationVO := CustomGameConfigurationSummaryMixer(customGameConfigurationDAO)
+		customGameConfigurationVO.Role = role
+		customGameConfigurationVOs = append(customGameConfigurationVOs, customGameConfigurationVO)
 	}
 
 	return customGameConfigurationVOs, nil
 }
 
 func GetCustomGameCandidateVO(candidateDAO models.CustomGameCandidateDAO) (*CustomGameCandidateVO, error) {
+	recentCountsMap, err := getRecentChampionPositionCounts(db.Root, []string{candidateDAO.Puuid})
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	return getCustomGameCandidateVO(candidateDAO, recentCountsMap[candidateDAO.Puuid])
+}
+
+// getCustomGameCandidateVOs returns VOs of candidates (same order), recent picks of candidates are counted at once
+func getCustomGameCandidateVOs(db db.Context, candidateDAOs []models.CustomGameCandidateDAO) ([]CustomGameCandidateVO, error) {
+	puuids := make([]string, 0, len(candidateDAOs))
+	for _, candidateDAO := range candidateDAOs {
+		puuids = append(puuids, candidateDAO.Puuid)
+	}
+	recentCountsMap, err := getRecentChampionPositionCounts(db, puuids)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	candidateVOs := make([]CustomGameCandidateVO, 0, len(candidateDAOs))
+	for _, candidateDAO := range candidateDAOs {
+		candidateVO, err := getCustomGameCandidateVO(candidateDAO, recentCountsMap[candidateDAO.Puuid])
+		if err != nil {
+			return nil, err
+		}
+		candidateVOs = append(candidateVOs, *candidateVO)
+	}
+	return candidateVOs, nil
+}
+
+func getCustomGameCandidateVO(candidateDAO models.CustomGameCandidateDAO, recentCountDAOs []mixed.RecentChampionPositionCountMXDAO) (*CustomGameCandidateVO, error) {
 	summonerDao, exists, err := models.GetSummonerDAO_byPuuid(db.Root, candidateDAO.Puuid)
 	if err != nil {
 		log.Error(err)
@@ -388,13 +434,28 @@ func GetCustomGameCandidateVO(candidateDAO models.CustomGameCandidateDAO) (*Cust
 		masteryVOs = append(masteryVOs, SummonerMasteryMixer(*mastery))
 	}
 
+	inHouseRatingDAOs, err := models.GetCustomGameRatingDAOs_byCustomGameConfigId(db.Root, candidateDAO.CustomGameConfigId, candidateDAO.Puuid)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	inHouseRatingVOs := make([]CustomGameRatingVO, 0)
+	for _, inHouseRatingDAO := range inHouseRatingDAOs {
+		inHouseRatingVOs = append(inHouseRatingVOs, CustomGameRatingMixer(inHouseRatingDAO))
+	}
+
 	return &CustomGameCandidateVO{
-		Summary:       summonerVO,
-		SoloRank:      soloLeagueVO,
-		FlexRank:      flexLeagueVO,
-		CustomRank:    customRankVO,
-		PositionFavor: positionFavorVO,
-		Mastery:       masteryVOs,
+		Summary:        summonerVO,
+		SoloRank:       soloLeagueVO,
+		FlexRank:       flexLeagueVO,
+		CustomRank:     customRankVO,
+		PositionFavor:  positionFavorVO,
+		Mastery:        masteryVOs,
+		MustPlay:       candidateDAO.MustPlay,
+		BenchStreak:    candidateDAO.BenchStreak,
+		PlayedCount:    candidateDAO.PlayedCount,
+		InHouseRatings: inHouseRatingVOs,
+		ChampionPool:   GetCustomGameChampionPoolVO(masteryDAO, recentCountDAOs),
 	}, nil
 }
 
@@ -429,25 +490,31 @@ func GetCustomGameConfigurationVO(configurationId string) (*CustomGameConfigurat
 		log.Error(err)
 		return nil, err
 	}
-	for _, candidateDAO := range candidateDAOs {
+	summonerVOs, err := getCustomGameCandidateVOs(db.Root, candidateDAOs)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	for i, candidateDAO := range candidateDAOs {
 		colorCode, exists := colorLabels[candidateDAO.Puuid]
 		if !exists {
 			colorCode = 0
 		}
 
-		summonerVO, err := GetCustomGameCandidateVO(candidateDAO)
-		if err != nil {
-			log.Error(err)
-			return nil, err
-		}
+		summonerVO := summonerVOs[i]
 		candidateVOs = append(candidateVOs, CustomGameCandidateVO{
-			Summary:       summonerVO.Summary,
-			SoloRank:      summonerVO.SoloRank,
-			FlexRank:      summonerVO.FlexRank,
-			CustomRank:    summonerVO.CustomRank,
-			PositionFavor: summonerVO.PositionFavor,
-			Mastery:       summonerVO.Mastery,
-			ColorCode:     colorCode,
+			Summary:        summonerVO.Summary,
+			SoloRank:       summonerVO.SoloRank,
+			FlexRank:       summonerVO.FlexRank,
+			CustomRank:     summonerVO.CustomRank,
+			PositionFavor:  summonerVO.PositionFavor,
+			Mastery:        summonerVO.Mastery,
+			ColorCode:      colorCode,
+			MustPlay:       summonerVO.MustPlay,
+			BenchStreak:    summonerVO.BenchStreak,
+			PlayedCount:    summonerVO.PlayedCount,
+			InHouseRatings: summonerVO.InHouseRatings,
+			ChampionPool:   summonerVO.ChampionPool,
 		})
 	}
 
@@ -466,25 +533,19 @@ func GetCustomGameConfigurationVO(configurationId string) (*CustomGameConfigurat
 		}
 	}
 
+	// get constraints
+	constraintVOs, err := GetCustomGameConstraintVOs(db.Root, configurationId)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
 	customGameConfigurationVO := CustomGameConfigurationMixer(
 		*customGameConfigurationDAO,
 		candidateVOs,
+		constraintVOs,
 		team1ParticipantsVOs,
 		team2ParticipantsVOs,
 	)
 	return &customGameConfigurationVO, nil
 }
-
-func GetCustomGameConfigurationBalanceVO(customGameConfigId string) (*CustomGameConfigurationBalanceVO, error) {
-	customGameConfigDAO, exists, err := models.GetCustomGameDAO_byId(db.Root, customGameConfigId)
-	if err != nil {
-		log.Error(err)
-		return nil, err
-	}
-	if !exists {
-		return nil, fmt.Errorf("custom game config dao not found with id (%s)", customGameConfigId)
-	}
-
-	fairnessVO := CustomGameConfigurationFairnessMixer(*customGameConfigDAO)
-	return &fairnessVO, nil
-}
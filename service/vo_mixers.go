@@ -16,6 +16,7 @@ func SummonerSummaryMixer(d models.SummonerDAO) SummonerSummaryVO {
 		TagLine:       d.TagLine,
 		Name:          d.Name,
 		Puuid:         d.Puuid,
+		Platform:      d.Platform,
 		SummonerLevel: d.SummonerLevel,
 		LastUpdatedAt: d.LastUpdatedAt,
 	}
@@ -29,6 +30,18 @@ func SummonerRankingMixer(d mixed.SummonerRankingMXDAO) SummonerRankingVO {
 	}
 }
 
+func SummonerLaneDiffMixer(d mixed.SummonerLaneDiffMXDAO) SummonerLaneDiffVO {
+	return SummonerLaneDiffVO{
+		AvgGoldDiffAt10: d.AvgGoldDiffAt10,
+		AvgGoldDiffAt15: d.AvgGoldDiffAt15,
+		AvgXpDiffAt10:   d.AvgXpDiffAt10,
+		AvgXpDiffAt15:   d.AvgXpDiffAt15,
+		AvgCsDiffAt10:   d.AvgCsDiffAt10,
+		AvgCsDiffAt15:   d.AvgCsDiffAt15,
+		Count:           d.Count,
+	}
+}
+
 func SummonerRankMixer(d models.LeagueDAO) (*SummonerRankVO, error) {
 	ratingPoint, err := CalculateRatingPoint(d.Tier, d.Rank, d.LeaguePoints)
 	if err != nil {
@@ -231,6 +244,7 @@ func CustomGameConfigurationParticipantMixer(d models.CustomGameParticipantDAO)
 
 func CustomGameConfigurationMixer(d models.CustomGameConfigurationDAO,
 	candidates []CustomGameCandidateVO,
+	constraints []CustomGameConstraintVO,
 	team1, team2 []CustomGameParticipantVO) CustomGameConfigurationVO {
 	return CustomGameConfigurationVO{
 		Id:            d.Id,
@@ -238,14 +252,48 @@ func CustomGameConfigurationMixer(d models.CustomGameConfigurationDAO,
 		CreatorUid:    d.CreatorUid,
 		CreatedAt:     d.CreatedAt,
 		LastUpdatedAt: d.LastUpdatedAt,
+		IsPrivate:     d.IsPrivate,
+		Version:       d.Version,
+		FairnessModel: d.FairnessModel,
 		Balance:       CustomGameConfigurationFairnessMixer(d),
 		Candidates:    candidates,
+		Constraints:   constraints,
 		Team1:         team1,
 		Team2:         team2,
 		Weights:       CustomGameConfigurationWeightsMixer(d),
 	}
 }
 
+func CustomGameConstraintMixer(d models.CustomGameConstraintDAO) CustomGameConstraintVO {
+	return CustomGameConstraintVO{
+		Id:          d.Id,
+		Type:        d.Type,
+		Puuid:       d.Puuid,
+		TargetPuuid: d.TargetPuuid,
+		Team:        d.Team,
+		Position:    d.Position,
+	}
+}
+
+func CustomGameInviteMixer(d models.CustomGameInviteDAO) CustomGameInviteVO {
+	return CustomGameInviteVO{
+		Token:              d.Token,
+		CustomGameConfigId: d.CustomGameConfigId,
+		Role:               d.Role,
+		CreatedAt:          d.CreatedAt,
+		ExpiresAt:          d.ExpiresAt,
+	}
+}
+
+func CustomGameShareTokenMixer(d models.CustomGameShareTokenDAO) CustomGameShareTokenVO {
+	return CustomGameShareTokenVO{
+		Token:              d.Token,
+		CustomGameConfigId: d.CustomGameConfigId,
+		CreatedAt:          d.CreatedAt,
+		ExpiresAt:          d.ExpiresAt,
+	}
+}
+
 func CustomGameConfigurationWeightsMixer(d models.CustomGameConfigurationDAO) CustomGameConfigurationWeightsVO {
 	return CustomGameConfigurationWeightsVO{
 		LineFairness:     d.LineFairnessWeight,
@@ -256,6 +304,44 @@ func CustomGameConfigurationWeightsMixer(d models.CustomGameConfigurationDAO) Cu
 		MidInfluence:     d.MidInfluenceWeight,
 		AdcInfluence:     d.AdcInfluenceWeight,
 		SupportInfluence: d.SupportInfluenceWeight,
+		InHouseRating:    d.InHouseRatingWeight,
+		ChampionPool:     d.ChampionPoolWeight,
+	}
+}
+
+func CustomGameRatingMixer(d models.CustomGameRatingDAO) CustomGameRatingVO {
+	return CustomGameRatingVO{
+		Puuid:        d.Puuid,
+		Position:     d.Position,
+		Rating:       d.Rating,
+		Deviation:    d.Deviation,
+		GameCount:    d.GameCount,
+		WinCount:     d.WinCount,
+		LastPlayedAt: d.LastPlayedAt,
+	}
+}
+
+func CustomGameResultParticipantStatsMixer(d models.MatchParticipantDAO) CustomGameResultParticipantStatsVO {
+	return CustomGameResultParticipantStatsVO{
+		ChampionId:                  d.ChampionId,
+		ChampionName:                d.ChampionName,
+		Kills:                       d.Kills,
+		Deaths:                      d.Deaths,
+		Assists:                     d.Assists,
+		TotalDamageDealtToChampions: d.TotalDamageDealtToChampions,
+		GoldEarned:                  d.GoldEarned,
+		TotalMinionsKilled:          d.TotalMinionsKilled,
+		VisionScore:                 d.VisionScore,
+	}
+}
+
+func CustomGameResultParticipantMixer(d models.CustomGameResultParticipantDAO) CustomGameResultParticipantVO {
+	return CustomGameResultParticipantVO{
+		Puuid:        d.Puuid,
+		Position:     d.Position,
+		RatingPoint:  d.RatingPoint,
+		RatingBefore: d.RatingBefore,
+		RatingAfter:  d.RatingAfter,
 	}
 }
 
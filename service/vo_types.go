@@ -2,6 +2,7 @@ package service
 
 import (
 	"strconv"
+	"team.gg-server/types"
 	"time"
 )
 
@@ -13,6 +14,7 @@ type SummonerSummaryVO struct {
 	TagLine       string    `json:"tagLine"`
 	Name          string    `json:"name"`
 	Puuid         string    `json:"puuid"`
+	Platform      string    `json:"platform"`
 	SummonerLevel int64     `json:"summonerLevel"`
 	LastUpdatedAt time.Time `json:"lastUpdatedAt"`
 }
@@ -40,10 +42,22 @@ type SummonerMasteryVO struct {
 }
 
 type SummonerExtraVO struct {
-	Ranking          SummonerRankingVO `json:"ranking"`
-	RecentAvgGGScore float64           `json:"recentAvgGGScore"`
-	PredictedMMR     float64           `json:"predictedMMR"`
-	PredictedRank    *SummonerRankVO   `json:"predictedRank"`
+	Ranking          SummonerRankingVO  `json:"ranking"`
+	RecentAvgGGScore float64            `json:"recentAvgGGScore"`
+	PredictedMMR     float64            `json:"predictedMMR"`
+	PredictedRank    *SummonerRankVO    `json:"predictedRank"`
+	LaneDiff         SummonerLaneDiffVO `json:"laneDiff"`
+}
+
+// SummonerLaneDiffVO is average gold/xp/cs differential against lane opponent of recent matches
+type SummonerLaneDiffVO struct {
+	AvgGoldDiffAt10 *float64 `json:"avgGoldDiffAt10"`
+	AvgGoldDiffAt15 *float64 `json:"avgGoldDiffAt15"`
+	AvgXpDiffAt10   *float64 `json:"avgXpDiffAt10"`
+	AvgXpDiffAt15   *float64 `json:"avgXpDiffAt15"`
+	AvgCsDiffAt10   *float64 `json:"avgCsDiffAt10"`
+	AvgCsDiffAt15   *float64 `json:"avgCsDiffAt15"`
+	Count           int      `json:"count"`
 }
 
 type PerkVO struct {
@@ -382,6 +396,7 @@ type CustomGameConfigurationSummaryVO struct {
 	Name          string                           `json:"name"`
 	LastUpdatedAt time.Time                        `json:"lastUpdatedAt"`
 	Balance       CustomGameConfigurationBalanceVO `json:"balance"`
+	Role          string                           `json:"role"` // role of requesting user
 }
 
 type CustomGameCandidatePositionFavorVO struct {
@@ -400,6 +415,13 @@ type CustomGameCandidateVO struct {
 	PositionFavor CustomGameCandidatePositionFavorVO `json:"positionFavor"`
 	Mastery       []SummonerMasteryVO                `json:"mastery"`
 	ColorCode     int                                `json:"colorCode"`
+	MustPlay      bool                               `json:"mustPlay"`
+	BenchStreak   int                                `json:"benchStreak"`
+	PlayedCount   int                                `json:"playedCount"`
+	// in-house ratings in group of configuration (overall & each played position)
+	InHouseRatings []CustomGameRatingVO `json:"inHouseRatings"`
+	// champions playable on each position, from masteries & recent matches
+	ChampionPool CustomGameChampionPoolVO `json:"championPool"`
 }
 
 func (c *CustomGameCandidateVO) GetRepresentativeRank() *SummonerRankVO {
@@ -420,6 +442,32 @@ func (c *CustomGameCandidateVO) GetRepresentativeRatingPoint() int64 {
 	return representativeRank.RatingPoint
 }
 
+func (c *CustomGameCandidateVO) GetInHouseRating(position string) *CustomGameRatingVO {
+	for i := range c.InHouseRatings {
+		if c.InHouseRatings[i].Position == position {
+			return &c.InHouseRatings[i]
+		}
+	}
+	return nil
+}
+
+// GetBalanceRatingPoint returns representative rating point blended with in-house rating of position
+// (overall rating if not played on position) by weight (0: tier only, 1: in-house rating only)
+func (c *CustomGameCandidateVO) GetBalanceRatingPoint(position string, inHouseRatingWeight float64) float64 {
+	ratingPoint := float64(c.GetRepresentativeRatingPoint())
+	if inHouseRatingWeight <= 0 {
+		return ratingPoint
+	}
+	inHouseRating := c.GetInHouseRating(position)
+	if inHouseRating == nil {
+		inHouseRating = c.GetInHouseRating(types.CustomGameRatingPositionAll)
+	}
+	if inHouseRating == nil {
+		return ratingPoint
+	}
+	return (1-inHouseRatingWeight)*ratingPoint + inHouseRatingWeight*inHouseRating.Rating
+}
+
 type CustomGameTeamParticipantVO struct {
 	CustomGameCandidateVO
 	Team     int    `json:"team"`
@@ -438,6 +486,108 @@ type CustomGameConfigurationBalanceVO struct {
 	LineSatisfaction float64 `json:"lineSatisfaction"`
 }
 
+type CustomGameChampionPoolVO struct {
+	// false if summoner has neither masteries nor recent matches (pool is not penalized)
+	Known   bool                             `json:"known"`
+	Top     CustomGamePositionChampionPoolVO `json:"top"`
+	Jungle  CustomGamePositionChampionPoolVO `json:"jungle"`
+	Mid     CustomGamePositionChampionPoolVO `json:"mid"`
+	Adc     CustomGamePositionChampionPoolVO `json:"adc"`
+	Support CustomGamePositionChampionPoolVO `json:"support"`
+}
+
+func (p *CustomGameChampionPoolVO) byPosition(position string) *CustomGamePositionChampionPoolVO {
+	switch position {
+	case types.PositionTop:
+		return &p.Top
+	case types.PositionJungle:
+		return &p.Jungle
+	case types.PositionMid:
+		return &p.Mid
+	case types.PositionAdc:
+		return &p.Adc
+	case types.PositionSupport:
+		return &p.Support
+	default:
+		return nil
+	}
+}
+
+type CustomGamePositionChampionPoolVO struct {
+	// sum of proficiencies of champions in pool (roughly count of playable champions)
+	Depth     float64                    `json:"depth"`
+	Champions []CustomGamePoolChampionVO `json:"champions"` // most proficient first
+}
+
+type CustomGamePoolChampionVO struct {
+	ChampionId       int64   `json:"championId"`
+	ChampionName     *string `json:"championName"`
+	Proficiency      float64 `json:"proficiency"` // 0~1
+	ChampionPoints   int     `json:"championPoints"`
+	RecentMatchCount int     `json:"recentMatchCount"` // on this position
+}
+
+// CustomGamePositionFavorSuggestionVO is position favor inferred from recent matches of candidate
+type CustomGamePositionFavorSuggestionVO struct {
+	Puuid         string                             `json:"puuid"`
+	PositionFavor CustomGameCandidatePositionFavorVO `json:"positionFavor"`
+	MatchCount    int                                `json:"matchCount"`
+	Suggested     bool                               `json:"suggested"` // false if recent matches are not enough to suggest
+	Positions     []CustomGamePositionStatVO         `json:"positions"`
+}
+
+type CustomGamePositionStatVO struct {
+	Position   string  `json:"position"`
+	MatchCount int     `json:"matchCount"`
+	WinRate    float64 `json:"winRate"`
+	AvgGGScore float64 `json:"avgGGScore"`
+}
+
+// CustomGameBalanceDetailVO explains balance of current arrangement
+type CustomGameBalanceDetailVO struct {
+	CustomGameConfigurationBalanceVO
+	Team1WinProbability *float64                             `json:"team1WinProbability"` // estimated, nil if any team is empty
+	LaneDiffs           CustomGameLaneDiffVO                 `json:"laneDiffs"`
+	Unsatisfied         []CustomGameUnsatisfiedParticipantVO `json:"unsatisfied"` // most unsatisfied first
+	SwapSuggestions     []CustomGameSwapSuggestionVO         `json:"swapSuggestions"`
+	// penalty of lacking or shared champion pools (0~1), applied to fairness by champion pool weight
+	ChampionPoolPenalty float64 `json:"championPoolPenalty"`
+}
+
+// CustomGameLaneDiffVO is rating difference of team 1 over team 2 on each lane (positive: team 1 is better)
+type CustomGameLaneDiffVO struct {
+	Top    float64 `json:"top"`
+	Jungle float64 `json:"jungle"`
+	Mid    float64 `json:"mid"`
+	Bottom float64 `json:"bottom"` // adc & support weighted by influence
+}
+
+// CustomGameUnsatisfiedParticipantVO is participant placed on less favored position than the favorite one
+type CustomGameUnsatisfiedParticipantVO struct {
+	Puuid            string  `json:"puuid"`
+	Team             int     `json:"team"`
+	Position         string  `json:"position"`
+	Favor            int     `json:"favor"`
+	FavoritePosition string  `json:"favoritePosition"`
+	FavoriteFavor    int     `json:"favoriteFavor"`
+	SatisfactionLoss float64 `json:"satisfactionLoss"` // favor weight lost compared to favorite position
+}
+
+// CustomGameSwapSuggestionVO is swap of 2 participants' team & position improving fairness
+type CustomGameSwapSuggestionVO struct {
+	Puuid1       string                           `json:"puuid1"`
+	Puuid2       string                           `json:"puuid2"`
+	Balance      CustomGameConfigurationBalanceVO `json:"balance"` // balance after swap
+	FairnessGain float64                          `json:"fairnessGain"`
+}
+
+type CustomGameOptimizedConfigurationVO struct {
+	Balance CustomGameConfigurationBalanceVO `json:"balance"`
+	Team1   []CustomGameParticipantVO        `json:"team1"`
+	Team2   []CustomGameParticipantVO        `json:"team2"`
+	Bench   []string                         `json:"bench"` // puuids of candidates not playing
+}
+
 type CustomGameConfigurationWeightsVO struct {
 	LineFairness     float64 `json:"lineFairness"`
 	TierFairness     float64 `json:"tierFairness"`
@@ -448,6 +598,51 @@ type CustomGameConfigurationWeightsVO struct {
 	MidInfluence     float64 `json:"midInfluence"`
 	AdcInfluence     float64 `json:"adcInfluence"`
 	SupportInfluence float64 `json:"supportInfluence"`
+
+	InHouseRating float64 `json:"inHouseRating"` // blend of in-house rating into rating point
+	ChampionPool  float64 `json:"championPool"`  // penalty of lacking champion pool on positions
+}
+
+type CustomGameRatingVO struct {
+	Puuid        string     `json:"puuid"`
+	Position     string     `json:"position"`
+	Rating       float64    `json:"rating"`
+	Deviation    float64    `json:"deviation"`
+	GameCount    int        `json:"gameCount"`
+	WinCount     int        `json:"winCount"`
+	LastPlayedAt *time.Time `json:"lastPlayedAt"`
+}
+
+type CustomGameResultParticipantVO struct {
+	Puuid        string  `json:"puuid"`
+	Position     string  `json:"position"`
+	RatingPoint  float64 `json:"ratingPoint"`
+	RatingBefore float64 `json:"ratingBefore"`
+	RatingAfter  float64 `json:"ratingAfter"`
+	// stats from linked riot match (nil if not linked)
+	Stats *CustomGameResultParticipantStatsVO `json:"stats"`
+}
+
+type CustomGameResultParticipantStatsVO struct {
+	ChampionId                  int    `json:"championId"`
+	ChampionName                string `json:"championName"`
+	Kills                       int    `json:"kills"`
+	Deaths                      int    `json:"deaths"`
+	Assists                     int    `json:"assists"`
+	TotalDamageDealtToChampions int    `json:"totalDamageDealtToChampions"`
+	GoldEarned                  int    `json:"goldEarned"`
+	TotalMinionsKilled          int    `json:"totalMinionsKilled"`
+	VisionScore                 int    `json:"visionScore"`
+}
+
+type CustomGameResultVO struct {
+	Id                 string                          `json:"id"`
+	CustomGameConfigId *string                         `json:"customGameConfigId"`
+	Winner             int                             `json:"winner"`
+	RiotMatchId        *string                         `json:"riotMatchId"`
+	PlayedAt           time.Time                       `json:"playedAt"`
+	Team1              []CustomGameResultParticipantVO `json:"team1"`
+	Team2              []CustomGameResultParticipantVO `json:"team2"`
 }
 
 type CustomGameTeamPositionVO struct {
@@ -461,12 +656,82 @@ type CustomGameConfigurationVO struct {
 	CreatorUid    string                           `json:"creatorUid"`
 	CreatedAt     time.Time                        `json:"createdAt"`
 	LastUpdatedAt time.Time                        `json:"lastUpdatedAt"`
+	IsPrivate     bool                             `json:"isPrivate"`
+	Version       int                              `json:"version"` // should be sent on mutating requests
+	FairnessModel string                           `json:"fairnessModel"`
 	Balance       CustomGameConfigurationBalanceVO `json:"balance"`
 
 	Weights CustomGameConfigurationWeightsVO `json:"weights"`
 
-	Candidates []CustomGameCandidateVO `json:"candidates"`
+	Candidates  []CustomGameCandidateVO  `json:"candidates"`
+	Constraints []CustomGameConstraintVO `json:"constraints"`
 
 	Team1 []CustomGameParticipantVO `json:"team1"`
 	Team2 []CustomGameParticipantVO `json:"team2"`
 }
+
+type CustomGameConstraintVO struct {
+	Id          string  `json:"id"`
+	Type        string  `json:"type"`
+	Puuid       string  `json:"puuid"`
+	TargetPuuid *string `json:"targetPuuid"`
+	Team        *int    `json:"team"`
+	Position    *string `json:"position"`
+}
+
+type CustomGameMemberVO struct {
+	Uid      string    `json:"uid"`
+	UserId   string    `json:"userId"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+type CustomGameInviteVO struct {
+	Token              string    `json:"token"`
+	CustomGameConfigId string    `json:"customGameConfigId"`
+	Role               string    `json:"role"`
+	CreatedAt          time.Time `json:"createdAt"`
+	ExpiresAt          time.Time `json:"expiresAt"`
+}
+
+type CustomGameShareTokenVO struct {
+	Token              string     `json:"token"`
+	CustomGameConfigId string     `json:"customGameConfigId"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	ExpiresAt          *time.Time `json:"expiresAt"`
+}
+
+// CustomGameSharedParticipantVO is participant shown on shared view, without organizing details
+type CustomGameSharedParticipantVO struct {
+	Position string            `json:"position"`
+	Summary  SummonerSummaryVO `json:"summary"`
+	Rank     *SummonerRankVO   `json:"rank"` // representative rank
+}
+
+// CustomGameSharedViewVO is read-only view of arrangement exposed by share token
+type CustomGameSharedViewVO struct {
+	Name          string                           `json:"name"`
+	LastUpdatedAt time.Time                        `json:"lastUpdatedAt"`
+	Balance       CustomGameConfigurationBalanceVO `json:"balance"`
+	Team1         []CustomGameSharedParticipantVO  `json:"team1"`
+	Team2         []CustomGameSharedParticipantVO  `json:"team2"`
+}
+
+// CustomGameArrangementSnapshotVO is state of configuration tracked by revisions
+type CustomGameArrangementSnapshotVO struct {
+	Team1         []CustomGameParticipantVO        `json:"team1"`
+	Team2         []CustomGameParticipantVO        `json:"team2"`
+	Weights       CustomGameConfigurationWeightsVO `json:"weights"`
+	FairnessModel string                           `json:"fairnessModel"`
+}
+
+type CustomGameRevisionVO struct {
+	Id        string                          `json:"id"`
+	Seq       int                             `json:"seq"`
+	ActorUid  *string                         `json:"actorUid"`
+	Operation string                          `json:"operation"`
+	Before    CustomGameArrangementSnapshotVO `json:"before"`
+	After     CustomGameArrangementSnapshotVO `json:"after"`
+	Undone    bool                            `json:"undone"`
+	CreatedAt time.Time                       `json:"createdAt"`
+}
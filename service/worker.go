@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Worker keeps state of background loop (data explorer, statistics repositories, ...)
+// and lets admin pause, resume or trigger immediate run of it.
+
+const (
+	WorkerStatusRunning = "running"
+	WorkerStatusIdle    = "idle"
+	WorkerStatusPaused  = "paused"
+)
+
+type WorkerState struct {
+	Name                string     `json:"name"`
+	Status              string     `json:"status"`
+	LastRunStartedAt    *time.Time `json:"lastRunStartedAt"`
+	LastRunEndedAt      *time.Time `json:"lastRunEndedAt"`
+	LastRunDurationMs   int64      `json:"lastRunDurationMs"`
+	LastError           *string    `json:"lastError"`
+	LastItemsProcessed  int        `json:"lastItemsProcessed"`
+	TotalItemsProcessed int64      `json:"totalItemsProcessed"`
+	TotalRuns           int64      `json:"totalRuns"`
+}
+
+type Worker struct {
+	mutex   sync.Mutex
+	state   WorkerState
+	paused  bool
+	running int // count of concurrent runs
+
+	trigger chan struct{}
+	resumed chan struct{} // closed on resume (wakes every waiting loop)
+}
+
+var (
+	workerRegistry      = make(map[string]*Worker)
+	workerRegistryMutex sync.Mutex
+)
+
+// NewWorker creates worker and registers it with given name
+func NewWorker(name string) *Worker {
+	w := &Worker{
+		state:   WorkerState{Name: name, Status: WorkerStatusIdle},
+		trigger: make(chan struct{}, 1),
+	}
+
+	workerRegistryMutex.Lock()
+	defer workerRegistryMutex.Unlock()
+	workerRegistry[name] = w
+	return w
+}
+
+func GetWorker(name string) (*Worker, bool) {
+	workerRegistryMutex.Lock()
+	defer workerRegistryMutex.Unlock()
+	w, exists := workerRegistry[name]
+	return w, exists
+}
+
+func GetWorkerStates() []WorkerState {
+	workerRegistryMutex.Lock()
+	workers := make([]*Worker, 0, len(workerRegistry))
+	for _, w := range workerRegistry {
+		workers = append(workers, w)
+	}
+	workerRegistryMutex.Unlock()
+
+	states := make([]WorkerState, 0, len(workers))
+	for _, w := range workers {
+		states = append(states, w.State())
+	}
+	sort.SliceStable(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+	return states
+}
+
+func (w *Worker) State() WorkerState {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	state := w.state
+	if w.running > 0 {
+		state.Status = WorkerStatusRunning
+	} else if w.paused {
+		state.Status = WorkerStatusPaused
+	} else {
+		state.Status = WorkerStatusIdle
+	}
+	return state
+}
+
+// Run runs job once and records its result. job returns count of processed items.
+func (w *Worker) Run(job func() (int, error)) error {
+	startedAt := time.Now()
+	w.mutex.Lock()
+	w.running++
+	w.state.LastRunStartedAt = &startedAt
+	w.mutex.Unlock()
+
+	items, err := job()
+
+	endedAt := time.Now()
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.running--
+	w.state.LastRunEndedAt = &endedAt
+	w.state.LastRunDurationMs = endedAt.Sub(startedAt).Milliseconds()
+	w.state.LastItemsProcessed = items
+	w.state.TotalItemsProcessed += int64(items)
+	w.state.TotalRuns++
+	if err != nil {
+		errMsg := err.Error()
+		w.state.LastError = &errMsg
+	} else {
+		w.state.LastError = nil
+	}
+	return err
+}
+
+// Wait sleeps for period, keeps waiting while paused.
+// returns immediately if run is triggered (even if paused),
+// and returns false if context is done (loop should stop).
+func (w *Worker) Wait(ctx context.Context, period time.Duration) bool {
+	timer := time.NewTimer(period)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-w.trigger:
+		return true
+	case <-timer.C:
+	}
+
+	w.mutex.Lock()
+	paused, resumed := w.paused, w.resumed
+	w.mutex.Unlock()
+	if !paused {
+		return true
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-w.trigger:
+	case <-resumed:
+	}
+	return true
+}
+
+func (w *Worker) IsPaused() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.paused
+}
+
+func (w *Worker) Pause() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if !w.paused {
+		w.paused = true
+		w.resumed = make(chan struct{})
+	}
+}
+
+func (w *Worker) Resume() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.paused {
+		w.paused = false
+		close(w.resumed)
+	}
+}
+
+// Trigger wakes up waiting loop to run immediately
+func (w *Worker) Trigger() {
+	select {
+	case w.trigger <- struct{}{}:
+	default:
+	}
+}
@@ -1,8 +1,7 @@
 package api
 
 import (
-	"encoding/json"
-	"team.gg-server/libs/http"
+	"context"
 	"team.gg-server/third_party/riot"
 )
 
@@ -12,42 +11,22 @@ type AccountByRiotIdDto struct {
 	TagLine  string `json:"tagLine"`
 }
 
-func GetAccountByRiotId(gameName, tagLine string) (*AccountByRiotIdDto, int, error) {
-	riot.UpdateRiotApiCalls()
-	resp, err := http.Get(http.GetRequest{
-		Url: riot.CreateUrl(riot.RegionAsia, "/riot/account/v1/accounts/by-riot-id/"+riot.Encode(gameName)+"/"+riot.Encode(tagLine)),
-	})
-	if err != nil {
-		return nil, 0, err
-	}
-	if !resp.Success {
-		return nil, resp.StatusCode, resp.Err
-	}
-
+func GetAccountByRiotId(ctx context.Context, platform, gameName, tagLine string) (*AccountByRiotIdDto, error) {
+	region := riot.GetAccountRegion(platform)
+	url := riot.CreateUrl(region, "/riot/account/v1/accounts/by-riot-id/"+riot.Encode(gameName)+"/"+riot.Encode(tagLine))
 	var account AccountByRiotIdDto
-	if err := json.Unmarshal(resp.Body, &account); err != nil {
-		return nil, resp.StatusCode, err
+	if err := riot.GetJson(ctx, region, "/riot/account/v1/accounts/by-riot-id", url, &account); err != nil {
+		return nil, err
 	}
-
-	return &account, resp.StatusCode, nil
+	return &account, nil
 }
 
-func GetAccountByPuuid(puuid string) (*AccountByRiotIdDto, int, error) {
-	riot.UpdateRiotApiCalls()
-	resp, err := http.Get(http.GetRequest{
-		Url: riot.CreateUrl(riot.RegionAsia, "/riot/account/v1/accounts/by-puuid/"+puuid),
-	})
-	if err != nil {
-		return nil, 0, err
-	}
-	if !resp.Success {
-		return nil, resp.StatusCode, resp.Err
-	}
-
+func GetAccountByPuuid(ctx context.Context, platform, puuid string) (*AccountByRiotIdDto, error) {
+	region := riot.GetAccountRegion(platform)
+	url := riot.CreateUrl(region, "/riot/account/v1/accounts/by-puuid/"+puuid)
 	var account AccountByRiotIdDto
-	if err := json.Unmarshal(resp.Body, &account); err != nil {
-		return nil, resp.StatusCode, err
+	if err := riot.GetJson(ctx, region, "/riot/account/v1/accounts/by-puuid", url, &account); err != nil {
+		return nil, err
 	}
-
-	return &account, resp.StatusCode, nil
+	return &account, nil
 }
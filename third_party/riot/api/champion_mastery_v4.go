@@ -1,8 +1,7 @@
 package api
 
 import (
-	"encoding/json"
-	"team.gg-server/libs/http"
+	"context"
 	"team.gg-server/third_party/riot"
 )
 
@@ -21,22 +20,11 @@ type MasteryItemDto struct {
 
 type MasteryDto []MasteryItemDto
 
-func GetMasteryByPuuid(puuid string) (*MasteryDto, error) {
-	riot.UpdateRiotApiCalls()
-	resp, err := http.Get(http.GetRequest{
-		Url: riot.CreateUrl(riot.RegionKr, "/lol/champion-mastery/v4/champion-masteries/by-puuid/"+puuid),
-	})
-	if err != nil {
-		return nil, err
-	}
-	if !resp.Success {
-		return nil, resp.Err
-	}
-
+func GetMasteryByPuuid(ctx context.Context, platform, puuid string) (*MasteryDto, error) {
+	url := riot.CreateUrl(platform, "/lol/champion-mastery/v4/champion-masteries/by-puuid/"+puuid)
 	var mastery MasteryDto
-	if err := json.Unmarshal(resp.Body, &mastery); err != nil {
+	if err := riot.GetJson(ctx, platform, "/lol/champion-mastery/v4/champion-masteries/by-puuid", url, &mastery); err != nil {
 		return nil, err
 	}
-
 	return &mastery, nil
 }
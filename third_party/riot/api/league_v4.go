@@ -1,14 +1,14 @@
 package api
 
 import (
-	"encoding/json"
-	"team.gg-server/libs/http"
+	"context"
 	"team.gg-server/third_party/riot"
 )
 
 type LeagueItemDto struct {
 	LeagueId     string `json:"leagueId"`
 	SummonerId   string `json:"summonerId"`
+	Puuid        string `json:"puuid"`
 	SummonerName string `json:"summonerName"`
 	QueueType    string `json:"queueType"`
 	Tier         string `json:"tier"`
@@ -30,22 +30,58 @@ type LeagueItemDto struct {
 
 type LeagueDto []LeagueItemDto
 
-func GetLeaguesBySummonerId(summonerId string) (*LeagueDto, error) {
-	riot.UpdateRiotApiCalls()
-	resp, err := http.Get(http.GetRequest{
-		Url: riot.CreateUrl(riot.RegionKr, "/lol/league/v4/entries/by-summoner/"+summonerId),
-	})
-	if err != nil {
+func GetLeaguesBySummonerId(ctx context.Context, platform, summonerId string) (*LeagueDto, error) {
+	url := riot.CreateUrl(platform, "/lol/league/v4/entries/by-summoner/"+summonerId)
+	var league LeagueDto
+	if err := riot.GetJson(ctx, platform, "/lol/league/v4/entries/by-summoner", url, &league); err != nil {
 		return nil, err
 	}
-	if !resp.Success {
-		return nil, resp.Err
+	return &league, nil
+}
+
+type LeagueListDto struct {
+	LeagueId string          `json:"leagueId"`
+	Tier     string          `json:"tier"`
+	Name     string          `json:"name"`
+	Queue    string          `json:"queue"`
+	Entries  []LeagueItemDto `json:"entries"`
+}
+
+func GetChallengerLeague(ctx context.Context, platform, queue string) (*LeagueListDto, error) {
+	url := riot.CreateUrl(platform, "/lol/league/v4/challengerleagues/by-queue/"+queue)
+	var league LeagueListDto
+	if err := riot.GetJson(ctx, platform, "/lol/league/v4/challengerleagues/by-queue", url, &league); err != nil {
+		return nil, err
 	}
+	return &league, nil
+}
 
-	var league LeagueDto
-	if err := json.Unmarshal(resp.Body, &league); err != nil {
+func GetGrandmasterLeague(ctx context.Context, platform, queue string) (*LeagueListDto, error) {
+	url := riot.CreateUrl(platform, "/lol/league/v4/grandmasterleagues/by-queue/"+queue)
+	var league LeagueListDto
+	if err := riot.GetJson(ctx, platform, "/lol/league/v4/grandmasterleagues/by-queue", url, &league); err != nil {
 		return nil, err
 	}
+	return &league, nil
+}
 
+func GetMasterLeague(ctx context.Context, platform, queue string) (*LeagueListDto, error) {
+	url := riot.CreateUrl(platform, "/lol/league/v4/masterleagues/by-queue/"+queue)
+	var league LeagueListDto
+	if err := riot.GetJson(ctx, platform, "/lol/league/v4/masterleagues/by-queue", url, &league); err != nil {
+		return nil, err
+	}
+	return &league, nil
+}
+
+// GetLeagueExpEntries returns entries of given tier/division (league-exp-v4), page starts from 1
+func GetLeagueExpEntries(ctx context.Context, platform, queue, tier, division string, page int) (*LeagueDto, error) {
+	url := riot.CreateUrlWithQuery(platform, "/lol/league-exp/v4/entries/"+queue+"/"+tier+"/"+division, map[string]interface{}{
+		"page": page,
+	})
+	var league LeagueDto
+	if err := riot.GetJson(ctx, platform, "/lol/league-exp/v4/entries", url, &league); err != nil {
+		return nil, err
+	}
 	return &league, nil
 }
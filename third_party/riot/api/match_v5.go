@@ -1,8 +1,7 @@
 package api
 
 import (
-	"encoding/json"
-	"team.gg-server/libs/http"
+	"context"
 	"team.gg-server/third_party/riot"
 	"time"
 )
@@ -17,8 +16,7 @@ type MatchIdsReqOption struct {
 	EndTime   *time.Time
 }
 
-func GetMatchIdsInterval(puuid string, opt *MatchIdsReqOption) (*MatchIdsDto, error) {
-	riot.UpdateRiotApiCalls()
+func GetMatchIdsInterval(ctx context.Context, platform, puuid string, opt *MatchIdsReqOption) (*MatchIdsDto, error) {
 	query := make(map[string]interface{})
 	if opt != nil {
 		if opt.StartTime != nil {
@@ -34,21 +32,12 @@ func GetMatchIdsInterval(puuid string, opt *MatchIdsReqOption) (*MatchIdsDto, er
 			query["count"] = opt.Count
 		}
 	}
-	resp, err := http.Get(http.GetRequest{
-		Url: riot.CreateUrlWithQuery(riot.RegionAsia, "/lol/match/v5/matches/by-puuid/"+puuid+"/ids", query),
-	})
-	if err != nil {
-		return nil, err
-	}
-	if !resp.Success {
-		return nil, resp.Err
-	}
-
+	region := riot.GetMatchRegion(platform)
+	url := riot.CreateUrlWithQuery(region, "/lol/match/v5/matches/by-puuid/"+puuid+"/ids", query)
 	var matches MatchIdsDto
-	if err := json.Unmarshal(resp.Body, &matches); err != nil {
+	if err := riot.GetJson(ctx, region, "/lol/match/v5/matches/by-puuid/ids", url, &matches); err != nil {
 		return nil, err
 	}
-
 	return &matches, nil
 }
 
@@ -232,22 +221,84 @@ type MatchDto struct {
 	} `json:"info"`
 }
 
-func GetMatchByMatchId(matchId string) (*MatchDto, error) {
-	riot.UpdateRiotApiCalls()
-	resp, err := http.Get(http.GetRequest{
-		Url: riot.CreateUrl(riot.RegionAsia, "/lol/match/v5/matches/"+matchId),
-	})
-	if err != nil {
+func GetMatchByMatchId(ctx context.Context, platform, matchId string) (*MatchDto, error) {
+	region := riot.GetMatchRegion(platform)
+	url := riot.CreateUrl(region, "/lol/match/v5/matches/"+matchId)
+	var match MatchDto
+	if err := riot.GetJson(ctx, region, "/lol/match/v5/matches", url, &match); err != nil {
 		return nil, err
 	}
-	if !resp.Success {
-		return nil, resp.Err
-	}
+	return &match, nil
+}
 
-	var match MatchDto
-	if err := json.Unmarshal(resp.Body, &match); err != nil {
+type MatchTimelinePositionDto struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type MatchTimelineParticipantFrameDto struct {
+	ParticipantId       int                      `json:"participantId"`
+	CurrentGold         int                      `json:"currentGold"`
+	TotalGold           int                      `json:"totalGold"`
+	GoldPerSecond       int                      `json:"goldPerSecond"`
+	Xp                  int                      `json:"xp"`
+	Level               int                      `json:"level"`
+	MinionsKilled       int                      `json:"minionsKilled"`
+	JungleMinionsKilled int                      `json:"jungleMinionsKilled"`
+	Position            MatchTimelinePositionDto `json:"position"`
+}
+
+type MatchTimelineEventDto struct {
+	Type      string `json:"type"`
+	Timestamp int64  `json:"timestamp"`
+
+	// ITEM_PURCHASED, ITEM_SOLD, ITEM_DESTROYED, ITEM_UNDO
+	ParticipantId int `json:"participantId"`
+	ItemId        int `json:"itemId"`
+	BeforeId      int `json:"beforeId"`
+	AfterId       int `json:"afterId"`
+
+	// SKILL_LEVEL_UP
+	SkillSlot   int    `json:"skillSlot"`
+	LevelUpType string `json:"levelUpType"`
+
+	// CHAMPION_KILL
+	KillerId                int                       `json:"killerId"`
+	VictimId                int                       `json:"victimId"`
+	AssistingParticipantIds []int                     `json:"assistingParticipantIds"`
+	Bounty                  int                       `json:"bounty"`
+	ShutdownBounty          int                       `json:"shutdownBounty"`
+	Position                *MatchTimelinePositionDto `json:"position"`
+}
+
+type MatchTimelineFrameDto struct {
+	Timestamp         int64                                       `json:"timestamp"`
+	Events            []MatchTimelineEventDto                     `json:"events"`
+	ParticipantFrames map[string]MatchTimelineParticipantFrameDto `json:"participantFrames"`
+}
+
+type MatchTimelineDto struct {
+	Metadata struct {
+		DataVersion  string   `json:"dataVersion"`
+		MatchId      string   `json:"matchId"`
+		Participants []string `json:"participants"`
+	} `json:"metadata"`
+	Info struct {
+		FrameInterval int64                   `json:"frameInterval"`
+		Frames        []MatchTimelineFrameDto `json:"frames"`
+		Participants  []struct {
+			ParticipantId int    `json:"participantId"`
+			Puuid         string `json:"puuid"`
+		} `json:"participants"`
+	} `json:"info"`
+}
+
+func GetMatchTimelineByMatchId(ctx context.Context, platform, matchId string) (*MatchTimelineDto, error) {
+	region := riot.GetMatchRegion(platform)
+	url := riot.CreateUrl(region, "/lol/match/v5/matches/"+matchId+"/timeline")
+	var timeline MatchTimelineDto
+	if err := riot.GetJson(ctx, region, "/lol/match/v5/matches/timeline", url, &timeline); err != nil {
 		return nil, err
 	}
-
-	return &match, nil
+	return &timeline, nil
 }
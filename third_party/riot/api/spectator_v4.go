@@ -1,8 +1,7 @@
 package api
 
 import (
-	"encoding/json"
-	"team.gg-server/libs/http"
+	"context"
 	"team.gg-server/third_party/riot"
 )
 
@@ -46,22 +45,11 @@ type SpectatorDto struct {
 	Participants []SpectatorParticipantDto `json:"participants"`
 }
 
-func GetSpectatorInfo(summonerId string) (*SpectatorDto, int, error) {
-	riot.UpdateRiotApiCalls()
-	resp, err := http.Get(http.GetRequest{
-		Url: riot.CreateUrl(riot.RegionKr, "/lol/spectator/v4/active-games/by-summoner/"+summonerId),
-	})
-	if err != nil {
-		return nil, 0, err
-	}
-	if !resp.Success {
-		return nil, resp.StatusCode, resp.Err
-	}
-
+func GetSpectatorInfo(ctx context.Context, platform, summonerId string) (*SpectatorDto, error) {
+	url := riot.CreateUrl(platform, "/lol/spectator/v4/active-games/by-summoner/"+summonerId)
 	var spectator SpectatorDto
-	if err := json.Unmarshal(resp.Body, &spectator); err != nil {
-		return nil, resp.StatusCode, err
+	if err := riot.GetJson(ctx, platform, "/lol/spectator/v4/active-games/by-summoner", url, &spectator); err != nil {
+		return nil, err
 	}
-
-	return &spectator, resp.StatusCode, nil
+	return &spectator, nil
 }
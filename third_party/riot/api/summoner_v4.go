@@ -1,8 +1,7 @@
 package api
 
 import (
-	"encoding/json"
-	"team.gg-server/libs/http"
+	"context"
 	"team.gg-server/third_party/riot"
 )
 
@@ -16,41 +15,29 @@ type SummonerDto struct {
 	SummonerLevel int64  `json:"summonerLevel"`
 }
 
-func GetSummonerByName(name string) (*SummonerDto, int, error) {
-	riot.UpdateRiotApiCalls()
-	resp, err := http.Get(http.GetRequest{
-		Url: riot.CreateUrl(riot.RegionKr, "/lol/summoner/v4/summoners/by-name/"+name),
-	})
-	if err != nil {
-		return nil, 0, err
-	}
-	if !resp.Success {
-		return nil, resp.StatusCode, resp.Err
-	}
-
+func GetSummonerByName(ctx context.Context, platform, name string) (*SummonerDto, error) {
+	url := riot.CreateUrl(platform, "/lol/summoner/v4/summoners/by-name/"+name)
 	var summoner SummonerDto
-	if err := json.Unmarshal(resp.Body, &summoner); err != nil {
-		return nil, resp.StatusCode, err
+	if err := riot.GetJson(ctx, platform, "/lol/summoner/v4/summoners/by-name", url, &summoner); err != nil {
+		return nil, err
 	}
-	return &summoner, resp.StatusCode, nil
+	return &summoner, nil
 }
 
-func GetSummonerByPuuid(puuid string) (*SummonerDto, int, error) {
-	riot.UpdateRiotApiCalls()
-	resp, err := http.Get(http.GetRequest{
-		Url: riot.CreateUrl(riot.RegionKr, "/lol/summoner/v4/summoners/by-puuid/"+puuid),
-	})
-	if err != nil {
-		return nil, 0, err
-	}
-	if !resp.Success {
-		return nil, resp.StatusCode, resp.Err
+func GetSummonerByPuuid(ctx context.Context, platform, puuid string) (*SummonerDto, error) {
+	url := riot.CreateUrl(platform, "/lol/summoner/v4/summoners/by-puuid/"+puuid)
+	var summoner SummonerDto
+	if err := riot.GetJson(ctx, platform, "/lol/summoner/v4/summoners/by-puuid", url, &summoner); err != nil {
+		return nil, err
 	}
+	return &summoner, nil
+}
 
+func GetSummonerBySummonerId(ctx context.Context, platform, summonerId string) (*SummonerDto, error) {
+	url := riot.CreateUrl(platform, "/lol/summoner/v4/summoners/"+summonerId)
 	var summoner SummonerDto
-	if err := json.Unmarshal(resp.Body, &summoner); err != nil {
-		return nil, resp.StatusCode, err
+	if err := riot.GetJson(ctx, platform, "/lol/summoner/v4/summoners", url, &summoner); err != nil {
+		return nil, err
 	}
-
-	return &summoner, resp.StatusCode, nil
+	return &summoner, nil
 }
@@ -0,0 +1,126 @@
+package riot
+
+import (
+	"context"
+	"encoding/json"
+	log "github.com/shyunku-libraries/go-logger"
+	"math/rand"
+	nethttp "net/http"
+	"strconv"
+	"team.gg-server/libs/http"
+	"time"
+)
+
+type RetryPolicy struct {
+	// max retry count for transient failures (429, 5xx, network errors)
+	MaxRetries int
+	// backoff of first retry, doubled for each attempt
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   8 * time.Second,
+}
+
+var RateLimiter = NewRateLimitManager()
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << attempt
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	// add jitter to prevent retrying in lockstep
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// Get requests riot api through the shared rate limiter.
+// method is the route identifier used for per-method buckets (e.g. "/lol/summoner/v4/summoners/by-puuid")
+// requests rejected by rate limit (429) are retried after Retry-After (up to max retries, within deadline of context),
+// and transient failures are retried with exponential backoff. failures are returned as *ApiError.
+func Get(ctx context.Context, region, method, url string) (http.Response, error) {
+	policy := DefaultRetryPolicy
+	for attempt := 0; ; attempt++ {
+		if err := RateLimiter.Acquire(ctx, region, method); err != nil {
+			return http.Response{}, err
+		}
+
+		UpdateRiotApiCalls()
+		resp, err := http.Get(http.GetRequest{Url: url})
+
+		var apiErr *ApiError
+		if err != nil {
+			apiErr = newTransportError(method, err)
+		} else {
+			RateLimiter.Update(region, method, resp.StatusCode, resp.Header)
+			if resp.Success {
+				return resp, nil
+			}
+			apiErr = newResponseError(method, resp)
+		}
+
+		if !IsTransient(apiErr) {
+			return resp, apiErr
+		}
+
+		if attempt >= policy.MaxRetries {
+			return resp, apiErr
+		}
+
+		// rate limited requests wait until Retry-After in Acquire (bucket is blocked by limiter), others back off.
+		// rate limited request is retried only if Retry-After is within deadline of context.
+		if apiErr.Kind == ErrRateLimited {
+			retryAfter := getRetryAfter(resp.Header)
+			if !isWithinDeadline(ctx, retryAfter) {
+				return resp, apiErr
+			}
+			log.Warnf("riot api rate limited (%s %s), retrying after %s", region, method, retryAfter)
+			continue
+		}
+
+		delay := policy.backoff(attempt)
+		log.Warnf("riot api failed (%s %s): %v, retrying in %s", region, method, apiErr, delay)
+		if err := sleepContext(ctx, delay); err != nil {
+			return resp, err
+		}
+	}
+}
+
+// getRetryAfter returns wait duration of Retry-After header (1 second if missing, as limiter does)
+func getRetryAfter(header nethttp.Header) time.Duration {
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return time.Second
+}
+
+// isWithinDeadline reports whether context is still alive after given duration
+func isWithinDeadline(ctx context.Context, wait time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Now().Add(wait).Before(deadline)
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// GetJson requests riot api and decodes response body into v
+func GetJson(ctx context.Context, region, method, url string, v interface{}) error {
+	resp, err := Get(ctx, region, method, url)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(resp.Body, v); err != nil {
+		return newDecodeError(method, err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package riot
+
+import (
+	"errors"
+	"fmt"
+	"team.gg-server/libs/http"
+)
+
+var (
+	ErrNotFound    = errors.New("riot api: not found")
+	ErrRateLimited = errors.New("riot api: rate limited")
+	ErrForbidden   = errors.New("riot api: forbidden or expired api key")
+	ErrUpstream    = errors.New("riot api: upstream error")
+	ErrBadRequest  = errors.New("riot api: bad request")
+	ErrDecode      = errors.New("riot api: decode failure")
+)
+
+// ApiError is returned by every riot api call which didn't succeed.
+// use errors.Is(err, ErrXxx) to check kind of error.
+type ApiError struct {
+	Kind       error
+	Method     string
+	StatusCode int
+	Message    string
+	Err        error
+}
+
+func (e *ApiError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s (%s): %v", e.Kind, e.Method, e.Err)
+	}
+	return fmt.Sprintf("%s (%s, status %d): %s", e.Kind, e.Method, e.StatusCode, e.Message)
+}
+
+func (e *ApiError) Unwrap() error {
+	return e.Kind
+}
+
+func newResponseError(method string, resp http.Response) *ApiError {
+	apiErr := &ApiError{
+		Method:     method,
+		StatusCode: resp.StatusCode,
+	}
+	if resp.Err != nil {
+		apiErr.Message = resp.Err.Error()
+	}
+
+	switch {
+	case resp.StatusCode == 404:
+		apiErr.Kind = ErrNotFound
+	case resp.StatusCode == 429:
+		apiErr.Kind = ErrRateLimited
+	case resp.StatusCode == 401 || resp.StatusCode == 403:
+		apiErr.Kind = ErrForbidden
+	case resp.StatusCode >= 500:
+		apiErr.Kind = ErrUpstream
+	default:
+		apiErr.Kind = ErrBadRequest
+	}
+	return apiErr
+}
+
+func newTransportError(method string, err error) *ApiError {
+	return &ApiError{Kind: ErrUpstream, Method: method, Err: err}
+}
+
+func newDecodeError(method string, err error) *ApiError {
+	return &ApiError{Kind: ErrDecode, Method: method, Err: err}
+}
+
+// IsTransient reports whether the request may succeed if retried later
+func IsTransient(err error) bool {
+	return errors.Is(err, ErrUpstream) || errors.Is(err, ErrRateLimited)
+}
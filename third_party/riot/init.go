@@ -2,6 +2,7 @@ package riot
 
 import (
 	"os"
+	"strconv"
 	"time"
 )
 
@@ -15,6 +16,10 @@ func Init() {
 	apiKey = os.Getenv("RIOT_API_KEY")
 	ApiCalls = 0
 	LastApiCallTime = time.Now().Add(-24 * time.Hour)
+
+	if maxRetries, err := strconv.Atoi(os.Getenv("RIOT_API_MAX_RETRIES")); err == nil && maxRetries >= 0 {
+		DefaultRetryPolicy.MaxRetries = maxRetries
+	}
 }
 
 func UpdateRiotApiCalls() {
@@ -0,0 +1,293 @@
+package riot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Priority int
+
+const (
+	// PriorityUser is used for requests triggered by user-facing lookups
+	PriorityUser Priority = iota
+	// PriorityBackground is used for crawling jobs (data explorer, etc.)
+	PriorityBackground
+)
+
+const (
+	// default application limit of development key, used until riot tells us the real one
+	DefaultAppRateLimit = "20:1,100:120"
+
+	// ratio of each bucket reserved for user-facing requests
+	BackgroundReserveRatio = 0.2
+
+	// polling interval for background requests waiting behind user requests
+	backgroundPollInterval = 50 * time.Millisecond
+)
+
+type priorityContextKey struct{}
+
+// WithPriority returns a context whose riot api calls are scheduled with given priority
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, priority)
+}
+
+func PriorityOf(ctx context.Context) Priority {
+	if priority, ok := ctx.Value(priorityContextKey{}).(Priority); ok {
+		return priority
+	}
+	return PriorityUser
+}
+
+// rateLimitBucket is a sliding window bucket (e.g. 20 requests per 1 second)
+type rateLimitBucket struct {
+	limit   int
+	window  time.Duration
+	history []time.Time
+}
+
+func (b *rateLimitBucket) prune(now time.Time) {
+	i := 0
+	for i < len(b.history) && now.Sub(b.history[i]) >= b.window {
+		i++
+	}
+	b.history = b.history[i:]
+}
+
+// waitTime returns how long to wait until a request can be made using up to capacity of this bucket
+func (b *rateLimitBucket) waitTime(now time.Time, capacity int) time.Duration {
+	b.prune(now)
+	if capacity < 1 {
+		capacity = 1
+	}
+	if len(b.history) < capacity {
+		return 0
+	}
+	return b.history[len(b.history)-capacity].Add(b.window).Sub(now)
+}
+
+// rateLimiter is a set of buckets sharing one rate limit header (app or method)
+type rateLimiter struct {
+	spec         string
+	buckets      []*rateLimitBucket
+	blockedUntil time.Time
+}
+
+func newRateLimiter(spec string) *rateLimiter {
+	limiter := &rateLimiter{}
+	limiter.configure(spec)
+	return limiter
+}
+
+// configure parses header value like "20:1,100:120" (requests:seconds)
+func (l *rateLimiter) configure(spec string) {
+	if spec == "" || spec == l.spec {
+		return
+	}
+
+	buckets := make([]*rateLimitBucket, 0)
+	for _, pair := range strings.Split(spec, ",") {
+		limit, window, ok := parseRateLimitPair(pair)
+		if !ok {
+			continue
+		}
+
+		bucket := &rateLimitBucket{limit: limit, window: window, history: make([]time.Time, 0)}
+		// keep history of the previous bucket with same window
+		for _, old := range l.buckets {
+			if old.window == window {
+				bucket.history = old.history
+			}
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	l.spec = spec
+	l.buckets = buckets
+}
+
+// synchronize catches up request counts reported by riot (e.g. calls made before restart)
+func (l *rateLimiter) synchronize(now time.Time, counts string) {
+	if counts == "" {
+		return
+	}
+	for _, pair := range strings.Split(counts, ",") {
+		count, window, ok := parseRateLimitPair(pair)
+		if !ok {
+			continue
+		}
+		for _, bucket := range l.buckets {
+			if bucket.window != window {
+				continue
+			}
+			bucket.prune(now)
+			for len(bucket.history) < count {
+				bucket.history = append(bucket.history, now)
+			}
+		}
+	}
+}
+
+func (l *rateLimiter) waitTime(now time.Time, priority Priority) time.Duration {
+	var wait time.Duration
+	if l.blockedUntil.After(now) {
+		wait = l.blockedUntil.Sub(now)
+	}
+	for _, bucket := range l.buckets {
+		capacity := bucket.limit
+		if priority == PriorityBackground {
+			capacity -= int(float64(bucket.limit)*BackgroundReserveRatio + 0.5)
+		}
+		if w := bucket.waitTime(now, capacity); w > wait {
+			wait = w
+		}
+	}
+	return wait
+}
+
+func (l *rateLimiter) record(now time.Time) {
+	for _, bucket := range l.buckets {
+		bucket.history = append(bucket.history, now)
+	}
+}
+
+func parseRateLimitPair(pair string) (int, time.Duration, bool) {
+	tokens := strings.Split(strings.TrimSpace(pair), ":")
+	if len(tokens) != 2 {
+		return 0, 0, false
+	}
+	count, err := strconv.Atoi(tokens[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	seconds, err := strconv.Atoi(tokens[1])
+	if err != nil || seconds <= 0 {
+		return 0, 0, false
+	}
+	return count, time.Duration(seconds) * time.Second, true
+}
+
+// RateLimitManager schedules riot api calls with per-app (per routing value) and per-method buckets
+type RateLimitManager struct {
+	mu           sync.Mutex
+	apps         map[string]*rateLimiter
+	methods      map[string]*rateLimiter
+	waitingUsers int
+}
+
+func NewRateLimitManager() *RateLimitManager {
+	return &RateLimitManager{
+		apps:    make(map[string]*rateLimiter),
+		methods: make(map[string]*rateLimiter),
+	}
+}
+
+func (m *RateLimitManager) app(region string) *rateLimiter {
+	limiter, ok := m.apps[region]
+	if !ok {
+		limiter = newRateLimiter(DefaultAppRateLimit)
+		m.apps[region] = limiter
+	}
+	return limiter
+}
+
+func (m *RateLimitManager) method(region, method string) *rateLimiter {
+	key := region + ":" + method
+	limiter, ok := m.methods[key]
+	if !ok {
+		limiter = newRateLimiter("")
+		m.methods[key] = limiter
+	}
+	return limiter
+}
+
+// Acquire blocks until a request to given method can be made, or ctx is done
+func (m *RateLimitManager) Acquire(ctx context.Context, region, method string) error {
+	priority := PriorityOf(ctx)
+
+	m.mu.Lock()
+	if priority == PriorityUser {
+		m.waitingUsers++
+	}
+	release := func() {
+		if priority == PriorityUser {
+			m.waitingUsers--
+		}
+		m.mu.Unlock()
+	}
+
+	for {
+		now := time.Now()
+		var wait time.Duration
+		if priority == PriorityBackground && m.waitingUsers > 0 {
+			// user requests go first
+			wait = backgroundPollInterval
+		} else {
+			app, method := m.app(region), m.method(region, method)
+			wait = app.waitTime(now, priority)
+			if w := method.waitTime(now, priority); w > wait {
+				wait = w
+			}
+			if wait <= 0 {
+				app.record(now)
+				method.record(now)
+				release()
+				return nil
+			}
+		}
+		m.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			m.mu.Lock()
+			release()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		m.mu.Lock()
+	}
+}
+
+// Update applies rate limit headers of riot response
+func (m *RateLimitManager) Update(region, method string, statusCode int, header map[string][]string) {
+	get := func(key string) string {
+		if values, ok := header[key]; ok && len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	app, methodLimiter := m.app(region), m.method(region, method)
+	app.configure(get("X-App-Rate-Limit"))
+	app.synchronize(now, get("X-App-Rate-Limit-Count"))
+	methodLimiter.configure(get("X-Method-Rate-Limit"))
+	methodLimiter.synchronize(now, get("X-Method-Rate-Limit-Count"))
+
+	if statusCode != 429 {
+		return
+	}
+
+	retryAfter := time.Second
+	if seconds, err := strconv.Atoi(get("Retry-After")); err == nil && seconds > 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	blockedUntil := now.Add(retryAfter)
+
+	// service limits (no type header) are handled as method limits
+	target := methodLimiter
+	if get("X-Rate-Limit-Type") == "application" {
+		target = app
+	}
+	if blockedUntil.After(target.blockedUntil) {
+		target.blockedUntil = blockedUntil
+	}
+}
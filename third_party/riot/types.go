@@ -1,9 +1,113 @@
 package riot
 
+import "strings"
+
 const (
-	// old
-	RegionKr = "kr"
+	// platform routing values (summoner, league, mastery, spectator)
+	PlatformKr   = "kr"
+	PlatformJp1  = "jp1"
+	PlatformNa1  = "na1"
+	PlatformBr1  = "br1"
+	PlatformLa1  = "la1"
+	PlatformLa2  = "la2"
+	PlatformEuw1 = "euw1"
+	PlatformEun1 = "eun1"
+	PlatformTr1  = "tr1"
+	PlatformRu   = "ru"
+	PlatformOc1  = "oc1"
+	PlatformPh2  = "ph2"
+	PlatformSg2  = "sg2"
+	PlatformTh2  = "th2"
+	PlatformTw2  = "tw2"
+	PlatformVn2  = "vn2"
+
+	// regional routing values (account, match)
+	RegionAsia     = "asia"
+	RegionAmericas = "americas"
+	RegionEurope   = "europe"
+	RegionSea      = "sea"
 
-	// new
-	RegionAsia = "asia"
+	DefaultPlatform = PlatformKr
 )
+
+type platformRoute struct {
+	// regional cluster of match-v5
+	Region string
+	// default tag line of riot id
+	TagLine string
+}
+
+var platformRoutes = map[string]platformRoute{
+	PlatformKr:   {Region: RegionAsia, TagLine: "KR1"},
+	PlatformJp1:  {Region: RegionAsia, TagLine: "JP1"},
+	PlatformNa1:  {Region: RegionAmericas, TagLine: "NA1"},
+	PlatformBr1:  {Region: RegionAmericas, TagLine: "BR1"},
+	PlatformLa1:  {Region: RegionAmericas, TagLine: "LAN"},
+	PlatformLa2:  {Region: RegionAmericas, TagLine: "LAS"},
+	PlatformEuw1: {Region: RegionEurope, TagLine: "EUW"},
+	PlatformEun1: {Region: RegionEurope, TagLine: "EUNE"},
+	PlatformTr1:  {Region: RegionEurope, TagLine: "TR1"},
+	PlatformRu:   {Region: RegionEurope, TagLine: "RU1"},
+	PlatformOc1:  {Region: RegionSea, TagLine: "OCE"},
+	PlatformPh2:  {Region: RegionSea, TagLine: "PH2"},
+	PlatformSg2:  {Region: RegionSea, TagLine: "SG2"},
+	PlatformTh2:  {Region: RegionSea, TagLine: "TH2"},
+	PlatformTw2:  {Region: RegionSea, TagLine: "TW2"},
+	PlatformVn2:  {Region: RegionSea, TagLine: "VN2"},
+}
+
+// NormalizePlatform converts platform id (e.g. "KR", "EUW1") into routing value
+func NormalizePlatform(platform string) string {
+	return strings.ToLower(strings.TrimSpace(platform))
+}
+
+func IsSupportedPlatform(platform string) bool {
+	_, ok := platformRoutes[NormalizePlatform(platform)]
+	return ok
+}
+
+func GetSupportedPlatforms() []string {
+	platforms := make([]string, 0, len(platformRoutes))
+	for platform := range platformRoutes {
+		platforms = append(platforms, platform)
+	}
+	return platforms
+}
+
+// GetMatchRegion returns regional routing value for match-v5 of given platform
+func GetMatchRegion(platform string) string {
+	if route, ok := platformRoutes[NormalizePlatform(platform)]; ok {
+		return route.Region
+	}
+	return platformRoutes[DefaultPlatform].Region
+}
+
+// GetAccountRegion returns regional routing value for account-v1 of given platform.
+// account-v1 is not served by sea cluster, so asia is used instead.
+func GetAccountRegion(platform string) string {
+	region := GetMatchRegion(platform)
+	if region == RegionSea {
+		return RegionAsia
+	}
+	return region
+}
+
+func GetDefaultTagLine(platform string) string {
+	if route, ok := platformRoutes[NormalizePlatform(platform)]; ok {
+		return route.TagLine
+	}
+	return platformRoutes[DefaultPlatform].TagLine
+}
+
+// GetPlatformByMatchId extracts platform from match id (e.g. "KR_1234" -> "kr")
+func GetPlatformByMatchId(matchId string) (string, bool) {
+	index := strings.Index(matchId, "_")
+	if index <= 0 {
+		return "", false
+	}
+	platform := NormalizePlatform(matchId[:index])
+	if !IsSupportedPlatform(platform) {
+		return "", false
+	}
+	return platform, true
+}
@@ -18,6 +18,22 @@ const (
 	DataExplorerLoopPeriodDev    = 5 * time.Minute
 	DataExplorerLoadMatchesCount = 3
 
+	DataExplorerLadderRatio             = 0.5 // ratio of ladder seeding (others are random seeding)
+	DataExplorerTierCountRefreshPeriod  = 10 * time.Minute
+	DataExplorerLadderExhaustedCooldown = 6 * time.Hour
+
+	DataExplorerWorkerCount          = 2
+	DataExplorerSeedPeriod           = 10 * time.Second
+	DataExplorerFrontierLowWatermark = 50 // seed frontier when due summoners are less than this
+	DataExplorerCrawlLease           = 5 * time.Minute
+	DataExplorerRetryBaseDelay       = 1 * time.Minute
+	DataExplorerRetryMaxDelay        = 24 * time.Hour
+	DataExplorerStalePeriod          = 7 * 24 * time.Hour // re-crawl summoners after this
+	DataExplorerStaleCheckPeriod     = 10 * time.Minute
+	DataExplorerStaleEnqueueCount    = 100
+	// timelines of matches saved by user-facing requests, fetched after each crawl
+	DataExplorerTimelineBackfillCount = 3
+
 	SummonerRankingRevisionPeriod = 7 * 24 * time.Hour
 
 	PositionTop     = "TOP"
@@ -42,6 +58,79 @@ const (
 	WeightAdcInfluence     = 0.21
 	WeightSupportInfluence = 1 - WeightTopInfluence - WeightJungleInfluence - WeightMidInfluence - WeightAdcInfluence
 
+	CustomGameOptimizeResultCount    = 5 // count of top configurations returned by optimizer
+	CustomGameOptimizeMaxResultCount = 20
+	// limit of player selections searched when there are more candidates than seats (sampled beyond this)
+	CustomGameOptimizeMaxSelectionCount = 30
+	// count of lobby splits tried when splitting candidates into 2 lobbies
+	CustomGameOptimizeLobbySplitCount = 8
+	// finished job (and its result) is kept for this duration
+	CustomGameOptimizeJobRetention = 1 * time.Hour
+
+	CustomGameConstraintTogether          = "TOGETHER"           // 2 players on the same team
+	CustomGameConstraintApart             = "APART"              // 2 players on different teams
+	CustomGameConstraintLockedTeam        = "LOCKED_TEAM"        // player on the given team
+	CustomGameConstraintLockedPosition    = "LOCKED_POSITION"    // player on the given position
+	CustomGameConstraintForbiddenPosition = "FORBIDDEN_POSITION" // player not on the given position
+
+	CustomGameRatingPositionAll       = "ALL" // position of overall in-house rating
+	CustomGameRatingInitialDeviation  = 200.0 // initial rating starts from representative rating point, so less uncertain than usual
+	CustomGameRatingInitialVolatility = 0.06
+	CustomGameRatingTau               = 0.5 // constrains change of volatility over time
+
+	CustomGameResultImportWindow     = 6 * time.Hour // default time window of matches searched on result import
+	CustomGameResultImportMatchCount = 20            // count of recent matches searched on result import
+
+	CustomGameRoleOwner  = "OWNER"  // manages members and invites, creator of configuration
+	CustomGameRoleEditor = "EDITOR" // edits configuration
+	CustomGameRoleViewer = "VIEWER" // views configuration only
+
+	CustomGameInviteDefaultExpiry = 24 * time.Hour
+	CustomGameInviteMaxExpiry     = 7 * 24 * time.Hour
+
+	CustomGameOperationArrange      = "ARRANGE"
+	CustomGameOperationUnarrange    = "UNARRANGE"
+	CustomGameOperationArrangeAll   = "ARRANGE_ALL"
+	CustomGameOperationUnarrangeAll = "UNARRANGE_ALL"
+	CustomGameOperationSwapTeam     = "SWAP_TEAM"
+	CustomGameOperationShuffle      = "SHUFFLE"
+	CustomGameOperationWeights      = "UPDATE_WEIGHTS"
+	CustomGameOperationOptimize     = "OPTIMIZE"
+	CustomGameOperationRestore      = "RESTORE"
+	CustomGameOperationUndo         = "UNDO"
+	CustomGameOperationRedo         = "REDO"
+	CustomGameOperationDraft        = "DRAFT"
+	CustomGameOperationFairness     = "UPDATE_FAIRNESS_MODEL"
+
+	CustomGameDraftOrderSnake       = "SNAKE"       // A B B A A B B A
+	CustomGameDraftOrderAlternating = "ALTERNATING" // A B A B A B A B
+
+	CustomGameDraftDefaultTurnSeconds = 30 // captain who doesn't pick in time gets auto pick
+	CustomGameDraftMinTurnSeconds     = 10
+	CustomGameDraftMaxTurnSeconds     = 180
+	// finished draft (and its picks) is kept for this duration
+	CustomGameDraftRetention = 1 * time.Hour
+
+	CustomGameRevisionMaxCount = 100 // revisions kept per configuration, older ones are deleted
+
+	CustomGameBalanceSwapSuggestionCount = 3 // count of swaps suggested by balance detail
+
+	CustomGamePositionFavorMatchCount    = 50 // recent summoner's rift matches to infer position favor from
+	CustomGamePositionFavorMinMatchCount = 10 // position favor is not suggested with fewer matches
+	CustomGamePositionFavorMinStatCount  = 5  // winrate & gg score of position are considered from this count of matches
+
+	CustomGameChampionPoolMatchCount      = 50     // recent summoner's rift matches to build champion pool from
+	CustomGameChampionPoolMasteryPoints   = 100000 // mastery points regarded as full proficiency
+	CustomGameChampionPoolRecentCount     = 5      // recent matches of champion on position regarded as full proficiency
+	CustomGameChampionPoolMinProficiency  = 0.1    // champions below this proficiency are not in pool
+	CustomGameChampionPoolSize            = 5      // champions listed per position (most proficient first)
+	CustomGameChampionPoolThinDepth       = 2.0    // position with shallower pool than this is penalized
+	CustomGameChampionPoolNarrowDepth     = 3.0    // teammates sharing champions are penalized if one's pool is shallower than this
+	CustomGameChampionPoolShareCacheHours = 6      // period to recount global champion picks by position
+
+	CustomGameFairnessModelDefault        = "DEFAULT"         // favor-weighted line scores & total rating points
+	CustomGameFairnessModelWinProbability = "WIN_PROBABILITY" // predicted win probability from lane rating differences
+
 	QueueTypeAll         = 0   // 전체
 	QueueTypeNormalDraft = 400 // 일반 (드래프트)
 	QueueTypeSolo        = 420 // 솔랭
@@ -52,6 +141,8 @@ const (
 	QueueTypeUrf         = 900 // 우르프
 	QueueTypePoro        = 920 // 포로왕?
 
+	GameTypeCustom = "CUSTOM_GAME"
+
 	MapTypeSummonersRift = 11
 	MapTypeHowlingAbyss  = 12
 
@@ -177,3 +268,35 @@ const (
 	PerkSlotTypeKeystone = "kKeyStone"
 	PerkSlotTypeStatMod  = "kStatMod"
 )
+
+const (
+	TimelineEventItemPurchased = "ITEM_PURCHASED"
+	TimelineEventItemUndo      = "ITEM_UNDO"
+	TimelineEventSkillLevelUp  = "SKILL_LEVEL_UP"
+	TimelineEventChampionKill  = "CHAMPION_KILL"
+
+	// lane phase differential (gold/xp/cs) is measured at these minutes
+	LaneDiffEarlyMinute = 10
+	LaneDiffLateMinute  = 15
+)
+
+const (
+	CrawlReasonLadder           = "LADDER"
+	CrawlReasonMatchParticipant = "MATCH_PARTICIPANT"
+	CrawlReasonRandom           = "RANDOM"
+	CrawlReasonStale            = "STALE"
+
+	CrawlPriorityLadder           = 50
+	CrawlPriorityMatchParticipant = 10
+	CrawlPriorityRandom           = 5
+	CrawlPriorityStale            = 0
+)
+
+const (
+	SkillLevelUpTypeNormal = "NORMAL"
+
+	SkillSlotQ = 1
+	SkillSlotW = 2
+	SkillSlotE = 3
+	SkillSlotR = 4
+)
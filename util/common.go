@@ -149,3 +149,11 @@ func MemorySizeOfArray[T any](obj []T) string {
 		return fmt.Sprintf("%.3f GB", size/1024/1024/1024)
 	}
 }
+
+// EqualPtr checks both are nil, or both point equal values
+func EqualPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}